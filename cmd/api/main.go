@@ -15,7 +15,7 @@ import (
 	"github.com/anvidev/goenv"
 )
 
-func gracefulShutdown(apiServer *http.Server, done chan bool) {
+func gracefulShutdown(apiServer *http.Server, srv *server.Server, done chan bool) {
 	// Create context that listens for the interrupt signal from the OS.
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
@@ -33,6 +33,17 @@ func gracefulShutdown(apiServer *http.Server, done chan bool) {
 		log.Printf("Server forced to shutdown with error: %v", err)
 	}
 
+	// No new requests are accepted at this point, so wait for the
+	// ingestion queue to write the collections it has already accepted
+	log.Println("Draining ingestion queue")
+	if err := srv.DrainQueue(); err != nil {
+		log.Printf("Ingestion queue was not fully drained, remaining jobs will be replayed on next start: %v", err)
+	}
+
+	if err := srv.FlushThrottled(); err != nil {
+		log.Printf("Counts of throttled requests were not written: %v", err)
+	}
+
 	log.Println("Server exiting")
 
 	// Notify the main goroutine that the shutdown is complete
@@ -46,16 +57,16 @@ func main() {
 		log.Fatalf("Error reading environment variables: %v\n", err)
 	}
 
-	server := server.NewServer(config)
+	httpServer, srv := server.NewServer(config)
 
 	// Create a done channel to signal when the shutdown is complete
 	done := make(chan bool, 1)
 
 	// Run graceful shutdown in a separate goroutine
-	go gracefulShutdown(server, done)
+	go gracefulShutdown(httpServer, srv, done)
 
 	log.Printf("Server listening on :%d\n", config.Port)
-	err = server.ListenAndServe()
+	err = httpServer.ListenAndServe()
 	if err != nil && err != http.ErrServerClosed {
 		panic(fmt.Sprintf("http server error: %s", err))
 	}
@@ -35,6 +35,8 @@ func ParseFlags(args []string) (bool, Command) {
 		ApiKeyCommand(),
 		RegisterCommand(),
 		SignInCommand(),
+		TracesCommand(),
+		MappingsCommand(),
 	}
 
 	if len(args) < 1 {
@@ -1,17 +1,30 @@
 package cli
 
 import (
+	"ObservabilityServer/internal/model"
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 )
 
 type apiKeyCommand struct {
-	fs     *flag.FlagSet
-	create bool
-	appId  int
+	fs      *flag.FlagSet
+	create  bool
+	list    bool
+	revoke  bool
+	rotate  bool
+	appId   int
+	keyId   int
+	name    string
+	scopes  string
+	expires time.Duration
+	grace   time.Duration
 }
 
 func ApiKeyCommand() Command {
@@ -20,7 +33,15 @@ func ApiKeyCommand() Command {
 	}
 
 	cmd.fs.BoolVar(&cmd.create, "create", false, "Create a new api key for app id specified with 'id'")
+	cmd.fs.BoolVar(&cmd.list, "list", false, "List the api keys of the app id specified with 'id'")
+	cmd.fs.BoolVar(&cmd.revoke, "revoke", false, "Revoke the api key specified with 'key' of the app id specified with 'id'")
+	cmd.fs.BoolVar(&cmd.rotate, "rotate", false, "Replace the api key specified with 'key' of the app id specified with 'id' with a new key")
 	cmd.fs.IntVar(&cmd.appId, "id", -1, "Id of the app the api key belongs to")
+	cmd.fs.IntVar(&cmd.keyId, "key", -1, "Id of the api key to revoke or rotate")
+	cmd.fs.StringVar(&cmd.name, "name", "", "Name of the new api key")
+	cmd.fs.StringVar(&cmd.scopes, "scopes", "", "Comma separated scopes of the new api key, e.g. 'ingest:events,ingest:traces' or 'read'. Defaults to 'ingest'")
+	cmd.fs.DurationVar(&cmd.expires, "expires", 0, "Time until the new api key expires, e.g. '720h'. The key never expires if not set")
+	cmd.fs.DurationVar(&cmd.grace, "grace", 24*time.Hour, "Time the rotated api key keeps working")
 
 	return cmd
 }
@@ -29,47 +50,163 @@ func (c *apiKeyCommand) Init(args []string) error {
 	return c.fs.Parse(args)
 }
 func (c *apiKeyCommand) Run() {
-	if !c.create || c.appId == -1 {
+	actions := 0
+	for _, set := range []bool{c.create, c.list, c.revoke, c.rotate} {
+		if set {
+			actions++
+		}
+	}
+	if actions != 1 || c.appId == -1 || ((c.revoke || c.rotate) && c.keyId == -1) {
 		fmt.Println("Malformed arguments for 'keys' command")
 		c.fs.Usage()
-	} else {
-		err := createApiKey(c.appId)
-		if err != nil {
-			fmt.Printf("Could not create apiKey: %v\n", err)
+		return
+	}
+
+	var expiresAt int64
+	if c.expires > 0 {
+		expiresAt = time.Now().Add(c.expires).UnixMilli()
+	}
+
+	var err error
+	switch {
+	case c.create:
+		var scopes []string
+		if c.scopes != "" {
+			scopes = strings.Split(c.scopes, ",")
 		}
+		err = createApiKey(c.appId, c.name, scopes, expiresAt)
+	case c.list:
+		err = listApiKeys(c.appId)
+	case c.revoke:
+		err = revokeApiKey(c.appId, c.keyId)
+	case c.rotate:
+		err = rotateApiKey(c.appId, c.keyId, c.grace, expiresAt)
+	}
+	if err != nil {
+		fmt.Printf("Could not manage api keys: %v\n", err)
 	}
 }
 func (c *apiKeyCommand) Name() string {
 	return c.fs.Name()
 }
 func (c *apiKeyCommand) Description() string {
-	return "Generate new api keys for owners"
+	return "Create, list, revoke and rotate api keys of apps"
 }
 
-func createApiKey(appId int) error {
+// doKeysRequest sends a request with the JSON body, if any, to the keys of
+// the app, and decodes the response into resBody
+func doKeysRequest(method, path string, body any, expectedStatus int, resBody any) error {
 	secret := os.Getenv("OBSERVE_CLI_SESSION")
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/app/v1/apps/%d/keys", baseUrl, appId), nil)
+
+	var reader io.Reader = http.NoBody
+	if body != nil {
+		jsonBytes, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(jsonBytes)
+	}
+
+	req, err := http.NewRequest(method, fmt.Sprintf("%s%s", baseUrl, path), reader)
 	if err != nil {
 		return err
 	}
 	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", secret))
+	if body != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
 
 	res, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return err
 	}
+	defer res.Body.Close()
+
+	if res.StatusCode != expectedStatus {
+		var errBody map[string]any
+		json.NewDecoder(res.Body).Decode(&errBody)
+		return fmt.Errorf("%s %s failed with status %d, and body: \n%v\nMAKE SURE ENV VAR 'OBSERVE_CLI_SESSION' IS SET!", method, path, res.StatusCode, errBody)
+	}
+
+	return json.NewDecoder(res.Body).Decode(resBody)
+}
+
+func createApiKey(appId int, name string, scopes []string, expiresAt int64) error {
+	body := map[string]any{
+		"name":      name,
+		"scopes":    scopes,
+		"expiresAt": expiresAt,
+	}
+
+	var resBody map[string]any
+	err := doKeysRequest(http.MethodPost, fmt.Sprintf("/app/v1/apps/%d/keys", appId), body, http.StatusCreated, &resBody)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("New api key created with id '%v'!\nReturned key:\n\t%v\n", resBody["id"], resBody["key"])
+
+	return nil
+}
+
+func listApiKeys(appId int) error {
+	var resBody struct {
+		Message string               `json:"message"`
+		Keys    []model.GetApiKeyDTO `json:"keys"`
+	}
+	err := doKeysRequest(http.MethodGet, fmt.Sprintf("/app/v1/apps/%d/keys", appId), nil, http.StatusOK, &resBody)
+	if err != nil {
+		return err
+	}
+
+	if len(resBody.Keys) == 0 {
+		fmt.Printf("App '%d' has no api keys\n", appId)
+		return nil
+	}
 
+	fmt.Printf("Api keys of app '%d':\n", appId)
+	fmt.Printf("\tID\tSTATUS\tNAME\tSCOPES\tCREATED\tLAST USED\tEXPIRES\n")
+	for _, key := range resBody.Keys {
+		fmt.Printf("\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n", key.Id, key.Status, key.Name, strings.Join(key.Scopes, ","), formatMillis(key.CreatedAt), formatMillis(key.LastUsedAt), formatMillis(key.ExpiresAt))
+	}
+
+	return nil
+}
+
+func revokeApiKey(appId, keyId int) error {
 	var resBody map[string]any
-	if err = json.NewDecoder(res.Body).Decode(&resBody); err != nil {
+	err := doKeysRequest(http.MethodDelete, fmt.Sprintf("/app/v1/apps/%d/keys/%d", appId, keyId), nil, http.StatusOK, &resBody)
+	if err != nil {
 		return err
 	}
 
-	if res.StatusCode != http.StatusCreated {
-		return fmt.Errorf("Creating new api key for app id '%d' failed with status %d, and body: \n%v\nMAKE SURE ENV VAR 'CLI_SECRET' IS SET!", appId, res.StatusCode, resBody)
+	fmt.Printf("Api key '%d' revoked!\n", keyId)
+
+	return nil
+}
+
+func rotateApiKey(appId, keyId int, grace time.Duration, expiresAt int64) error {
+	body := map[string]any{
+		"gracePeriod": int64(grace / time.Second),
+		"expiresAt":   expiresAt,
 	}
 
-	fmt.Printf("New api key created!\nReturned key:\n\t%v\n", resBody["key"])
+	var resBody map[string]any
+	err := doKeysRequest(http.MethodPost, fmt.Sprintf("/app/v1/apps/%d/keys/%d/rotate", appId, keyId), body, http.StatusCreated, &resBody)
+	if err != nil {
+		return err
+	}
+
+	previousExpiry, _ := resBody["previousExpiry"].(float64)
+	fmt.Printf("Api key '%d' rotated, and stops working at %s\nNew api key with id '%v':\n\t%v\n", keyId, formatMillis(int64(previousExpiry)), resBody["id"], resBody["key"])
 
 	return nil
+}
 
+// formatMillis formats milliseconds since epoch, or '-' if they are not set
+func formatMillis(millis int64) string {
+	if millis == 0 {
+		return "-"
+	}
+	return time.UnixMilli(millis).Format(time.DateTime)
 }
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+type mappingsCommand struct {
+	fs      *flag.FlagSet
+	upload  bool
+	appId   int
+	version string
+	file    string
+}
+
+func MappingsCommand() Command {
+	cmd := &mappingsCommand{
+		fs: flag.NewFlagSet("mappings", flag.ExitOnError),
+	}
+
+	cmd.fs.BoolVar(&cmd.upload, "upload", false, "Upload the mapping specified with 'file' for the app id specified with 'id'")
+	cmd.fs.IntVar(&cmd.appId, "id", -1, "Id of the app the mapping belongs to")
+	cmd.fs.StringVar(&cmd.version, "version", "", "Version of the app the mapping was built for")
+	cmd.fs.StringVar(&cmd.file, "file", "", "Path to the mapping.txt written by R8 or ProGuard")
+
+	return cmd
+}
+
+func (c *mappingsCommand) Init(args []string) error {
+	return c.fs.Parse(args)
+}
+func (c *mappingsCommand) Run() {
+	if !c.upload || c.appId == -1 || c.version == "" || c.file == "" {
+		fmt.Println("Malformed arguments for 'mappings' command")
+		c.fs.Usage()
+		return
+	}
+
+	err := uploadMapping(c.appId, c.version, c.file)
+	if err != nil {
+		fmt.Printf("Could not upload mapping: %v\n", err)
+	}
+}
+func (c *mappingsCommand) Name() string {
+	return c.fs.Name()
+}
+func (c *mappingsCommand) Description() string {
+	return "Upload R8/ProGuard mappings used to deobfuscate crashes"
+}
+
+func uploadMapping(appId int, version, path string) error {
+	secret := os.Getenv("OBSERVE_CLI_SESSION")
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.WriteField("version", version); err != nil {
+		return err
+	}
+	part, err := w.CreateFormFile("mapping", filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/app/v1/apps/%d/mappings", baseUrl, appId), &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", secret))
+	req.Header.Add("Content-Type", w.FormDataContentType())
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	var resBody map[string]any
+	if err = json.NewDecoder(res.Body).Decode(&resBody); err != nil {
+		return err
+	}
+
+	if res.StatusCode != http.StatusCreated {
+		return fmt.Errorf("Uploading mapping of version '%s' for app id '%d' failed with status %d, and body: \n%v", version, appId, res.StatusCode, resBody)
+	}
+
+	fmt.Printf("Mapping of version '%s' uploaded!\n", version)
+
+	return nil
+}
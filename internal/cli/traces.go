@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+type tracesCommand struct {
+	fs        *flag.FlagSet
+	sessionId string
+	format    string
+	out       string
+}
+
+func TracesCommand() Command {
+	cmd := &tracesCommand{
+		fs: flag.NewFlagSet("traces", flag.ExitOnError),
+	}
+
+	cmd.fs.StringVar(&cmd.sessionId, "session", "", "Id of the session to export the traces of")
+	cmd.fs.StringVar(&cmd.format, "format", "json", "OTLP encoding of the export, either 'json' or 'protobuf'")
+	cmd.fs.StringVar(&cmd.out, "out", "", "File to write the export to, defaults to stdout")
+
+	return cmd
+}
+
+func (c *tracesCommand) Init(args []string) error {
+	return c.fs.Parse(args)
+}
+func (c *tracesCommand) Run() {
+	if c.sessionId == "" || (c.format != "json" && c.format != "protobuf") {
+		fmt.Println("Malformed arguments for 'traces' command")
+		c.fs.Usage()
+		return
+	}
+
+	err := exportTraces(c.sessionId, c.format, c.out)
+	if err != nil {
+		fmt.Printf("Could not export traces: %v\n", err)
+	}
+}
+func (c *tracesCommand) Name() string {
+	return c.fs.Name()
+}
+func (c *tracesCommand) Description() string {
+	return "Export the traces of a session as OTLP, e.g. for Jaeger or Tempo"
+}
+
+func exportTraces(sessionId, format, out string) error {
+	secret := os.Getenv("OBSERVE_CLI_SESSION")
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/app/v1/sessions/%s/traces?format=otlp", baseUrl, sessionId), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", secret))
+	if format == "protobuf" {
+		req.Header.Add("Accept", "application/x-protobuf")
+	} else {
+		req.Header.Add("Accept", "application/json")
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("Exporting traces of session '%s' failed with status %d", sessionId, res.StatusCode)
+	}
+
+	var w io.Writer = os.Stdout
+	if out != "" {
+		file, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if _, err := io.Copy(w, res.Body); err != nil {
+		return err
+	}
+
+	if out != "" {
+		fmt.Printf("Traces of session '%s' written to %s\n", sessionId, out)
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
@@ -32,6 +33,7 @@ type Service interface {
 
 	CreateTeamUserLink(data model.NewTeamUserLinkData) error
 	ValidateTeamUserLink(teamId, userId int) bool
+	GetTeamUserRole(teamId, userId int) (string, error)
 
 	CreateAuthSession(data model.NewAuthSessionData) error
 	GetAuthSession(sessionId string) (model.AuthSessionEntity, error)
@@ -40,14 +42,50 @@ type Service interface {
 
 	CreateApplication(data model.NewApplicationData) (int, error)
 	GetApplication(id int) (model.ApplicationEntity, error)
-	GetApplicationData(id int) (model.ApplicationDataEntity, error)
+	GetApplicationData(id int, installations, sessions model.PageQuery) (model.ApplicationDataEntity, error)
 	GetTeamApplications(teamId int) ([]model.ApplicationEntity, error)
 
-	CreateApiKey(data model.NewApiKeyData) error
-	// Validates that the given apiKey exists in the database and is active
-	ValidateApiKey(string) bool
-	// Returns the id of the owner of the ApiKey
-	GetAppId(apiKey string) (int, error)
+	CreateApiKey(data model.NewApiKeyData) (int, error)
+	GetApiKeys(appId int) ([]model.ApiKeyEntity, error)
+	// Revokes the key with the given id of the app. Returns sql.ErrNoRows
+	// if the app has no such key which is not yet revoked
+	RevokeApiKey(appId, id int, revokedAt int64) error
+	// Creates the new key with the scopes of the active key with the given id
+	// of the app, and lets that key expire at graceEnd, unless it expires
+	// before. Returns the id of the new key and when the replaced key
+	// expires, or sql.ErrNoRows if the app has no such active key
+	RotateApiKey(id int, data model.NewApiKeyData, graceEnd int64) (int, int64, error)
+	// Returns the metadata of the apiKey, including its app and scopes, if it
+	// is active at the time now. Returns sql.ErrNoRows if the key does not
+	// exist, or is revoked or expired.
+	GetApiKey(apiKey string, now int64) (model.ApiKeyEntity, error)
+	// Records that the apiKey was used, at most once per
+	// model.ApiKeyUsageResolution
+	MarkApiKeyUsed(apiKey string, usedAt int64) error
+
+	// Takes a token from the bucket of key, after refilling it at rate tokens
+	// per second up to burst. Returns whether a token was taken, and the
+	// tokens left
+	TakeRateLimitToken(key string, rate float64, burst int, now int64) (bool, float64, error)
+	// Removes the buckets which have refilled completely at now
+	DeleteFullRateLimits(now int64) error
+	// Returns sql.ErrNoRows if the settings of the app were never changed
+	GetIngestionSettings(appId int) (model.IngestionSettingsEntity, error)
+	SetIngestionSettings(data model.IngestionSettingsData) error
+	// Adds the batch to the usage of the month, storing as many events and
+	// traces as their quotas allow. Returns the number of each to store
+	AddIngestionUsage(data model.IngestionUsageData) (int, int, error)
+	// Subtracts usage which was added for a batch that was not stored after
+	// all, so it does not count against the quota when it is sent again
+	ReleaseIngestionUsage(appId int, usage model.IngestionUsageEntity) error
+	// Usage of an app in the month, which is 0 if nothing was ingested
+	GetIngestionUsage(appId int, month int64) (model.IngestionUsageEntity, error)
+
+	// Adds the counts of throttled requests to their hourly buckets
+	AddThrottledRequests(data []model.ThrottledRequestsData) error
+	// Counts of the throttled requests of an app per bucket and reason,
+	// ordered by bucket
+	GetThrottledRequests(appId int, query model.ThrottledRequestsQuery) ([]model.ThrottledRequestsEntity, error)
 
 	CreateInstallation(data model.NewInstallationData) error
 	GetInstallation(id string) (model.InstallationEntity, error)
@@ -55,17 +93,112 @@ type Service interface {
 	CreateSession(data model.NewSessionData) error
 	GetSession(id string) (model.SessionEntity, error)
 	MarkSessionCrashed(id string, ownerId int) error
-
-	CreateEvent(data model.NewEventData) error
-	GetEventsBySessionId(sessionId string) ([]model.EventEntity, error)
-
-	CreateTrace(data model.NewTraceData) error
-	GetTracesBySessionId(sessionId string) ([]model.TraceEntity, error)
+	// Sessions of an app with the given ids, skipping ids which are not stored
+	GetSessions(appId int, ids []string) ([]model.SessionEntity, error)
+	// Counts the sessions and installations of an app and how many of them
+	// crashed, ordered by bucket and the number of sessions
+	GetStability(appId int, query model.StabilityQuery) ([]model.StabilityEntity, error)
+
+	// Releases of an app, newest first by version code and first session
+	GetReleases(appId int) ([]model.ReleaseEntity, error)
+	GetRelease(appId int, version string) (model.ReleaseEntity, error)
+
+	// Returns whether the event was inserted, which it is not if it was
+	// stored before
+	CreateEvent(data model.NewEventData) (bool, error)
+	// Inserts all events in a single transaction using multi-row inserts.
+	// Returns the number of events inserted, leaving out those stored before
+	CreateEvents(data []model.NewEventData) (int, error)
+	GetEventsBySessionId(sessionId string, page model.PageQuery) ([]model.EventEntity, string, error)
+	// Events of an app matching the type and attribute predicates of search,
+	// across its sessions
+	SearchEvents(appId int, search model.EventSearchQuery, page model.PageQuery) ([]model.EventEntity, string, error)
+
+	// Returns whether the trace was inserted, which it is not if it was
+	// stored before, even if the record ends the stored trace
+	CreateTrace(data model.NewTraceData) (bool, error)
+	// Inserts all traces in a single transaction using multi-row inserts.
+	// Returns the number of traces inserted, leaving out those stored before
+	CreateTraces(data []model.NewTraceData) (int, error)
+	// Writes the whole collection in a single transaction. Returns the number
+	// of events and traces inserted, leaving out those stored before. If an
+	// item cannot be written, nothing is and a *model.CollectionItemError
+	// names the item
+	CreateCollection(data model.NewCollectionData) (int, int, error)
+	GetTracesBySessionId(sessionId string, page model.PageQuery) ([]model.TraceEntity, string, error)
+
+	// Stores the crash, groups it into the issue of its fingerprint and marks
+	// the session as crashed
+	CreateCrash(data model.NewCrashData) error
+	// Issues of an app ordered by the number of affected sessions. Only
+	// occurrences within the time range of page are counted. An empty
+	// issueType includes issues of every type
+	GetIssuesByAppId(appId int, issueType string, page model.PageQuery) ([]model.IssueEntity, error)
+	GetCrashesByIssue(appId int, fingerprint string, page model.PageQuery) ([]model.CrashEntity, string, error)
+
+	// Stores the ANR and groups it into the issue of its fingerprint. The
+	// installation is taken from the session
+	CreateAnr(data model.NewAnrData) error
+	GetAnrsByIssue(appId int, fingerprint string, page model.PageQuery) ([]model.AnrEntity, string, error)
+	// ANR counts per app version of the sessions created within the time
+	// range of page, newest version first
+	GetAnrRates(appId int, page model.PageQuery) ([]model.AnrRateEntity, error)
+
+	// Stores the mapping of an app version, replacing any previous upload
+	CreateMapping(data model.NewMappingData) error
+	GetMapping(appId int, version string) (model.MappingEntity, error)
+	GetMappingContent(appId int, version string) ([]byte, error)
+	GetMappings(appId int) ([]model.MappingEntity, error)
+
+	CreateIngestionBatch(data model.NewIngestionBatchData) error
+	// Inserts or updates the result of processing an ingestion batch
+	UpdateIngestionBatch(data model.IngestionBatchResultData) error
+	GetIngestionBatch(id string) (model.IngestionBatchEntity, error)
 
 	CreateMemoryUsage(data model.NewMemoryUsageData) error
 	GetMemoryUsageById(id string) (model.MemoryUsageEntity, error)
-	GetMemoryUsageBySessionId(id string) ([]model.MemoryUsageEntity, error)
-	GetMemoryUsageByInstallationId(id string) ([]model.MemoryUsageEntity, error)
+	GetMemoryUsageBySessionId(id string, page model.PageQuery) ([]model.MemoryUsageEntity, string, error)
+	GetMemoryUsageByInstallationId(id string, page model.PageQuery) ([]model.MemoryUsageEntity, string, error)
+	// Percentiles of the memory usage of an app, ordered by bucket and the
+	// number of samples
+	GetMemorySummary(appId int, query model.MemorySummaryQuery) ([]model.MemoryPercentilesEntity, error)
+	// Memory trends of the sessions with at least minSamples samples whose
+	// used memory grew, ordered by the growth relative to their max memory
+	GetMemoryTrends(appId int, query model.MemorySummaryQuery, minSamples, limit int) ([]model.MemoryTrendEntity, error)
+
+	CreateCpuUsage(data model.NewCpuUsageData) error
+	GetCpuUsageBySessionId(id string, page model.PageQuery) ([]model.CpuUsageEntity, string, error)
+	GetCpuUsageByInstallationId(id string, page model.PageQuery) ([]model.CpuUsageEntity, string, error)
+
+	CreateFrameMetrics(data model.NewFrameMetricsData) error
+	GetFrameMetricsBySessionId(id string, page model.PageQuery) ([]model.FrameMetricsEntity, string, error)
+	GetFrameMetricsByInstallationId(id string, page model.PageQuery) ([]model.FrameMetricsEntity, string, error)
+	// Frames rendered per screen of an app, with the screens with the most
+	// janky and frozen frames first
+	GetFrameSummary(appId int, page model.PageQuery) ([]model.FrameSummaryEntity, error)
+
+	CreateBatteryState(data model.NewBatteryStateData) error
+	GetBatteryStateBySessionId(id string, page model.PageQuery) ([]model.BatteryStateEntity, string, error)
+	GetBatteryStateByInstallationId(id string, page model.PageQuery) ([]model.BatteryStateEntity, string, error)
+
+	CreateNetworkUsage(data model.NewNetworkUsageData) error
+	GetNetworkUsageBySessionId(id string, page model.PageQuery) ([]model.NetworkUsageEntity, string, error)
+	GetNetworkUsageByInstallationId(id string, page model.PageQuery) ([]model.NetworkUsageEntity, string, error)
+
+	CreateAppStart(data model.NewAppStartData) error
+	GetAppStartsBySessionId(id string, page model.PageQuery) ([]model.AppStartEntity, string, error)
+	GetAppStartsByInstallationId(id string, page model.PageQuery) ([]model.AppStartEntity, string, error)
+	// Percentiles of the duration of the starts of an app, and of their
+	// phases, ordered by bucket and the number of starts
+	GetStartupSummary(appId int, query model.StartupQuery) ([]model.StartupPercentilesEntity, error)
+
+	CreateNetworkRequest(data model.NewNetworkRequestData) error
+	CreateNetworkRequests(data []model.NewNetworkRequestData) error
+	GetNetworkRequestsBySessionId(id string, page model.PageQuery) ([]model.NetworkRequestEntity, string, error)
+	GetNetworkRequestsByTraceId(traceId string) ([]model.NetworkRequestEntity, error)
+	// Latency and errors of the requests per bucket and endpoint, ordered by
+	// bucket and the number of requests
+	GetNetworkEndpoints(appId int, query model.NetworkQuery) ([]model.NetworkEndpointEntity, error)
 
 	// Health returns a map of health status information.
 	// The keys and values in the map are service-specific.
@@ -84,6 +217,33 @@ var (
 	dbInstance *service
 )
 
+// Max number of rows in a single multi-row insert. Keeps the number of
+// bind parameters well below the postgres limit of 65535
+const maxBatchRows = 1000
+
+// SDKs retry uploads on flaky networks, so every ingestion insert accepts
+// replays of rows it has already stored. A replayed trace is ignored,
+// unless it has ended and completes a trace that was stored unfinished.
+const (
+	ignoreConflictClause = "ON CONFLICT DO NOTHING"
+	// Rows updated by an upsert have xmax set to the updating transaction,
+	// while inserted rows do not
+	insertedClause      = "RETURNING xmax = 0"
+	sessionUpsertClause = `ON CONFLICT (id) DO UPDATE SET
+		crashed = GREATEST(s.crashed, EXCLUDED.crashed),
+		app_version = COALESCE(NULLIF(EXCLUDED.app_version, ''), s.app_version),
+		version_code = GREATEST(s.version_code, EXCLUDED.version_code),
+		build_type = COALESCE(NULLIF(EXCLUDED.build_type, ''), s.build_type)
+	WHERE s.app_id = EXCLUDED.app_id`
+	traceUpsertClause = `ON CONFLICT (trace_id) DO UPDATE SET
+		status = EXCLUDED.status,
+		error_message = EXCLUDED.error_message,
+		ended_at = EXCLUDED.ended_at,
+		has_ended = EXCLUDED.has_ended,
+		attributes = t.attributes || EXCLUDED.attributes
+	WHERE t.has_ended = 0 AND EXCLUDED.has_ended = 1 AND t.app_id = EXCLUDED.app_id`
+)
+
 func SetupTestDatabase(schema string) (func(context.Context, ...testcontainers.TerminateOption) error, model.DatabaseConfig, error) {
 	var (
 		dbName = "routes_database"
@@ -240,6 +400,17 @@ func (s *service) ValidateTeamUserLink(teamId, userId int) bool {
 	return exists
 }
 
+// GetTeamUserRole returns the role of the user in the team, or sql.ErrNoRows
+// if the user is not a member
+func (s *service) GetTeamUserRole(teamId, userId int) (string, error) {
+	query := "SELECT role FROM public.ob_team_users WHERE team_id = $1 AND user_id = $2 ORDER BY id LIMIT 1"
+
+	var role string
+	err := s.db.QueryRow(query, teamId, userId).Scan(&role)
+
+	return role, err
+}
+
 func (s *service) CreateAuthSession(data model.NewAuthSessionData) error {
 	tx, err := s.db.Begin()
 	if err != nil {
@@ -316,15 +487,25 @@ func (s *service) GetApplication(id int) (model.ApplicationEntity, error) {
 	return res, err
 }
 
-func (s *service) GetApplicationData(id int) (model.ApplicationDataEntity, error) {
-	installationQuery := "SELECT id, type, data, app_id, created_at FROM public.ob_installations WHERE app_id = $1"
+func (s *service) GetApplicationData(id int, installations, sessions model.PageQuery) (model.ApplicationDataEntity, error) {
+	// Installations are part of a release if any of their sessions is
+	filter, args := releaseClause("s.app_version", installations, []any{id})
+	if filter != "" {
+		filter = " AND EXISTS (SELECT 1 FROM public.ob_sessions s WHERE s.installation_id = i.id AND s.app_id = $1" + filter + ")"
+	}
+	clause, args, err := pageClause("created_at", "id", installations, args)
+	if err != nil {
+		return model.ApplicationDataEntity{}, err
+	}
+	installationQuery := "SELECT id, type, data, app_id, created_at FROM public.ob_installations i WHERE app_id = $1" + filter + clause
 
-	rows, err := s.db.Query(installationQuery, id)
+	rows, err := s.db.Query(installationQuery, args...)
 	if err != nil {
 		return model.ApplicationDataEntity{}, err
 	}
+	defer rows.Close()
 
-	installations := make([]model.InstallationEntity, 0)
+	installationEntities := make([]model.InstallationEntity, 0)
 	for rows.Next() {
 		var entityData []byte
 		var entity model.InstallationEntity
@@ -344,30 +525,46 @@ func (s *service) GetApplicationData(id int) (model.ApplicationDataEntity, error
 			log.Printf("Error unmarshalling installation data: %v\n", err)
 			return model.ApplicationDataEntity{}, err
 		}
-		installations = append(installations, entity)
+		installationEntities = append(installationEntities, entity)
 	}
 
-	sessionQuery := "SELECT id, installation_id, created_at, crashed, app_id FROM public.ob_sessions WHERE app_id = $1"
+	installationPage, nextInstallationCursor := nextPage(installationEntities, installations.NormalizedLimit(), func(e model.InstallationEntity) (int64, string) {
+		return e.CreatedAt, e.Id
+	})
 
-	rows, err = s.db.Query(sessionQuery, id)
+	filter, args = releaseClause("app_version", sessions, []any{id})
+	clause, args, err = pageClause("created_at", "id", sessions, args)
 	if err != nil {
 		return model.ApplicationDataEntity{}, err
 	}
+	sessionQuery := "SELECT id, installation_id, app_version, version_code, build_type, created_at, crashed, app_id FROM public.ob_sessions WHERE app_id = $1" + filter + clause
 
-	sessions := make([]model.SessionEntity, 0)
-	for rows.Next() {
+	sessionRows, err := s.db.Query(sessionQuery, args...)
+	if err != nil {
+		return model.ApplicationDataEntity{}, err
+	}
+	defer sessionRows.Close()
+
+	sessionEntities := make([]model.SessionEntity, 0)
+	for sessionRows.Next() {
 		var entity model.SessionEntity
-		err := rows.Scan(&entity.Id, &entity.InstallationId, &entity.CreatedAt, &entity.Crashed, &entity.AppId)
+		err := sessionRows.Scan(&entity.Id, &entity.InstallationId, &entity.AppVersion, &entity.VersionCode, &entity.BuildType, &entity.CreatedAt, &entity.Crashed, &entity.AppId)
 		if err != nil {
 			log.Printf("Error scanning installation entity: %v\n", err)
 			return model.ApplicationDataEntity{}, err
 		}
-		sessions = append(sessions, entity)
+		sessionEntities = append(sessionEntities, entity)
 	}
 
+	sessionPage, nextSessionCursor := nextPage(sessionEntities, sessions.NormalizedLimit(), func(e model.SessionEntity) (int64, string) {
+		return e.CreatedAt, e.Id
+	})
+
 	return model.ApplicationDataEntity{
-		Installations: installations,
-		Sessions:      sessions,
+		Installations:          installationPage,
+		Sessions:               sessionPage,
+		NextInstallationCursor: nextInstallationCursor,
+		NextSessionCursor:      nextSessionCursor,
 	}, nil
 }
 
@@ -396,10 +593,92 @@ func (s *service) GetTeamApplications(teamId int) ([]model.ApplicationEntity, er
 	return apps, nil
 }
 
-func (s *service) CreateApiKey(data model.NewApiKeyData) error {
-	query := "INSERT INTO public.ob_api_keys(key, app_id) VALUES ($1, $2)"
+const insertApiKeyQuery = `INSERT INTO public.ob_api_keys(key, app_id, name, scopes, created_by, created_at, expires_at)
+	VALUES ($1, $2, $3, $4, NULLIF($5, 0), $6, NULLIF($7, 0))
+	RETURNING id`
+
+func insertApiKeyArgs(data model.NewApiKeyData) ([]any, error) {
+	if data.Scopes == nil {
+		data.Scopes = []string{}
+	}
+	scopes, err := json.Marshal(data.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	return []any{data.Key, data.AppId, data.Name, scopes, data.CreatedBy, data.CreatedAt, data.ExpiresAt}, nil
+}
+
+func (s *service) CreateApiKey(data model.NewApiKeyData) (int, error) {
+	args, err := insertApiKeyArgs(data)
+	if err != nil {
+		return -1, err
+	}
+
+	var id int
+	err = s.db.QueryRow(insertApiKeyQuery, args...).Scan(&id)
+
+	return id, err
+}
+
+const apiKeyColumns = "id, app_id, name, scopes, created_at, COALESCE(created_by, 0), COALESCE(last_used_at, 0), COALESCE(expires_at, 0), COALESCE(revoked_at, 0)"
+
+func scanApiKey(row interface{ Scan(dest ...any) error }) (model.ApiKeyEntity, error) {
+	var ent model.ApiKeyEntity
+	var scopes []byte
+	err := row.Scan(
+		&ent.Id,
+		&ent.AppId,
+		&ent.Name,
+		&scopes,
+		&ent.CreatedAt,
+		&ent.CreatedBy,
+		&ent.LastUsedAt,
+		&ent.ExpiresAt,
+		&ent.RevokedAt,
+	)
+	if err != nil {
+		return ent, err
+	}
+
+	err = json.Unmarshal(scopes, &ent.Scopes)
+	return ent, err
+}
+
+func (s *service) GetApiKeys(appId int) ([]model.ApiKeyEntity, error) {
+	query := `SELECT ` + apiKeyColumns + `
+	FROM public.ob_api_keys
+	WHERE app_id = $1
+	ORDER BY created_at DESC, id DESC`
+
+	rows, err := s.db.Query(query, appId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]model.ApiKeyEntity, 0)
+	for rows.Next() {
+		ent, err := scanApiKey(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, ent)
+	}
+
+	return keys, rows.Err()
+}
+
+func (s *service) GetApiKey(apiKey string, now int64) (model.ApiKeyEntity, error) {
+	query := "SELECT " + apiKeyColumns + " FROM public.ob_api_keys WHERE key = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > $2)"
+
+	return scanApiKey(s.db.QueryRow(query, apiKey, now))
+}
+
+func (s *service) RevokeApiKey(appId, id int, revokedAt int64) error {
+	query := "UPDATE public.ob_api_keys SET revoked_at = $3 WHERE id = $1 AND app_id = $2 AND revoked_at IS NULL"
 
-	res, err := s.db.Exec(query, data.Key, data.AppId)
+	res, err := s.db.Exec(query, id, appId, revokedAt)
 	if err != nil {
 		return err
 	}
@@ -408,14 +687,52 @@ func (s *service) CreateApiKey(data model.NewApiKeyData) error {
 	if err != nil {
 		return err
 	}
-
-	if rowsAffected != 1 {
-		return fmt.Errorf("Expected 1 api key to be inserted but was %d", rowsAffected)
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
 	}
 
 	return nil
 }
 
+func (s *service) RotateApiKey(id int, data model.NewApiKeyData, graceEnd int64) (int, int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return -1, 0, err
+	}
+	defer tx.Rollback()
+
+	// LEAST ignores NULL, so keys without expiry get the end of the grace period
+	query := `UPDATE public.ob_api_keys
+	SET expires_at = LEAST(expires_at, $3)
+	WHERE id = $1 AND app_id = $2 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > $4)
+	RETURNING name, scopes, expires_at`
+
+	var name string
+	var scopes []byte
+	var expiresAt int64
+	if err := tx.QueryRow(query, id, data.AppId, graceEnd, data.CreatedAt).Scan(&name, &scopes, &expiresAt); err != nil {
+		return -1, 0, err
+	}
+
+	if data.Name == "" {
+		data.Name = name
+	}
+	if err := json.Unmarshal(scopes, &data.Scopes); err != nil {
+		return -1, 0, err
+	}
+	args, err := insertApiKeyArgs(data)
+	if err != nil {
+		return -1, 0, err
+	}
+
+	var newId int
+	if err := tx.QueryRow(insertApiKeyQuery, args...).Scan(&newId); err != nil {
+		return -1, 0, err
+	}
+
+	return newId, expiresAt, tx.Commit()
+}
+
 func (s *service) MarkSessionCrashed(id string, ownerId int) error {
 	tx, err := s.db.Begin()
 	if err != nil {
@@ -451,7 +768,7 @@ func (s *service) CreateInstallation(data model.NewInstallationData) error {
 		$3,
 		$4,
 		$5
-	)`
+	) ` + ignoreConflictClause
 
 	res, err := s.db.Exec(
 		stmt,
@@ -470,8 +787,8 @@ func (s *service) CreateInstallation(data model.NewInstallationData) error {
 		return err
 	}
 
-	if rowsAffected != 1 {
-		return fmt.Errorf("Expected 1 session to be inserted but was %d", rowsAffected)
+	if rowsAffected > 1 {
+		return fmt.Errorf("Expected at most 1 installation to be inserted but was %d", rowsAffected)
 	}
 
 	return nil
@@ -500,12 +817,29 @@ func (s *service) GetInstallation(id string) (model.InstallationEntity, error) {
 }
 
 func (s *service) CreateSession(data model.NewSessionData) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := createSession(tx, data); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// createSession upserts the session and the release of its app version
+func createSession(tx *sql.Tx, data model.NewSessionData) error {
 	crashed := 0
 	if data.Crashed {
 		crashed = 1
 	}
 
-	res, err := s.db.Exec("INSERT INTO public.ob_sessions (id, installation_id, app_id, created_at, crashed) VALUES ($1, $2, $3, $4, $5)", data.Id, data.InstallationId, data.AppId, data.CreatedAt, crashed)
+	query := "INSERT INTO public.ob_sessions AS s (id, installation_id, app_id, app_version, version_code, build_type, created_at, crashed) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) " + sessionUpsertClause
+
+	res, err := tx.Exec(query, data.Id, data.InstallationId, data.AppId, data.AppVersion, data.VersionCode, data.BuildType, data.CreatedAt, crashed)
 	if err != nil {
 		return err
 	}
@@ -515,60 +849,114 @@ func (s *service) CreateSession(data model.NewSessionData) error {
 		return err
 	}
 
+	// Nothing is affected if the id is already used by a session of another app
 	if rowsAffected != 1 {
 		return fmt.Errorf("Expected 1 session to be inserted but was %d", rowsAffected)
 	}
 
+	if data.AppVersion != "" {
+		releaseQuery := `
+		INSERT INTO public.ob_releases AS r (app_id, version, version_code, first_seen, last_seen)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (app_id, version) DO UPDATE SET
+			version_code = GREATEST(r.version_code, EXCLUDED.version_code),
+			first_seen = LEAST(r.first_seen, EXCLUDED.first_seen),
+			last_seen = GREATEST(r.last_seen, EXCLUDED.last_seen)`
+
+		_, err = tx.Exec(releaseQuery, data.AppId, data.AppVersion, data.VersionCode, data.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (s *service) GetSession(id string) (model.SessionEntity, error) {
-	query := "SELECT id, installation_id, created_at, crashed, app_id FROM public.ob_sessions WHERE id = $1"
+	query := "SELECT id, installation_id, app_version, version_code, build_type, created_at, crashed, app_id FROM public.ob_sessions WHERE id = $1"
 
 	var entity model.SessionEntity
-	err := s.db.QueryRow(query, id).Scan(&entity.Id, &entity.InstallationId, &entity.CreatedAt, &entity.Crashed, &entity.AppId)
+	err := s.db.QueryRow(query, id).Scan(&entity.Id, &entity.InstallationId, &entity.AppVersion, &entity.VersionCode, &entity.BuildType, &entity.CreatedAt, &entity.Crashed, &entity.AppId)
 
 	return entity, err
 }
 
-func (s *service) CreateEvent(data model.NewEventData) error {
-	sql := " INSERT INTO public.ob_events( id, session_id, app_id, created_at, type, serialized_data) VALUES ($1, $2, $3, $4, $5, $6)"
+func (s *service) GetSessions(appId int, ids []string) ([]model.SessionEntity, error) {
+	query := "SELECT id, installation_id, app_version, version_code, build_type, created_at, crashed, app_id FROM public.ob_sessions WHERE app_id = $1 AND id = ANY($2)"
 
-	res, err := s.db.Exec(sql, data.Id, data.SessionId, data.AppId, data.CreatedAt, data.Type, data.SerializedData)
+	rows, err := s.db.Query(query, appId, ids)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer rows.Close()
 
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return err
+	entities := make([]model.SessionEntity, 0, len(ids))
+	for rows.Next() {
+		var entity model.SessionEntity
+		if err := rows.Scan(&entity.Id, &entity.InstallationId, &entity.AppVersion, &entity.VersionCode, &entity.BuildType, &entity.CreatedAt, &entity.Crashed, &entity.AppId); err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
 	}
 
-	if rowsAffected != 1 {
-		return fmt.Errorf("Expected 1 event to be inserted but was %d", rowsAffected)
+	return entities, rows.Err()
+}
+
+func (s *service) GetStability(appId int, query model.StabilityQuery) ([]model.StabilityEntity, error) {
+	args := []any{appId}
+	conditions := ""
+	if query.From > 0 {
+		args = append(args, query.From)
+		conditions += fmt.Sprintf(" AND s.created_at >= $%d", len(args))
+	}
+	if query.To > 0 {
+		args = append(args, query.To)
+		conditions += fmt.Sprintf(" AND s.created_at <= $%d", len(args))
+	}
+	if query.Release != "" {
+		args = append(args, query.Release)
+		conditions += fmt.Sprintf(" AND s.app_version = $%d", len(args))
 	}
 
-	return nil
-}
+	bucket, args := bucketExpr("s.created_at", query.Interval, args)
 
-func (s *service) GetEventsBySessionId(sessionId string) ([]model.EventEntity, error) {
-	query := "SELECT id, session_id, app_id, created_at, type, serialized_data FROM public.ob_events WHERE session_id = $1"
+	group := "''"
+	join := ""
+	if query.ByRelease {
+		group = "s.app_version"
+	} else if query.GroupBy != "" {
+		args = append(args, query.GroupBy)
+		group = fmt.Sprintf("COALESCE(i.data ->> $%d, '')", len(args))
+		join = " LEFT JOIN public.ob_installations i ON i.id = s.installation_id AND i.app_id = s.app_id"
+	}
 
-	rows, err := s.db.Query(query, sessionId)
+	stmt := fmt.Sprintf(`
+	SELECT %s, %s,
+		COUNT(s.id),
+		COUNT(s.id) FILTER (WHERE s.crashed = 1),
+		COUNT(DISTINCT s.installation_id),
+		COUNT(DISTINCT s.installation_id) FILTER (WHERE s.crashed = 1)
+	FROM public.ob_sessions s%s
+	WHERE s.app_id = $1%s
+	GROUP BY 1, 2
+	ORDER BY 1, 3 DESC, 2`, bucket, group, join, conditions)
+
+	rows, err := s.db.Query(stmt, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	entities := make([]model.EventEntity, 0)
+	entities := make([]model.StabilityEntity, 0)
 	for rows.Next() {
-		var ent model.EventEntity
-		err = rows.Scan(
-			&ent.Id,
-			&ent.SessionId,
-			&ent.AppId,
-			&ent.CreatedAt,
-			&ent.Type,
-			&ent.SerializedData,
+		var ent model.StabilityEntity
+		err := rows.Scan(
+			&ent.Bucket,
+			&ent.Group,
+			&ent.Sessions,
+			&ent.CrashedSessions,
+			&ent.Installations,
+			&ent.CrashedInstallations,
 		)
 		if err != nil {
 			return nil, err
@@ -577,186 +965,1709 @@ func (s *service) GetEventsBySessionId(sessionId string) ([]model.EventEntity, e
 		entities = append(entities, ent)
 	}
 
-	return entities, err
+	return entities, rows.Err()
 }
 
-func (s *service) CreateTrace(data model.NewTraceData) error {
-	sql := "INSERT INTO public.ob_trace( trace_id, session_id, group_id, parent_id, app_id, name, status, error_message, started_at, ended_at, has_ended) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)"
+func (s *service) GetReleases(appId int) ([]model.ReleaseEntity, error) {
+	query := "SELECT app_id, version, version_code, first_seen, last_seen FROM public.ob_releases WHERE app_id = $1 ORDER BY version_code DESC, first_seen DESC, version"
 
-	hasEnded := 0
-	if data.HasEnded {
-		hasEnded = 1
-	}
-
-	res, err := s.db.Exec(sql, data.TraceId, data.SessionId, data.GroupId, data.ParentId, data.AppId, data.Name, data.Status, data.ErrorMessage, data.StartedAt, data.EndedAt, hasEnded)
+	rows, err := s.db.Query(query, appId)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer rows.Close()
 
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
+	entities := make([]model.ReleaseEntity, 0)
+	for rows.Next() {
+		var ent model.ReleaseEntity
+		if err := rows.Scan(&ent.AppId, &ent.Version, &ent.VersionCode, &ent.FirstSeen, &ent.LastSeen); err != nil {
+			return nil, err
+		}
 
-	if rowsAffected != 1 {
-		return fmt.Errorf("Expected 1 trace to be inserted but was %d", rowsAffected)
+		entities = append(entities, ent)
 	}
 
-	return nil
+	return entities, rows.Err()
 }
 
-func (s *service) GetTracesBySessionId(sessionId string) ([]model.TraceEntity, error) {
-	query := "SELECT trace_id, session_id, group_id, parent_id, app_id, name, status, error_message, started_at, ended_at, has_ended FROM public.ob_trace WHERE session_id = $1"
+func (s *service) GetRelease(appId int, version string) (model.ReleaseEntity, error) {
+	query := "SELECT app_id, version, version_code, first_seen, last_seen FROM public.ob_releases WHERE app_id = $1 AND version = $2"
+
+	var ent model.ReleaseEntity
+	err := s.db.QueryRow(query, appId, version).Scan(&ent.AppId, &ent.Version, &ent.VersionCode, &ent.FirstSeen, &ent.LastSeen)
+
+	return ent, err
+}
 
-	rows, err := s.db.Query(query, sessionId)
+func (s *service) CreateEvent(data model.NewEventData) (bool, error) {
+	attributes, err := attributesJson(data.Attributes)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 
-	entities := make([]model.TraceEntity, 0)
-	for rows.Next() {
-		var ent model.TraceEntity
-		err = rows.Scan(
-			&ent.TraceId,
-			&ent.SessionId,
-			&ent.GroupId,
-			&ent.ParentId,
-			&ent.AppId,
-			&ent.Name,
-			&ent.Status,
-			&ent.ErrorMessage,
-			&ent.StartedAt,
-			&ent.EndedAt,
-			&ent.HasEnded,
-		)
-		if err != nil {
-			return nil, err
-		}
+	sql := "INSERT INTO public.ob_events( id, session_id, app_id, created_at, type, serialized_data, attributes) VALUES ($1, $2, $3, $4, $5, $6, $7) " + ignoreConflictClause
 
-		entities = append(entities, ent)
+	res, err := s.db.Exec(sql, data.Id, data.SessionId, data.AppId, data.CreatedAt, data.Type, data.SerializedData, attributes)
+	if err != nil {
+		return false, err
 	}
 
-	return entities, err
-}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
 
-func (s *service) CreateMemoryUsage(data model.NewMemoryUsageData) error {
-	query := "INSERT INTO public.ob_memory_usage (id, session_id, installation_id, app_id, free_memory, used_memory, max_memory, total_memory, available_heap_space, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)"
+	if rowsAffected > 1 {
+		return false, fmt.Errorf("Expected at most 1 event to be inserted but was %d", rowsAffected)
+	}
 
-	_, err := s.db.Exec(query, data.Id, data.SessionId, data.InstallationId, data.AppId, data.FreeMemory, data.UsedMemory, data.MaxMemory, data.TotalMemory, data.AvailableHeapSpace, data.CreatedAt)
+	return rowsAffected == 1, nil
+}
+
+func (s *service) CreateEvents(data []model.NewEventData) (int, error) {
+	rows, err := eventRows(data)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	return nil
+	return s.insertBatch(eventsTable, eventInsertColumns, rows, ignoreConflictClause)
 }
 
-func (s *service) GetMemoryUsageById(id string) (model.MemoryUsageEntity, error) {
-	query := "SELECT id, session_id, installation_id, app_id, free_memory, used_memory, max_memory, total_memory, available_heap_space, created_at FROM public.ob_memory_usage WHERE id = $1"
+var eventInsertColumns = []string{"id", "session_id", "app_id", "created_at", "type", "serialized_data", "attributes"}
 
-	var ent model.MemoryUsageEntity
-	err := s.db.QueryRow(query, id).Scan(
-		&ent.Id,
-		&ent.SessionId,
-		&ent.InstallationId,
-		&ent.AppId,
-		&ent.FreeMemory,
-		&ent.UsedMemory,
-		&ent.MaxMemory,
-		&ent.TotalMemory,
-		&ent.AvailableHeapSpace,
-		&ent.CreatedAt,
-	)
+const eventsTable = "public.ob_events"
 
-	return ent, err
+// eventRows returns the rows of the events for insertBatch. Events with
+// invalid attributes fail with a *model.CollectionItemError
+func eventRows(data []model.NewEventData) ([][]any, error) {
+	rows := make([][]any, len(data))
+	for i, d := range data {
+		attributes, err := attributesJson(d.Attributes)
+		if err != nil {
+			return nil, &model.CollectionItemError{Kind: model.CollectionEvents, Index: i, Err: err}
+		}
+		rows[i] = []any{d.Id, d.SessionId, d.AppId, d.CreatedAt, d.Type, d.SerializedData, attributes}
+	}
+	return rows, nil
 }
 
-func (s *service) GetMemoryUsageBySessionId(id string) ([]model.MemoryUsageEntity, error) {
-	query := "SELECT id, session_id, installation_id, app_id, free_memory, used_memory, max_memory, total_memory, available_heap_space, created_at FROM public.ob_memory_usage WHERE session_id = $1"
-
-	rows, err := s.db.Query(query, id)
+func (s *service) GetEventsBySessionId(sessionId string, page model.PageQuery) ([]model.EventEntity, string, error) {
+	clause, args, err := pageClause("e.created_at", "e.id", page, []any{sessionId})
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
+	query := "SELECT " + eventColumns + " FROM public.ob_events e WHERE e.session_id = $1" + clause
 
-	entities := make([]model.MemoryUsageEntity, 0)
-	for rows.Next() {
-		var ent model.MemoryUsageEntity
-		err = rows.Scan(
-			&ent.Id,
-			&ent.SessionId,
-			&ent.InstallationId,
-			&ent.AppId,
-			&ent.FreeMemory,
-			&ent.UsedMemory,
-			&ent.MaxMemory,
-			&ent.TotalMemory,
-			&ent.AvailableHeapSpace,
-			&ent.CreatedAt,
-		)
-		if err != nil {
-			return nil, err
+	return s.queryEvents(query, page, args...)
+}
+
+func (s *service) SearchEvents(appId int, search model.EventSearchQuery, page model.PageQuery) ([]model.EventEntity, string, error) {
+	args := []any{appId}
+	conditions := ""
+	joins := ""
+	if search.Type != "" {
+		args = append(args, search.Type)
+		conditions += fmt.Sprintf(" AND e.type = $%d", len(args))
+	}
+	// Containment can use the GIN index of the attributes
+	for _, predicate := range search.Predicates {
+		matches := make([]string, len(predicate.Values))
+		for i, value := range predicate.Values {
+			attribute, err := json.Marshal(map[string]any{predicate.Key: value})
+			if err != nil {
+				return nil, "", err
+			}
+			args = append(args, string(attribute))
+			matches[i] = fmt.Sprintf("e.attributes @> $%d::jsonb", len(args))
 		}
+		conditions += " AND (" + strings.Join(matches, " OR ") + ")"
+	}
+	if page.Release != "" {
+		joins += " JOIN public.ob_sessions s ON s.id = e.session_id"
+	}
+	filter, args := releaseClause("s.app_version", page, args)
 
-		entities = append(entities, ent)
+	clause, args, err := pageClause("e.created_at", "e.id", page, args)
+	if err != nil {
+		return nil, "", err
 	}
+	query := "SELECT " + eventColumns + " FROM public.ob_events e" + joins + " WHERE e.app_id = $1" + conditions + filter + clause
 
-	return entities, err
+	return s.queryEvents(query, page, args...)
 }
 
-func (s *service) GetMemoryUsageByInstallationId(id string) ([]model.MemoryUsageEntity, error) {
-	query := "SELECT id, session_id, installation_id, app_id, free_memory, used_memory, max_memory, total_memory, available_heap_space, created_at FROM public.ob_memory_usage WHERE installation_id = $1"
+const eventColumns = "e.id, e.session_id, e.app_id, e.created_at, e.type, e.serialized_data, e.attributes"
 
-	rows, err := s.db.Query(query, id)
+// queryEvents loads a page of events selected by a query ending in the
+// clause of pageClause
+func (s *service) queryEvents(query string, page model.PageQuery, args ...any) ([]model.EventEntity, string, error) {
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
+	defer rows.Close()
 
-	entities := make([]model.MemoryUsageEntity, 0)
+	entities := make([]model.EventEntity, 0)
 	for rows.Next() {
-		var ent model.MemoryUsageEntity
+		var ent model.EventEntity
+		var attributes []byte
 		err = rows.Scan(
 			&ent.Id,
 			&ent.SessionId,
-			&ent.InstallationId,
 			&ent.AppId,
-			&ent.FreeMemory,
-			&ent.UsedMemory,
-			&ent.MaxMemory,
-			&ent.TotalMemory,
-			&ent.AvailableHeapSpace,
 			&ent.CreatedAt,
+			&ent.Type,
+			&ent.SerializedData,
+			&attributes,
 		)
 		if err != nil {
-			return nil, err
+			return nil, "", err
+		}
+		if err := json.Unmarshal(attributes, &ent.Attributes); err != nil {
+			return nil, "", err
 		}
 
 		entities = append(entities, ent)
 	}
 
-	return entities, err
-}
-
-func (s *service) ValidateApiKey(apiKey string) bool {
-	query := "SELECT EXISTS(SELECT 1 FROM public.ob_api_keys WHERE key = $1)"
-
-	var exists bool
-	if err := s.db.QueryRow(query, apiKey).Scan(&exists); err != nil {
-		log.Printf("Error validating api key: %v\n", err)
-		return false
+	if err := rows.Err(); err != nil {
+		return nil, "", err
 	}
 
-	return exists
-}
+	entities, cursor := nextPage(entities, page.NormalizedLimit(), func(e model.EventEntity) (int64, string) {
+		return e.CreatedAt, e.Id
+	})
 
-func (s *service) GetAppId(apiKey string) (int, error) {
-	query := "SELECT app_id FROM public.ob_api_keys WHERE key = $1"
+	return entities, cursor, nil
+}
 
-	var ownerId int
-	if err := s.db.QueryRow(query, apiKey).Scan(&ownerId); err != nil {
-		return -1, err
+func (s *service) CreateTrace(data model.NewTraceData) (bool, error) {
+	attributes, err := attributesJson(data.Attributes)
+	if err != nil {
+		return false, err
 	}
-	return ownerId, nil
-}
+
+	query := "INSERT INTO public.ob_trace AS t ( trace_id, session_id, group_id, parent_id, app_id, name, status, error_message, started_at, ended_at, has_ended, attributes) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) " + traceUpsertClause + " " + insertedClause
+
+	hasEnded := 0
+	if data.HasEnded {
+		hasEnded = 1
+	}
+
+	var inserted bool
+	err = s.db.QueryRow(query, data.TraceId, data.SessionId, data.GroupId, data.ParentId, data.AppId, data.Name, data.Status, data.ErrorMessage, data.StartedAt, data.EndedAt, hasEnded, attributes).Scan(&inserted)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The stored trace has ended already or belongs to another app, so
+		// nothing was written
+		return false, nil
+	}
+
+	return inserted, err
+}
+
+func (s *service) CreateTraces(data []model.NewTraceData) (int, error) {
+	rows, _, err := traceRows(data)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.insertBatch(tracesTable, traceInsertColumns, rows, traceUpsertClause)
+}
+
+var traceInsertColumns = []string{"trace_id", "session_id", "group_id", "parent_id", "app_id", "name", "status", "error_message", "started_at", "ended_at", "has_ended", "attributes"}
+
+const tracesTable = "public.ob_trace AS t"
+
+// traceRows returns the rows of the traces for insertBatch, and the index in
+// data of each row. Traces with invalid attributes fail with a
+// *model.CollectionItemError
+func traceRows(data []model.NewTraceData) ([][]any, []int, error) {
+	// An upsert cannot affect the same row twice in one statement, so only
+	// keep one record per trace id, preferring the one that has ended
+	indices := make(map[string]int, len(data))
+	rows := make([][]any, 0, len(data))
+	positions := make([]int, 0, len(data))
+	for pos, d := range data {
+		hasEnded := 0
+		if d.HasEnded {
+			hasEnded = 1
+		}
+		attributes, err := attributesJson(d.Attributes)
+		if err != nil {
+			return nil, nil, &model.CollectionItemError{Kind: model.CollectionTraces, Index: pos, Err: err}
+		}
+		row := []any{d.TraceId, d.SessionId, d.GroupId, d.ParentId, d.AppId, d.Name, d.Status, d.ErrorMessage, d.StartedAt, d.EndedAt, hasEnded, attributes}
+
+		if i, ok := indices[d.TraceId]; ok {
+			if d.HasEnded {
+				rows[i] = row
+				positions[i] = pos
+			}
+			continue
+		}
+		indices[d.TraceId] = len(rows)
+		rows = append(rows, row)
+		positions = append(positions, pos)
+	}
+	return rows, positions, nil
+}
+
+func (s *service) GetTracesBySessionId(sessionId string, page model.PageQuery) ([]model.TraceEntity, string, error) {
+	clause, args, err := pageClause("started_at", "trace_id", page, []any{sessionId})
+	if err != nil {
+		return nil, "", err
+	}
+	query := "SELECT trace_id, session_id, group_id, parent_id, app_id, name, status, error_message, started_at, ended_at, has_ended, attributes FROM public.ob_trace WHERE session_id = $1" + clause
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	entities := make([]model.TraceEntity, 0)
+	for rows.Next() {
+		var ent model.TraceEntity
+		var attributes []byte
+		err = rows.Scan(
+			&ent.TraceId,
+			&ent.SessionId,
+			&ent.GroupId,
+			&ent.ParentId,
+			&ent.AppId,
+			&ent.Name,
+			&ent.Status,
+			&ent.ErrorMessage,
+			&ent.StartedAt,
+			&ent.EndedAt,
+			&ent.HasEnded,
+			&attributes,
+		)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := json.Unmarshal(attributes, &ent.Attributes); err != nil {
+			return nil, "", err
+		}
+
+		entities = append(entities, ent)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	entities, cursor := nextPage(entities, page.NormalizedLimit(), func(e model.TraceEntity) (int64, string) {
+		return e.StartedAt, e.TraceId
+	})
+
+	return entities, cursor, nil
+}
+
+func (s *service) CreateCrash(data model.NewCrashData) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := createCrash(tx, data); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// createCrash stores the crash, groups it into its issue and marks the
+// session as crashed
+func createCrash(tx *sql.Tx, data model.NewCrashData) error {
+	frames := data.Frames
+	if frames == nil {
+		frames = make([]model.StackFrameDTO, 0)
+	}
+	framesJson, err := json.Marshal(frames)
+	if err != nil {
+		return err
+	}
+
+	issueQuery := `
+	INSERT INTO public.ob_issues AS i
+	(fingerprint, app_id, type, exception_class, message, first_seen, last_seen)
+	VALUES ($1, $2, $3, $4, $5, $6, $6)
+	ON CONFLICT (app_id, fingerprint) DO UPDATE SET
+		first_seen = LEAST(i.first_seen, EXCLUDED.first_seen),
+		last_seen = GREATEST(i.last_seen, EXCLUDED.last_seen)`
+
+	_, err = tx.Exec(issueQuery, data.Fingerprint, data.AppId, model.IssueTypeCrash, data.ExceptionClass, data.Message, data.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	crashQuery := "INSERT INTO public.ob_crashes (id, session_id, app_id, fingerprint, exception_class, message, frames, thread_name, screen, app_version, deobfuscated, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) " + ignoreConflictClause
+
+	_, err = tx.Exec(crashQuery, data.Id, data.SessionId, data.AppId, data.Fingerprint, data.ExceptionClass, data.Message, framesJson, data.ThreadName, data.Screen, data.AppVersion, data.Deobfuscated, data.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.Exec("UPDATE public.ob_sessions SET crashed=1 WHERE id=$1 AND app_id=$2", data.SessionId, data.AppId)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected != 1 {
+		return fmt.Errorf("Expected 1 session to be marked as crashed but was %d", rowsAffected)
+	}
+
+	return nil
+}
+
+func (s *service) GetIssuesByAppId(appId int, issueType string, page model.PageQuery) ([]model.IssueEntity, error) {
+	args := []any{appId}
+	conditions := ""
+	if issueType != "" {
+		args = append(args, issueType)
+		conditions += fmt.Sprintf(" AND i.type = $%d", len(args))
+	}
+	if page.From > 0 {
+		args = append(args, page.From)
+		conditions += fmt.Sprintf(" AND o.created_at >= $%d", len(args))
+	}
+	if page.To > 0 {
+		args = append(args, page.To)
+		conditions += fmt.Sprintf(" AND o.created_at <= $%d", len(args))
+	}
+	release, args := releaseClause("o.app_version", page, args)
+	conditions += release
+	args = append(args, page.NormalizedLimit())
+
+	query := fmt.Sprintf(`
+	SELECT i.fingerprint, i.app_id, i.type, i.exception_class, i.message, i.first_seen, i.last_seen,
+		COUNT(o.id), COUNT(DISTINCT o.session_id)
+	FROM public.ob_issues i
+	JOIN (
+		SELECT id, session_id, app_id, fingerprint, app_version, created_at FROM public.ob_crashes WHERE app_id = $1
+		UNION ALL
+		SELECT id, session_id, app_id, fingerprint, app_version, created_at FROM public.ob_anrs WHERE app_id = $1
+	) o ON o.app_id = i.app_id AND o.fingerprint = i.fingerprint
+	WHERE i.app_id = $1%s
+	GROUP BY i.app_id, i.fingerprint
+	ORDER BY COUNT(DISTINCT o.session_id) DESC, COUNT(o.id) DESC, i.fingerprint
+	LIMIT $%d`, conditions, len(args))
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entities := make([]model.IssueEntity, 0)
+	for rows.Next() {
+		var ent model.IssueEntity
+		err = rows.Scan(
+			&ent.Fingerprint,
+			&ent.AppId,
+			&ent.Type,
+			&ent.ExceptionClass,
+			&ent.Message,
+			&ent.FirstSeen,
+			&ent.LastSeen,
+			&ent.Occurrences,
+			&ent.Sessions,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entities = append(entities, ent)
+	}
+
+	return entities, rows.Err()
+}
+
+func (s *service) GetCrashesByIssue(appId int, fingerprint string, page model.PageQuery) ([]model.CrashEntity, string, error) {
+	filter, args := releaseClause("app_version", page, []any{appId, fingerprint})
+	clause, args, err := pageClause("created_at", "id", page, args)
+	if err != nil {
+		return nil, "", err
+	}
+	query := "SELECT id, session_id, app_id, fingerprint, exception_class, message, frames, thread_name, screen, app_version, deobfuscated, created_at FROM public.ob_crashes WHERE app_id = $1 AND fingerprint = $2" + filter + clause
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	entities := make([]model.CrashEntity, 0)
+	for rows.Next() {
+		var framesJson []byte
+		var ent model.CrashEntity
+		err = rows.Scan(
+			&ent.Id,
+			&ent.SessionId,
+			&ent.AppId,
+			&ent.Fingerprint,
+			&ent.ExceptionClass,
+			&ent.Message,
+			&framesJson,
+			&ent.ThreadName,
+			&ent.Screen,
+			&ent.AppVersion,
+			&ent.Deobfuscated,
+			&ent.CreatedAt,
+		)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := json.Unmarshal(framesJson, &ent.Frames); err != nil {
+			return nil, "", err
+		}
+
+		entities = append(entities, ent)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	entities, cursor := nextPage(entities, page.NormalizedLimit(), func(e model.CrashEntity) (int64, string) {
+		return e.CreatedAt, e.Id
+	})
+
+	return entities, cursor, nil
+}
+
+func (s *service) CreateAnr(data model.NewAnrData) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := createAnr(tx, data); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// createAnr stores the ANR with the installation of its session, and groups
+// it into its issue
+func createAnr(tx *sql.Tx, data model.NewAnrData) error {
+	mainThread := data.MainThread
+	if mainThread == nil {
+		mainThread = make([]model.StackFrameDTO, 0)
+	}
+	mainThreadJson, err := json.Marshal(mainThread)
+	if err != nil {
+		return err
+	}
+	threads := data.Threads
+	if threads == nil {
+		threads = make([]model.ThreadDTO, 0)
+	}
+	threadsJson, err := json.Marshal(threads)
+	if err != nil {
+		return err
+	}
+
+	var installationId string
+	err = tx.QueryRow("SELECT installation_id FROM public.ob_sessions WHERE id = $1 AND app_id = $2", data.SessionId, data.AppId).Scan(&installationId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("Expected session %s to exist", data.SessionId)
+		}
+		return err
+	}
+
+	issueQuery := `
+	INSERT INTO public.ob_issues AS i
+	(fingerprint, app_id, type, exception_class, message, first_seen, last_seen)
+	VALUES ($1, $2, $3, '', '', $4, $4)
+	ON CONFLICT (app_id, fingerprint) DO UPDATE SET
+		first_seen = LEAST(i.first_seen, EXCLUDED.first_seen),
+		last_seen = GREATEST(i.last_seen, EXCLUDED.last_seen)`
+
+	_, err = tx.Exec(issueQuery, data.Fingerprint, data.AppId, model.IssueTypeAnr, data.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	anrQuery := "INSERT INTO public.ob_anrs (id, session_id, installation_id, app_id, fingerprint, duration, main_thread, threads, screen, app_version, deobfuscated, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) " + ignoreConflictClause
+
+	_, err = tx.Exec(anrQuery, data.Id, data.SessionId, installationId, data.AppId, data.Fingerprint, data.Duration, mainThreadJson, threadsJson, data.Screen, data.AppVersion, data.Deobfuscated, data.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) GetAnrsByIssue(appId int, fingerprint string, page model.PageQuery) ([]model.AnrEntity, string, error) {
+	filter, args := releaseClause("app_version", page, []any{appId, fingerprint})
+	clause, args, err := pageClause("created_at", "id", page, args)
+	if err != nil {
+		return nil, "", err
+	}
+	query := "SELECT id, session_id, installation_id, app_id, fingerprint, duration, main_thread, threads, screen, app_version, deobfuscated, created_at FROM public.ob_anrs WHERE app_id = $1 AND fingerprint = $2" + filter + clause
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	entities := make([]model.AnrEntity, 0)
+	for rows.Next() {
+		var mainThreadJson, threadsJson []byte
+		var ent model.AnrEntity
+		err = rows.Scan(
+			&ent.Id,
+			&ent.SessionId,
+			&ent.InstallationId,
+			&ent.AppId,
+			&ent.Fingerprint,
+			&ent.Duration,
+			&mainThreadJson,
+			&threadsJson,
+			&ent.Screen,
+			&ent.AppVersion,
+			&ent.Deobfuscated,
+			&ent.CreatedAt,
+		)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := json.Unmarshal(mainThreadJson, &ent.MainThread); err != nil {
+			return nil, "", err
+		}
+		if err := json.Unmarshal(threadsJson, &ent.Threads); err != nil {
+			return nil, "", err
+		}
+
+		entities = append(entities, ent)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	entities, cursor := nextPage(entities, page.NormalizedLimit(), func(e model.AnrEntity) (int64, string) {
+		return e.CreatedAt, e.Id
+	})
+
+	return entities, cursor, nil
+}
+
+func (s *service) GetAnrRates(appId int, page model.PageQuery) ([]model.AnrRateEntity, error) {
+	args := []any{appId}
+	conditions := ""
+	if page.From > 0 {
+		args = append(args, page.From)
+		conditions += fmt.Sprintf(" AND s.created_at >= $%d", len(args))
+	}
+	if page.To > 0 {
+		args = append(args, page.To)
+		conditions += fmt.Sprintf(" AND s.created_at <= $%d", len(args))
+	}
+	release, args := releaseClause("s.app_version", page, args)
+	conditions += release
+
+	query := fmt.Sprintf(`
+	SELECT s.app_version, COUNT(s.id), COUNT(a.session_id), COALESCE(SUM(a.anrs), 0)
+	FROM public.ob_sessions s
+	LEFT JOIN (
+		SELECT session_id, COUNT(id) AS anrs FROM public.ob_anrs WHERE app_id = $1 GROUP BY session_id
+	) a ON a.session_id = s.id
+	WHERE s.app_id = $1%s
+	GROUP BY s.app_version
+	ORDER BY MAX(s.created_at) DESC, s.app_version`, conditions)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entities := make([]model.AnrRateEntity, 0)
+	for rows.Next() {
+		var ent model.AnrRateEntity
+		if err := rows.Scan(&ent.AppVersion, &ent.Sessions, &ent.AnrSessions, &ent.Anrs); err != nil {
+			return nil, err
+		}
+
+		entities = append(entities, ent)
+	}
+
+	return entities, rows.Err()
+}
+
+func (s *service) CreateMapping(data model.NewMappingData) error {
+	query := `
+	INSERT INTO public.ob_mappings (app_id, version, mapping, created_at)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (app_id, version) DO UPDATE SET
+		mapping = EXCLUDED.mapping,
+		created_at = EXCLUDED.created_at`
+
+	_, err := s.db.Exec(query, data.AppId, data.Version, data.Mapping, data.CreatedAt)
+
+	return err
+}
+
+func (s *service) GetMapping(appId int, version string) (model.MappingEntity, error) {
+	query := "SELECT app_id, version, octet_length(mapping), created_at FROM public.ob_mappings WHERE app_id = $1 AND version = $2"
+
+	var entity model.MappingEntity
+	err := s.db.QueryRow(query, appId, version).Scan(&entity.AppId, &entity.Version, &entity.Size, &entity.CreatedAt)
+
+	return entity, err
+}
+
+func (s *service) GetMappingContent(appId int, version string) ([]byte, error) {
+	query := "SELECT mapping FROM public.ob_mappings WHERE app_id = $1 AND version = $2"
+
+	var content []byte
+	err := s.db.QueryRow(query, appId, version).Scan(&content)
+
+	return content, err
+}
+
+func (s *service) GetMappings(appId int) ([]model.MappingEntity, error) {
+	query := "SELECT app_id, version, octet_length(mapping), created_at FROM public.ob_mappings WHERE app_id = $1 ORDER BY created_at DESC, version"
+
+	rows, err := s.db.Query(query, appId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entities := make([]model.MappingEntity, 0)
+	for rows.Next() {
+		var ent model.MappingEntity
+		if err := rows.Scan(&ent.AppId, &ent.Version, &ent.Size, &ent.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		entities = append(entities, ent)
+	}
+
+	return entities, rows.Err()
+}
+
+func (s *service) CreateIngestionBatch(data model.NewIngestionBatchData) error {
+	query := "INSERT INTO public.ob_ingestion_batches (id, app_id, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $4) " + ignoreConflictClause
+
+	_, err := s.db.Exec(query, data.Id, data.AppId, model.IngestionStatusPending, data.CreatedAt)
+
+	return err
+}
+
+func (s *service) UpdateIngestionBatch(data model.IngestionBatchResultData) error {
+	query := `
+	INSERT INTO public.ob_ingestion_batches AS b
+	(id, app_id, status, attempts, failures, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (id) DO UPDATE SET
+		status = EXCLUDED.status,
+		attempts = EXCLUDED.attempts,
+		failures = EXCLUDED.failures,
+		updated_at = EXCLUDED.updated_at
+	WHERE b.app_id = EXCLUDED.app_id`
+
+	failures := data.Failures
+	if failures == nil {
+		failures = make([]model.IngestionFailure, 0)
+	}
+	failuresJson, err := json.Marshal(failures)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(query, data.Id, data.AppId, data.Status, data.Attempts, failuresJson, data.CreatedAt, data.UpdatedAt)
+
+	return err
+}
+
+func (s *service) GetIngestionBatch(id string) (model.IngestionBatchEntity, error) {
+	query := "SELECT id, app_id, status, attempts, failures, created_at, updated_at FROM public.ob_ingestion_batches WHERE id = $1"
+
+	var failures []byte
+	var entity model.IngestionBatchEntity
+	err := s.db.QueryRow(query, id).Scan(
+		&entity.Id,
+		&entity.AppId,
+		&entity.Status,
+		&entity.Attempts,
+		&failures,
+		&entity.CreatedAt,
+		&entity.UpdatedAt,
+	)
+	if err != nil {
+		return entity, err
+	}
+
+	err = json.Unmarshal(failures, &entity.Failures)
+	return entity, err
+}
+
+func (s *service) CreateMemoryUsage(data model.NewMemoryUsageData) error {
+	query := "INSERT INTO public.ob_memory_usage (id, session_id, installation_id, app_id, free_memory, used_memory, max_memory, total_memory, available_heap_space, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) " + ignoreConflictClause
+
+	_, err := s.db.Exec(query, data.Id, data.SessionId, data.InstallationId, data.AppId, data.FreeMemory, data.UsedMemory, data.MaxMemory, data.TotalMemory, data.AvailableHeapSpace, data.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) GetMemoryUsageById(id string) (model.MemoryUsageEntity, error) {
+	query := "SELECT id, session_id, installation_id, app_id, free_memory, used_memory, max_memory, total_memory, available_heap_space, created_at FROM public.ob_memory_usage WHERE id = $1"
+
+	var ent model.MemoryUsageEntity
+	err := s.db.QueryRow(query, id).Scan(
+		&ent.Id,
+		&ent.SessionId,
+		&ent.InstallationId,
+		&ent.AppId,
+		&ent.FreeMemory,
+		&ent.UsedMemory,
+		&ent.MaxMemory,
+		&ent.TotalMemory,
+		&ent.AvailableHeapSpace,
+		&ent.CreatedAt,
+	)
+
+	return ent, err
+}
+
+func (s *service) GetMemoryUsageBySessionId(id string, page model.PageQuery) ([]model.MemoryUsageEntity, string, error) {
+	clause, args, err := pageClause("created_at", "id", page, []any{id})
+	if err != nil {
+		return nil, "", err
+	}
+	query := "SELECT id, session_id, installation_id, app_id, free_memory, used_memory, max_memory, total_memory, available_heap_space, created_at FROM public.ob_memory_usage WHERE session_id = $1" + clause
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	entities := make([]model.MemoryUsageEntity, 0)
+	for rows.Next() {
+		var ent model.MemoryUsageEntity
+		err = rows.Scan(
+			&ent.Id,
+			&ent.SessionId,
+			&ent.InstallationId,
+			&ent.AppId,
+			&ent.FreeMemory,
+			&ent.UsedMemory,
+			&ent.MaxMemory,
+			&ent.TotalMemory,
+			&ent.AvailableHeapSpace,
+			&ent.CreatedAt,
+		)
+		if err != nil {
+			return nil, "", err
+		}
+
+		entities = append(entities, ent)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	entities, cursor := nextPage(entities, page.NormalizedLimit(), func(e model.MemoryUsageEntity) (int64, string) {
+		return e.CreatedAt, e.Id
+	})
+
+	return entities, cursor, nil
+}
+
+func (s *service) GetMemoryUsageByInstallationId(id string, page model.PageQuery) ([]model.MemoryUsageEntity, string, error) {
+	clause, args, err := pageClause("created_at", "id", page, []any{id})
+	if err != nil {
+		return nil, "", err
+	}
+	query := "SELECT id, session_id, installation_id, app_id, free_memory, used_memory, max_memory, total_memory, available_heap_space, created_at FROM public.ob_memory_usage WHERE installation_id = $1" + clause
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	entities := make([]model.MemoryUsageEntity, 0)
+	for rows.Next() {
+		var ent model.MemoryUsageEntity
+		err = rows.Scan(
+			&ent.Id,
+			&ent.SessionId,
+			&ent.InstallationId,
+			&ent.AppId,
+			&ent.FreeMemory,
+			&ent.UsedMemory,
+			&ent.MaxMemory,
+			&ent.TotalMemory,
+			&ent.AvailableHeapSpace,
+			&ent.CreatedAt,
+		)
+		if err != nil {
+			return nil, "", err
+		}
+
+		entities = append(entities, ent)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	entities, cursor := nextPage(entities, page.NormalizedLimit(), func(e model.MemoryUsageEntity) (int64, string) {
+		return e.CreatedAt, e.Id
+	})
+
+	return entities, cursor, nil
+}
+
+func (s *service) CreateCpuUsage(data model.NewCpuUsageData) error {
+	query := "INSERT INTO public.ob_cpu_usage (id, session_id, installation_id, app_id, usage, user_time, system_time, cores, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) " + ignoreConflictClause
+
+	_, err := s.db.Exec(query, data.Id, data.SessionId, data.InstallationId, data.AppId, data.Usage, data.UserTime, data.SystemTime, data.Cores, data.CreatedAt)
+	return err
+}
+
+func (s *service) GetCpuUsageBySessionId(id string, page model.PageQuery) ([]model.CpuUsageEntity, string, error) {
+	return s.getCpuUsage("session_id", id, page)
+}
+
+func (s *service) GetCpuUsageByInstallationId(id string, page model.PageQuery) ([]model.CpuUsageEntity, string, error) {
+	return s.getCpuUsage("installation_id", id, page)
+}
+
+// getCpuUsage loads a page of the CPU usage whose idColumn is id
+func (s *service) getCpuUsage(idColumn, id string, page model.PageQuery) ([]model.CpuUsageEntity, string, error) {
+	clause, args, err := pageClause("created_at", "id", page, []any{id})
+	if err != nil {
+		return nil, "", err
+	}
+	query := "SELECT id, session_id, installation_id, app_id, usage, user_time, system_time, cores, created_at FROM public.ob_cpu_usage WHERE " + idColumn + " = $1" + clause
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	entities := make([]model.CpuUsageEntity, 0)
+	for rows.Next() {
+		var ent model.CpuUsageEntity
+		err = rows.Scan(
+			&ent.Id,
+			&ent.SessionId,
+			&ent.InstallationId,
+			&ent.AppId,
+			&ent.Usage,
+			&ent.UserTime,
+			&ent.SystemTime,
+			&ent.Cores,
+			&ent.CreatedAt,
+		)
+		if err != nil {
+			return nil, "", err
+		}
+
+		entities = append(entities, ent)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	entities, cursor := nextPage(entities, page.NormalizedLimit(), func(e model.CpuUsageEntity) (int64, string) {
+		return e.CreatedAt, e.Id
+	})
+
+	return entities, cursor, nil
+}
+
+func (s *service) CreateFrameMetrics(data model.NewFrameMetricsData) error {
+	query := "INSERT INTO public.ob_frame_metrics (id, session_id, installation_id, app_id, screen, total_frames, janky_frames, frozen_frames, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) " + ignoreConflictClause
+
+	_, err := s.db.Exec(query, data.Id, data.SessionId, data.InstallationId, data.AppId, data.Screen, data.TotalFrames, data.JankyFrames, data.FrozenFrames, data.CreatedAt)
+	return err
+}
+
+func (s *service) GetFrameMetricsBySessionId(id string, page model.PageQuery) ([]model.FrameMetricsEntity, string, error) {
+	return s.getFrameMetrics("session_id", id, page)
+}
+
+func (s *service) GetFrameMetricsByInstallationId(id string, page model.PageQuery) ([]model.FrameMetricsEntity, string, error) {
+	return s.getFrameMetrics("installation_id", id, page)
+}
+
+// getFrameMetrics loads a page of the frame metrics whose idColumn is id
+func (s *service) getFrameMetrics(idColumn, id string, page model.PageQuery) ([]model.FrameMetricsEntity, string, error) {
+	clause, args, err := pageClause("created_at", "id", page, []any{id})
+	if err != nil {
+		return nil, "", err
+	}
+	query := "SELECT id, session_id, installation_id, app_id, screen, total_frames, janky_frames, frozen_frames, created_at FROM public.ob_frame_metrics WHERE " + idColumn + " = $1" + clause
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	entities := make([]model.FrameMetricsEntity, 0)
+	for rows.Next() {
+		var ent model.FrameMetricsEntity
+		err = rows.Scan(
+			&ent.Id,
+			&ent.SessionId,
+			&ent.InstallationId,
+			&ent.AppId,
+			&ent.Screen,
+			&ent.TotalFrames,
+			&ent.JankyFrames,
+			&ent.FrozenFrames,
+			&ent.CreatedAt,
+		)
+		if err != nil {
+			return nil, "", err
+		}
+
+		entities = append(entities, ent)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	entities, cursor := nextPage(entities, page.NormalizedLimit(), func(e model.FrameMetricsEntity) (int64, string) {
+		return e.CreatedAt, e.Id
+	})
+
+	return entities, cursor, nil
+}
+
+func (s *service) GetFrameSummary(appId int, page model.PageQuery) ([]model.FrameSummaryEntity, error) {
+	args := []any{appId}
+	conditions := ""
+	joins := ""
+	if page.From > 0 {
+		args = append(args, page.From)
+		conditions += fmt.Sprintf(" AND f.created_at >= $%d", len(args))
+	}
+	if page.To > 0 {
+		args = append(args, page.To)
+		conditions += fmt.Sprintf(" AND f.created_at <= $%d", len(args))
+	}
+	if page.Release != "" {
+		joins = " JOIN public.ob_sessions s ON s.id = f.session_id"
+		var release string
+		release, args = releaseClause("s.app_version", page, args)
+		conditions += release
+	}
+	args = append(args, page.NormalizedLimit())
+
+	query := fmt.Sprintf(`
+	SELECT f.screen, COUNT(DISTINCT f.session_id), SUM(f.total_frames), SUM(f.janky_frames), SUM(f.frozen_frames)
+	FROM public.ob_frame_metrics f%s
+	WHERE f.app_id = $1%s
+	GROUP BY f.screen
+	ORDER BY SUM(f.janky_frames) + SUM(f.frozen_frames) DESC, f.screen
+	LIMIT $%d`, joins, conditions, len(args))
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entities := make([]model.FrameSummaryEntity, 0)
+	for rows.Next() {
+		var ent model.FrameSummaryEntity
+		if err := rows.Scan(&ent.Screen, &ent.Sessions, &ent.TotalFrames, &ent.JankyFrames, &ent.FrozenFrames); err != nil {
+			return nil, err
+		}
+
+		entities = append(entities, ent)
+	}
+
+	return entities, rows.Err()
+}
+
+func (s *service) CreateBatteryState(data model.NewBatteryStateData) error {
+	query := "INSERT INTO public.ob_battery_state (id, session_id, installation_id, app_id, level, charging, temperature, thermal_state, power_save, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) " + ignoreConflictClause
+
+	_, err := s.db.Exec(query, data.Id, data.SessionId, data.InstallationId, data.AppId, data.Level, data.Charging, data.Temperature, data.ThermalState, data.PowerSave, data.CreatedAt)
+	return err
+}
+
+func (s *service) GetBatteryStateBySessionId(id string, page model.PageQuery) ([]model.BatteryStateEntity, string, error) {
+	return s.getBatteryState("session_id", id, page)
+}
+
+func (s *service) GetBatteryStateByInstallationId(id string, page model.PageQuery) ([]model.BatteryStateEntity, string, error) {
+	return s.getBatteryState("installation_id", id, page)
+}
+
+// getBatteryState loads a page of the battery states whose idColumn is id
+func (s *service) getBatteryState(idColumn, id string, page model.PageQuery) ([]model.BatteryStateEntity, string, error) {
+	clause, args, err := pageClause("created_at", "id", page, []any{id})
+	if err != nil {
+		return nil, "", err
+	}
+	query := "SELECT id, session_id, installation_id, app_id, level, charging, temperature, thermal_state, power_save, created_at FROM public.ob_battery_state WHERE " + idColumn + " = $1" + clause
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	entities := make([]model.BatteryStateEntity, 0)
+	for rows.Next() {
+		var ent model.BatteryStateEntity
+		err = rows.Scan(
+			&ent.Id,
+			&ent.SessionId,
+			&ent.InstallationId,
+			&ent.AppId,
+			&ent.Level,
+			&ent.Charging,
+			&ent.Temperature,
+			&ent.ThermalState,
+			&ent.PowerSave,
+			&ent.CreatedAt,
+		)
+		if err != nil {
+			return nil, "", err
+		}
+
+		entities = append(entities, ent)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	entities, cursor := nextPage(entities, page.NormalizedLimit(), func(e model.BatteryStateEntity) (int64, string) {
+		return e.CreatedAt, e.Id
+	})
+
+	return entities, cursor, nil
+}
+
+func (s *service) CreateNetworkUsage(data model.NewNetworkUsageData) error {
+	query := "INSERT INTO public.ob_network_usage (id, session_id, installation_id, app_id, connection_type, requests, failed_requests, bytes_sent, bytes_received, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) " + ignoreConflictClause
+
+	_, err := s.db.Exec(query, data.Id, data.SessionId, data.InstallationId, data.AppId, data.ConnectionType, data.Requests, data.FailedRequests, data.BytesSent, data.BytesReceived, data.CreatedAt)
+	return err
+}
+
+func (s *service) GetNetworkUsageBySessionId(id string, page model.PageQuery) ([]model.NetworkUsageEntity, string, error) {
+	return s.getNetworkUsage("session_id", id, page)
+}
+
+func (s *service) GetNetworkUsageByInstallationId(id string, page model.PageQuery) ([]model.NetworkUsageEntity, string, error) {
+	return s.getNetworkUsage("installation_id", id, page)
+}
+
+// getNetworkUsage loads a page of the network usage whose idColumn is id
+func (s *service) getNetworkUsage(idColumn, id string, page model.PageQuery) ([]model.NetworkUsageEntity, string, error) {
+	clause, args, err := pageClause("created_at", "id", page, []any{id})
+	if err != nil {
+		return nil, "", err
+	}
+	query := "SELECT id, session_id, installation_id, app_id, connection_type, requests, failed_requests, bytes_sent, bytes_received, created_at FROM public.ob_network_usage WHERE " + idColumn + " = $1" + clause
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	entities := make([]model.NetworkUsageEntity, 0)
+	for rows.Next() {
+		var ent model.NetworkUsageEntity
+		err = rows.Scan(
+			&ent.Id,
+			&ent.SessionId,
+			&ent.InstallationId,
+			&ent.AppId,
+			&ent.ConnectionType,
+			&ent.Requests,
+			&ent.FailedRequests,
+			&ent.BytesSent,
+			&ent.BytesReceived,
+			&ent.CreatedAt,
+		)
+		if err != nil {
+			return nil, "", err
+		}
+
+		entities = append(entities, ent)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	entities, cursor := nextPage(entities, page.NormalizedLimit(), func(e model.NetworkUsageEntity) (int64, string) {
+		return e.CreatedAt, e.Id
+	})
+
+	return entities, cursor, nil
+}
+
+func (s *service) CreateAppStart(data model.NewAppStartData) error {
+	query := "INSERT INTO public.ob_app_starts (id, session_id, installation_id, app_id, start_type, duration, process_duration, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) " + ignoreConflictClause
+
+	_, err := s.db.Exec(query, data.Id, data.SessionId, data.InstallationId, data.AppId, data.StartType, data.Duration, data.ProcessDuration, data.CreatedAt)
+	return err
+}
+
+func (s *service) GetAppStartsBySessionId(id string, page model.PageQuery) ([]model.AppStartEntity, string, error) {
+	return s.getAppStarts("session_id", id, page)
+}
+
+func (s *service) GetAppStartsByInstallationId(id string, page model.PageQuery) ([]model.AppStartEntity, string, error) {
+	return s.getAppStarts("installation_id", id, page)
+}
+
+// getAppStarts loads a page of the app starts whose idColumn is id
+func (s *service) getAppStarts(idColumn, id string, page model.PageQuery) ([]model.AppStartEntity, string, error) {
+	clause, args, err := pageClause("created_at", "id", page, []any{id})
+	if err != nil {
+		return nil, "", err
+	}
+	query := "SELECT id, session_id, installation_id, app_id, start_type, duration, process_duration, created_at FROM public.ob_app_starts WHERE " + idColumn + " = $1" + clause
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	entities := make([]model.AppStartEntity, 0)
+	for rows.Next() {
+		var ent model.AppStartEntity
+		err = rows.Scan(
+			&ent.Id,
+			&ent.SessionId,
+			&ent.InstallationId,
+			&ent.AppId,
+			&ent.StartType,
+			&ent.Duration,
+			&ent.ProcessDuration,
+			&ent.CreatedAt,
+		)
+		if err != nil {
+			return nil, "", err
+		}
+
+		entities = append(entities, ent)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	entities, cursor := nextPage(entities, page.NormalizedLimit(), func(e model.AppStartEntity) (int64, string) {
+		return e.CreatedAt, e.Id
+	})
+
+	return entities, cursor, nil
+}
+
+func (s *service) GetStartupSummary(appId int, query model.StartupQuery) ([]model.StartupPercentilesEntity, error) {
+	args := []any{appId}
+	conditions := ""
+	joins := ""
+	if query.StartType != "" {
+		args = append(args, query.StartType)
+		conditions += fmt.Sprintf(" AND a.start_type = $%d", len(args))
+	}
+	if query.From > 0 {
+		args = append(args, query.From)
+		conditions += fmt.Sprintf(" AND a.created_at >= $%d", len(args))
+	}
+	if query.To > 0 {
+		args = append(args, query.To)
+		conditions += fmt.Sprintf(" AND a.created_at <= $%d", len(args))
+	}
+	if query.Release != "" || query.GroupBy == model.StartupGroupByAppVersion {
+		joins += " JOIN public.ob_sessions s ON s.id = a.session_id"
+	}
+	if query.Release != "" {
+		args = append(args, query.Release)
+		conditions += fmt.Sprintf(" AND s.app_version = $%d", len(args))
+	}
+
+	bucket, args := bucketExpr("a.created_at", query.Interval, args)
+
+	group := "''"
+	switch query.GroupBy {
+	case "":
+	case model.StartupGroupByAppVersion:
+		group = "s.app_version"
+	default:
+		args = append(args, query.GroupBy)
+		group = fmt.Sprintf("COALESCE(i.data ->> $%d, '')", len(args))
+		joins += " LEFT JOIN public.ob_installations i ON i.id = a.installation_id AND i.app_id = a.app_id"
+	}
+
+	// The phases of a start are only known for cold starts
+	stmt := fmt.Sprintf(`
+	SELECT %s, %s,
+		COUNT(a.id),
+		COUNT(DISTINCT a.session_id),
+		percentile_cont(0.5) WITHIN GROUP (ORDER BY a.duration),
+		percentile_cont(0.9) WITHIN GROUP (ORDER BY a.duration),
+		percentile_cont(0.99) WITHIN GROUP (ORDER BY a.duration),
+		COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY a.process_duration) FILTER (WHERE a.start_type = 'cold'), 0),
+		COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY a.process_duration) FILTER (WHERE a.start_type = 'cold'), 0),
+		COALESCE(percentile_cont(0.99) WITHIN GROUP (ORDER BY a.process_duration) FILTER (WHERE a.start_type = 'cold'), 0),
+		COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY a.duration - a.process_duration) FILTER (WHERE a.start_type = 'cold'), 0),
+		COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY a.duration - a.process_duration) FILTER (WHERE a.start_type = 'cold'), 0),
+		COALESCE(percentile_cont(0.99) WITHIN GROUP (ORDER BY a.duration - a.process_duration) FILTER (WHERE a.start_type = 'cold'), 0)
+	FROM public.ob_app_starts a%s
+	WHERE a.app_id = $1%s
+	GROUP BY 1, 2
+	ORDER BY 1, 3 DESC, 2`, bucket, group, joins, conditions)
+
+	rows, err := s.db.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entities := make([]model.StartupPercentilesEntity, 0)
+	for rows.Next() {
+		var ent model.StartupPercentilesEntity
+		err := rows.Scan(
+			&ent.Bucket,
+			&ent.Group,
+			&ent.Starts,
+			&ent.Sessions,
+			&ent.DurationP50,
+			&ent.DurationP90,
+			&ent.DurationP99,
+			&ent.ProcessP50,
+			&ent.ProcessP90,
+			&ent.ProcessP99,
+			&ent.ApplicationP50,
+			&ent.ApplicationP90,
+			&ent.ApplicationP99,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entities = append(entities, ent)
+	}
+
+	return entities, rows.Err()
+}
+
+func (s *service) CreateNetworkRequest(data model.NewNetworkRequestData) error {
+	return s.CreateNetworkRequests([]model.NewNetworkRequestData{data})
+}
+
+func (s *service) CreateNetworkRequests(data []model.NewNetworkRequestData) error {
+	_, err := s.insertBatch(networkRequestsTable, networkRequestInsertColumns, networkRequestRows(data), ignoreConflictClause)
+
+	return err
+}
+
+var networkRequestInsertColumns = []string{"id", "session_id", "trace_id", "app_id", "host", "url_template", "method", "status_code", "duration", "request_size", "response_size", "failure_reason", "started_at"}
+
+const networkRequestsTable = "public.ob_network_requests"
+
+func networkRequestRows(data []model.NewNetworkRequestData) [][]any {
+	rows := make([][]any, len(data))
+	for i, d := range data {
+		rows[i] = []any{d.Id, d.SessionId, d.TraceId, d.AppId, d.Host, d.UrlTemplate, d.Method, d.StatusCode, d.Duration, d.RequestSize, d.ResponseSize, d.FailureReason, d.StartedAt}
+	}
+	return rows
+}
+
+func (s *service) GetNetworkRequestsBySessionId(id string, page model.PageQuery) ([]model.NetworkRequestEntity, string, error) {
+	clause, args, err := pageClause("started_at", "id", page, []any{id})
+	if err != nil {
+		return nil, "", err
+	}
+	query := "SELECT " + networkRequestColumns + " FROM public.ob_network_requests WHERE session_id = $1" + clause
+
+	entities, err := s.queryNetworkRequests(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	entities, cursor := nextPage(entities, page.NormalizedLimit(), func(e model.NetworkRequestEntity) (int64, string) {
+		return e.StartedAt, e.Id
+	})
+
+	return entities, cursor, nil
+}
+
+func (s *service) GetNetworkRequestsByTraceId(traceId string) ([]model.NetworkRequestEntity, error) {
+	query := "SELECT " + networkRequestColumns + " FROM public.ob_network_requests WHERE trace_id = $1 ORDER BY started_at, id"
+
+	return s.queryNetworkRequests(query, traceId)
+}
+
+const networkRequestColumns = "id, session_id, trace_id, app_id, host, url_template, method, status_code, duration, request_size, response_size, failure_reason, started_at"
+
+func (s *service) queryNetworkRequests(query string, args ...any) ([]model.NetworkRequestEntity, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entities := make([]model.NetworkRequestEntity, 0)
+	for rows.Next() {
+		var ent model.NetworkRequestEntity
+		err = rows.Scan(
+			&ent.Id,
+			&ent.SessionId,
+			&ent.TraceId,
+			&ent.AppId,
+			&ent.Host,
+			&ent.UrlTemplate,
+			&ent.Method,
+			&ent.StatusCode,
+			&ent.Duration,
+			&ent.RequestSize,
+			&ent.ResponseSize,
+			&ent.FailureReason,
+			&ent.StartedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entities = append(entities, ent)
+	}
+
+	return entities, rows.Err()
+}
+
+func (s *service) GetNetworkEndpoints(appId int, query model.NetworkQuery) ([]model.NetworkEndpointEntity, error) {
+	args := []any{appId}
+	conditions := ""
+	joins := ""
+	if query.From > 0 {
+		args = append(args, query.From)
+		conditions += fmt.Sprintf(" AND n.started_at >= $%d", len(args))
+	}
+	if query.To > 0 {
+		args = append(args, query.To)
+		conditions += fmt.Sprintf(" AND n.started_at <= $%d", len(args))
+	}
+	if query.Release != "" {
+		args = append(args, query.Release)
+		conditions += fmt.Sprintf(" AND s.app_version = $%d", len(args))
+		joins += " JOIN public.ob_sessions s ON s.id = n.session_id"
+	}
+	if query.Host != "" {
+		args = append(args, query.Host)
+		conditions += fmt.Sprintf(" AND n.host = $%d", len(args))
+	}
+	if query.Method != "" {
+		args = append(args, query.Method)
+		conditions += fmt.Sprintf(" AND n.method = $%d", len(args))
+	}
+	if query.UrlTemplate != "" {
+		args = append(args, query.UrlTemplate)
+		conditions += fmt.Sprintf(" AND n.url_template = $%d", len(args))
+	}
+
+	bucket, args := bucketExpr("n.started_at", query.Interval, args)
+
+	limit := ""
+	if query.Limit > 0 {
+		args = append(args, query.Limit)
+		limit = fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	stmt := fmt.Sprintf(`
+	SELECT %s, n.method, n.url_template,
+		COUNT(n.id),
+		COUNT(n.id) FILTER (WHERE n.status_code = 0 OR n.status_code >= 400),
+		percentile_cont(0.5) WITHIN GROUP (ORDER BY n.duration),
+		percentile_cont(0.95) WITHIN GROUP (ORDER BY n.duration),
+		percentile_cont(0.99) WITHIN GROUP (ORDER BY n.duration),
+		AVG(n.response_size)::float8
+	FROM public.ob_network_requests n%s
+	WHERE n.app_id = $1%s
+	GROUP BY 1, 2, 3
+	ORDER BY 1, 4 DESC, 2, 3%s`, bucket, joins, conditions, limit)
+
+	rows, err := s.db.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entities := make([]model.NetworkEndpointEntity, 0)
+	for rows.Next() {
+		var ent model.NetworkEndpointEntity
+		err := rows.Scan(
+			&ent.Bucket,
+			&ent.Method,
+			&ent.UrlTemplate,
+			&ent.Requests,
+			&ent.Errors,
+			&ent.DurationP50,
+			&ent.DurationP95,
+			&ent.DurationP99,
+			&ent.AvgResponseSize,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entities = append(entities, ent)
+	}
+
+	return entities, rows.Err()
+}
+
+func (s *service) MarkApiKeyUsed(apiKey string, usedAt int64) error {
+	query := "UPDATE public.ob_api_keys SET last_used_at = $2 WHERE key = $1 AND (last_used_at IS NULL OR last_used_at <= $3)"
+
+	_, err := s.db.Exec(query, apiKey, usedAt, usedAt-model.ApiKeyUsageResolution)
+
+	return err
+}
+
+// refilledTokensExpr is the tokens of a bucket of ob_rate_limits r after
+// refilling it until $4 at $2 tokens per second, up to $3 tokens. Clocks of
+// replicas may be behind updated_at, which does not drain the bucket.
+const refilledTokensExpr = "LEAST($3::DOUBLE PRECISION, r.tokens + GREATEST($4::BIGINT - r.updated_at, 0) * $2::DOUBLE PRECISION / 1000)"
+
+func (s *service) TakeRateLimitToken(key string, rate float64, burst int, now int64) (bool, float64, error) {
+	// The update only happens if the refilled bucket has a token, so
+	// concurrent requests of replicas never take the same token
+	query := fmt.Sprintf(`INSERT INTO public.ob_rate_limits AS r (key, tokens, updated_at, full_at)
+	VALUES ($1, $3::DOUBLE PRECISION - 1, $4::BIGINT, $4::BIGINT + CEIL(1000 / $2::DOUBLE PRECISION)::BIGINT)
+	ON CONFLICT (key) DO UPDATE SET
+		tokens = %[1]s - 1,
+		updated_at = $4::BIGINT,
+		full_at = $4::BIGINT + CEIL(($3::DOUBLE PRECISION - %[1]s + 1) * 1000 / $2::DOUBLE PRECISION)::BIGINT
+	WHERE %[1]s >= 1
+	RETURNING tokens`, refilledTokensExpr)
+
+	var tokens float64
+	err := s.db.QueryRow(query, key, rate, float64(burst), now).Scan(&tokens)
+	if err == nil {
+		return true, tokens, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return false, 0, err
+	}
+
+	query = fmt.Sprintf("SELECT %s FROM public.ob_rate_limits r WHERE r.key = $1", refilledTokensExpr)
+	if err := s.db.QueryRow(query, key, rate, float64(burst), now).Scan(&tokens); err != nil {
+		return false, 0, err
+	}
+	return false, tokens, nil
+}
+
+func (s *service) DeleteFullRateLimits(now int64) error {
+	query := "DELETE FROM public.ob_rate_limits WHERE full_at <= $1"
+
+	_, err := s.db.Exec(query, now)
+
+	return err
+}
+
+func (s *service) AddThrottledRequests(data []model.ThrottledRequestsData) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(data)*4)
+	values := make([]string, 0, len(data))
+	for _, d := range data {
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d)", len(args)+1, len(args)+2, len(args)+3, len(args)+4))
+		args = append(args, d.AppId, d.Bucket, d.Reason, d.Count)
+	}
+
+	query := fmt.Sprintf(`INSERT INTO public.ob_throttled_requests AS t (app_id, bucket, reason, count)
+	VALUES %s
+	ON CONFLICT (app_id, bucket, reason) DO UPDATE SET count = t.count + EXCLUDED.count`, strings.Join(values, ", "))
+
+	_, err := s.db.Exec(query, args...)
+
+	return err
+}
+
+func (s *service) GetThrottledRequests(appId int, query model.ThrottledRequestsQuery) ([]model.ThrottledRequestsEntity, error) {
+	args := []any{appId}
+	conditions := ""
+	if query.From > 0 {
+		args = append(args, query.From)
+		conditions += fmt.Sprintf(" AND t.bucket >= $%d", len(args))
+	}
+	if query.To > 0 {
+		args = append(args, query.To)
+		conditions += fmt.Sprintf(" AND t.bucket <= $%d", len(args))
+	}
+
+	bucket, args := bucketExpr("t.bucket", query.Interval, args)
+
+	stmt := fmt.Sprintf(`
+	SELECT %s, t.reason, SUM(t.count)
+	FROM public.ob_throttled_requests t
+	WHERE t.app_id = $1%s
+	GROUP BY 1, 2
+	ORDER BY 1, 2`, bucket, conditions)
+
+	rows, err := s.db.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entities := make([]model.ThrottledRequestsEntity, 0)
+	for rows.Next() {
+		var ent model.ThrottledRequestsEntity
+		if err := rows.Scan(&ent.Bucket, &ent.Reason, &ent.Count); err != nil {
+			return nil, err
+		}
+		entities = append(entities, ent)
+	}
+
+	return entities, rows.Err()
+}
+
+func (s *service) GetIngestionSettings(appId int) (model.IngestionSettingsEntity, error) {
+	query := `SELECT app_id, monthly_event_quota, monthly_trace_quota, sampling_rules, COALESCE(updated_by, 0), updated_at
+	FROM public.ob_ingestion_settings WHERE app_id = $1`
+
+	var ent model.IngestionSettingsEntity
+	var rules []byte
+	err := s.db.QueryRow(query, appId).Scan(&ent.AppId, &ent.MonthlyEventQuota, &ent.MonthlyTraceQuota, &rules, &ent.UpdatedBy, &ent.UpdatedAt)
+	if err != nil {
+		return ent, err
+	}
+
+	return ent, json.Unmarshal(rules, &ent.SamplingRules)
+}
+
+func (s *service) SetIngestionSettings(data model.IngestionSettingsData) error {
+	if data.SamplingRules == nil {
+		data.SamplingRules = []model.SamplingRule{}
+	}
+	rules, err := json.Marshal(data.SamplingRules)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO public.ob_ingestion_settings (app_id, monthly_event_quota, monthly_trace_quota, sampling_rules, updated_by, updated_at)
+	VALUES ($1, $2, $3, $4, NULLIF($5, 0), $6)
+	ON CONFLICT (app_id) DO UPDATE SET
+		monthly_event_quota = EXCLUDED.monthly_event_quota,
+		monthly_trace_quota = EXCLUDED.monthly_trace_quota,
+		sampling_rules = EXCLUDED.sampling_rules,
+		updated_by = EXCLUDED.updated_by,
+		updated_at = EXCLUDED.updated_at`
+
+	_, err = s.db.Exec(query, data.AppId, data.MonthlyEventQuota, data.MonthlyTraceQuota, rules, data.UpdatedBy, data.UpdatedAt)
+
+	return err
+}
+
+// withinQuota returns how many of requested records fit into the quota, of
+// which used are taken. A quota of 0 is unlimited
+func withinQuota(used, quota int64, requested int) int {
+	if quota <= 0 {
+		return requested
+	}
+	return int(max(0, min(int64(requested), quota-used)))
+}
+
+func (s *service) AddIngestionUsage(data model.IngestionUsageData) (int, int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	// The row is locked until the usage is added, so concurrent batches of
+	// replicas never store more than the quota together
+	query := `INSERT INTO public.ob_ingestion_usage (app_id, month) VALUES ($1, $2)
+	ON CONFLICT (app_id, month) DO UPDATE SET events = ob_ingestion_usage.events
+	RETURNING events, traces`
+
+	var events, traces int64
+	if err := tx.QueryRow(query, data.AppId, data.Month).Scan(&events, &traces); err != nil {
+		return 0, 0, err
+	}
+
+	storedEvents := withinQuota(events, data.EventQuota, data.Events)
+	storedTraces := withinQuota(traces, data.TraceQuota, data.Traces)
+
+	query = `UPDATE public.ob_ingestion_usage SET
+		events = events + $3,
+		traces = traces + $4,
+		sampled_events = sampled_events + $5,
+		sampled_traces = sampled_traces + $6,
+		over_quota_events = over_quota_events + $7,
+		over_quota_traces = over_quota_traces + $8
+	WHERE app_id = $1 AND month = $2`
+
+	_, err = tx.Exec(query, data.AppId, data.Month, storedEvents, storedTraces, data.SampledEvents, data.SampledTraces, data.Events-storedEvents, data.Traces-storedTraces)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return storedEvents, storedTraces, tx.Commit()
+}
+
+func (s *service) ReleaseIngestionUsage(appId int, usage model.IngestionUsageEntity) error {
+	query := `UPDATE public.ob_ingestion_usage SET
+		events = GREATEST(events - $3, 0),
+		traces = GREATEST(traces - $4, 0),
+		sampled_events = GREATEST(sampled_events - $5, 0),
+		sampled_traces = GREATEST(sampled_traces - $6, 0),
+		over_quota_events = GREATEST(over_quota_events - $7, 0),
+		over_quota_traces = GREATEST(over_quota_traces - $8, 0)
+	WHERE app_id = $1 AND month = $2`
+
+	_, err := s.db.Exec(query, appId, usage.Month, usage.Events, usage.Traces, usage.SampledEvents, usage.SampledTraces, usage.OverQuotaEvents, usage.OverQuotaTraces)
+
+	return err
+}
+
+func (s *service) GetIngestionUsage(appId int, month int64) (model.IngestionUsageEntity, error) {
+	query := `SELECT month, events, traces, sampled_events, sampled_traces, over_quota_events, over_quota_traces
+	FROM public.ob_ingestion_usage WHERE app_id = $1 AND month = $2`
+
+	ent := model.IngestionUsageEntity{Month: month}
+	err := s.db.QueryRow(query, appId, month).Scan(&ent.Month, &ent.Events, &ent.Traces, &ent.SampledEvents, &ent.SampledTraces, &ent.OverQuotaEvents, &ent.OverQuotaTraces)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ent, nil
+	}
+
+	return ent, err
+}
 
 // Health checks the health of the database connection by pinging the database.
 // It returns a map with keys indicating various health statistics.
@@ -818,6 +2729,395 @@ func (s *service) Close() error {
 	return s.db.Close()
 }
 
+// insertBatch inserts rows into table in chunks of maxBatchRows, all
+// within one transaction, so either every row is inserted or none are.
+// conflict is appended to every insert statement. Returns the number of
+// rows inserted, leaving out rows the conflict clause ignored or updated
+func (s *service) insertBatch(table string, columns []string, rows [][]any, conflict string) (int, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	inserted, err := insertRows(tx, table, columns, rows, conflict)
+	if err != nil {
+		return 0, err
+	}
+
+	return inserted, tx.Commit()
+}
+
+// insertRows is insertBatch within the transaction tx
+func insertRows(tx *sql.Tx, table string, columns []string, rows [][]any, conflict string) (int, error) {
+	inserted := 0
+	for start := 0; start < len(rows); start += maxBatchRows {
+		chunk := rows[start:min(start+maxBatchRows, len(rows))]
+
+		query, args := multiRowInsert(table, columns, chunk)
+		chunkInserted, rowsAffected, err := insertChunk(tx, query+" "+conflict+" "+insertedClause, args)
+		if err != nil {
+			return 0, err
+		}
+
+		if rowsAffected > len(chunk) {
+			return 0, fmt.Errorf("Expected at most %d rows to be inserted into %s but was %d. Rolling back", len(chunk), table, rowsAffected)
+		}
+		inserted += chunkInserted
+	}
+
+	return inserted, nil
+}
+
+// insertItems inserts the rows of items of kind in the transaction tx. If
+// the rows cannot be inserted, they are inserted one by one to find the item
+// which fails, which is returned as a *model.CollectionItemError. positions
+// are the indices of the items of the rows, nil if they are the same.
+func insertItems(tx *sql.Tx, kind, table string, columns []string, rows [][]any, positions []int, conflict string) (int, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	if _, err := tx.Exec("SAVEPOINT insert_items"); err != nil {
+		return 0, err
+	}
+	inserted, err := insertRows(tx, table, columns, rows, conflict)
+	if err == nil {
+		return inserted, nil
+	}
+
+	if _, rollbackErr := tx.Exec("ROLLBACK TO SAVEPOINT insert_items"); rollbackErr != nil {
+		return 0, &model.CollectionItemError{Kind: kind, Index: -1, Err: err}
+	}
+	for i, row := range rows {
+		if _, rowErr := insertRows(tx, table, columns, [][]any{row}, conflict); rowErr != nil {
+			index := i
+			if positions != nil {
+				index = positions[i]
+			}
+			return 0, &model.CollectionItemError{Kind: kind, Index: index, Err: rowErr}
+		}
+	}
+	return 0, &model.CollectionItemError{Kind: kind, Index: -1, Err: err}
+}
+
+func (s *service) CreateCollection(data model.NewCollectionData) (int, int, error) {
+	events, err := eventRows(data.Events)
+	if err != nil {
+		return 0, 0, err
+	}
+	traces, tracePositions, err := traceRows(data.Traces)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	if data.Session != nil {
+		if err := createSession(tx, *data.Session); err != nil {
+			return 0, 0, &model.CollectionItemError{Kind: model.CollectionSession, Index: -1, Err: err}
+		}
+	}
+
+	insertedEvents, err := insertItems(tx, model.CollectionEvents, eventsTable, eventInsertColumns, events, nil, ignoreConflictClause)
+	if err != nil {
+		return 0, 0, err
+	}
+	insertedTraces, err := insertItems(tx, model.CollectionTraces, tracesTable, traceInsertColumns, traces, tracePositions, traceUpsertClause)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for i, crash := range data.Crashes {
+		if err := createCrash(tx, crash); err != nil {
+			return 0, 0, &model.CollectionItemError{Kind: model.CollectionCrashes, Index: i, Err: err}
+		}
+	}
+	for i, anr := range data.Anrs {
+		if err := createAnr(tx, anr); err != nil {
+			return 0, 0, &model.CollectionItemError{Kind: model.CollectionAnrs, Index: i, Err: err}
+		}
+	}
+
+	_, err = insertItems(tx, model.CollectionNetworkRequests, networkRequestsTable, networkRequestInsertColumns, networkRequestRows(data.NetworkRequests), nil, ignoreConflictClause)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return insertedEvents, insertedTraces, tx.Commit()
+}
+
+// insertChunk runs an insert ending with insertedClause, and returns how
+// many rows it inserted and how many it affected in total
+func insertChunk(tx *sql.Tx, query string, args []any) (int, int, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer rows.Close()
+
+	inserted, affected := 0, 0
+	for rows.Next() {
+		var isInsert bool
+		if err := rows.Scan(&isInsert); err != nil {
+			return 0, 0, err
+		}
+		affected++
+		if isInsert {
+			inserted++
+		}
+	}
+
+	return inserted, affected, rows.Err()
+}
+
+// pageClause returns the time range, keyset condition, ordering and limit
+// of a page, to be appended to a query whose WHERE clause uses args.
+// The records are ordered by time and then id, so the order is stable even
+// for records with the same time. One more row than the limit is selected,
+// which nextPage uses to find out whether there is a next page.
+func pageClause(timeColumn, idColumn string, page model.PageQuery, args []any) (string, []any, error) {
+	var clause strings.Builder
+
+	if page.From > 0 {
+		args = append(args, page.From)
+		fmt.Fprintf(&clause, " AND %s >= $%d", timeColumn, len(args))
+	}
+	if page.To > 0 {
+		args = append(args, page.To)
+		fmt.Fprintf(&clause, " AND %s <= $%d", timeColumn, len(args))
+	}
+	if page.Cursor != "" {
+		cursor, err := model.DecodeCursor(page.Cursor)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, cursor.Time, cursor.Id)
+		fmt.Fprintf(&clause, " AND (%s, %s) > ($%d, $%d)", timeColumn, idColumn, len(args)-1, len(args))
+	}
+
+	args = append(args, page.NormalizedLimit()+1)
+	fmt.Fprintf(&clause, " ORDER BY %s, %s LIMIT $%d", timeColumn, idColumn, len(args))
+
+	return clause.String(), args, nil
+}
+
+func (s *service) GetMemorySummary(appId int, query model.MemorySummaryQuery) ([]model.MemoryPercentilesEntity, error) {
+	conditions, joins, args := memoryConditions(query, []any{appId})
+
+	bucket, args := bucketExpr("m.created_at", query.Interval, args)
+
+	group := "''"
+	if query.GroupBy != "" {
+		args = append(args, query.GroupBy)
+		group = fmt.Sprintf("COALESCE(i.data ->> $%d, '')", len(args))
+		joins += " LEFT JOIN public.ob_installations i ON i.id = m.installation_id AND i.app_id = m.app_id"
+	}
+
+	stmt := fmt.Sprintf(`
+	SELECT %s, %s,
+		COUNT(m.id),
+		COUNT(DISTINCT m.session_id),
+		percentile_cont(0.5) WITHIN GROUP (ORDER BY m.used_memory),
+		percentile_cont(0.9) WITHIN GROUP (ORDER BY m.used_memory),
+		percentile_cont(0.99) WITHIN GROUP (ORDER BY m.used_memory),
+		COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY m.used_memory::float8 / NULLIF(m.max_memory, 0)), 0),
+		COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY m.used_memory::float8 / NULLIF(m.max_memory, 0)), 0),
+		COALESCE(percentile_cont(0.99) WITHIN GROUP (ORDER BY m.used_memory::float8 / NULLIF(m.max_memory, 0)), 0)
+	FROM public.ob_memory_usage m%s
+	WHERE m.app_id = $1%s
+	GROUP BY 1, 2
+	ORDER BY 1, 3 DESC, 2`, bucket, group, joins, conditions)
+
+	rows, err := s.db.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entities := make([]model.MemoryPercentilesEntity, 0)
+	for rows.Next() {
+		var ent model.MemoryPercentilesEntity
+		err := rows.Scan(
+			&ent.Bucket,
+			&ent.Group,
+			&ent.Samples,
+			&ent.Sessions,
+			&ent.UsedP50,
+			&ent.UsedP90,
+			&ent.UsedP99,
+			&ent.RatioP50,
+			&ent.RatioP90,
+			&ent.RatioP99,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entities = append(entities, ent)
+	}
+
+	return entities, rows.Err()
+}
+
+func (s *service) GetMemoryTrends(appId int, query model.MemorySummaryQuery, minSamples, limit int) ([]model.MemoryTrendEntity, error) {
+	conditions, joins, args := memoryConditions(query, []any{appId})
+	args = append(args, minSamples, limit)
+
+	stmt := fmt.Sprintf(`
+	WITH samples AS (
+		SELECT m.session_id, m.installation_id, m.used_memory, m.max_memory, m.created_at,
+			LAG(m.used_memory) OVER (PARTITION BY m.session_id ORDER BY m.created_at, m.id) AS previous_used
+		FROM public.ob_memory_usage m%s
+		WHERE m.app_id = $1%s
+	), trends AS (
+		SELECT session_id,
+			MIN(installation_id) AS installation_id,
+			COUNT(*) AS samples,
+			COUNT(*) FILTER (WHERE used_memory > previous_used) AS increases,
+			(array_agg(used_memory ORDER BY created_at))[1] AS first_used,
+			(array_agg(used_memory ORDER BY created_at DESC))[1] AS last_used,
+			MAX(max_memory) AS max_memory,
+			MIN(created_at) AS first_at,
+			MAX(created_at) AS last_at,
+			COALESCE(regr_slope(used_memory, created_at), 0) AS slope
+		FROM samples
+		GROUP BY session_id
+		HAVING COUNT(*) >= $%d
+	)
+	SELECT session_id, installation_id, samples, increases, first_used, last_used, max_memory, first_at, last_at, slope
+	FROM trends
+	WHERE last_used > first_used
+	ORDER BY (last_used - first_used)::float8 / NULLIF(max_memory, 0) DESC NULLS LAST, session_id
+	LIMIT $%d`, joins, conditions, len(args)-1, len(args))
+
+	rows, err := s.db.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entities := make([]model.MemoryTrendEntity, 0)
+	for rows.Next() {
+		var ent model.MemoryTrendEntity
+		err := rows.Scan(
+			&ent.SessionId,
+			&ent.InstallationId,
+			&ent.Samples,
+			&ent.Increases,
+			&ent.FirstUsed,
+			&ent.LastUsed,
+			&ent.MaxMemory,
+			&ent.FirstAt,
+			&ent.LastAt,
+			&ent.Slope,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entities = append(entities, ent)
+	}
+
+	return entities, rows.Err()
+}
+
+// memoryConditions filters the memory usage 'm' by the time range and
+// release of query
+func memoryConditions(query model.MemorySummaryQuery, args []any) (string, string, []any) {
+	conditions := ""
+	joins := ""
+	if query.From > 0 {
+		args = append(args, query.From)
+		conditions += fmt.Sprintf(" AND m.created_at >= $%d", len(args))
+	}
+	if query.To > 0 {
+		args = append(args, query.To)
+		conditions += fmt.Sprintf(" AND m.created_at <= $%d", len(args))
+	}
+	if query.Release != "" {
+		args = append(args, query.Release)
+		conditions += fmt.Sprintf(" AND s.app_version = $%d", len(args))
+		joins += " JOIN public.ob_sessions s ON s.id = m.session_id"
+	}
+
+	return conditions, joins, args
+}
+
+// attributesJson encodes attributes for a JSONB column, where records
+// without attributes have an empty object
+func attributesJson(attributes map[string]any) ([]byte, error) {
+	if attributes == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(attributes)
+}
+
+// bucketExpr truncates the millisecond timestamps of timeColumn to the start
+// of their interval, aligned to UTC. Without an interval every row is in
+// the same bucket.
+func bucketExpr(timeColumn, interval string, args []any) (string, []any) {
+	if interval == "" {
+		return "0::BIGINT", args
+	}
+
+	args = append(args, interval)
+	return fmt.Sprintf("(EXTRACT(EPOCH FROM date_trunc($%d, to_timestamp(%s / 1000.0) AT TIME ZONE 'UTC')) * 1000)::BIGINT", len(args), timeColumn), args
+}
+
+// releaseClause limits a query to the records of the release of page, if
+// it has one. It must come before the clause of pageClause.
+func releaseClause(versionColumn string, page model.PageQuery, args []any) (string, []any) {
+	if page.Release == "" {
+		return "", args
+	}
+
+	args = append(args, page.Release)
+	return fmt.Sprintf(" AND %s = $%d", versionColumn, len(args)), args
+}
+
+// nextPage trims the extra row selected by pageClause and returns the cursor
+// of the next page, which is empty on the last page
+func nextPage[T any](entities []T, limit int, key func(T) (int64, string)) ([]T, string) {
+	if len(entities) <= limit {
+		return entities, ""
+	}
+
+	entities = entities[:limit]
+	return entities, model.EncodeCursor(key(entities[limit-1]))
+}
+
+func multiRowInsert(table string, columns []string, rows [][]any) (string, []any) {
+	var sb strings.Builder
+	args := make([]any, 0, len(rows)*len(columns))
+
+	fmt.Fprintf(&sb, "INSERT INTO %s (%s) VALUES ", table, strings.Join(columns, ", "))
+	for i, row := range rows {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("(")
+		for j, value := range row {
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			args = append(args, value)
+			fmt.Fprintf(&sb, "$%d", len(args))
+		}
+		sb.WriteString(")")
+	}
+
+	return sb.String(), args
+}
+
 func jsonBuildObjectContent(data map[string]any) string {
 	pairs := make([]string, 0, 0)
 
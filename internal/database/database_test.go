@@ -4,10 +4,16 @@ import (
 	"ObservabilityServer/internal/auth"
 	"ObservabilityServer/internal/model"
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"log"
+	"math"
+	"reflect"
 	"slices"
 	"strings"
 	"testing"
+	"time"
 )
 
 var (
@@ -155,7 +161,7 @@ func TestCreateTeamUserLink(t *testing.T) {
 	err = srv.CreateTeamUserLink(model.NewTeamUserLinkData{
 		TeamId: teamId,
 		UserId: userId,
-		Role:   "User",
+		Role:   model.RoleDeveloper,
 	})
 
 	if err != nil || userId == -1 {
@@ -165,6 +171,23 @@ func TestCreateTeamUserLink(t *testing.T) {
 	if !srv.ValidateTeamUserLink(teamId, userId) {
 		t.Fatalf("Team-User link was not valid, when it should!")
 	}
+
+	role, err := srv.GetTeamUserRole(teamId, userId)
+	if err != nil || role != model.RoleDeveloper {
+		t.Fatalf("Expected role '%s', got '%s' (err = %v)", model.RoleDeveloper, role, err)
+	}
+	if _, err := srv.GetTeamUserRole(teamId, userId+1); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Expected no role of a user outside the team, got err = %v", err)
+	}
+
+	err = srv.CreateTeamUserLink(model.NewTeamUserLinkData{
+		TeamId: teamId,
+		UserId: userId,
+		Role:   "User",
+	})
+	if err == nil {
+		t.Fatalf("Expected a link with an unknown role to be rejected")
+	}
 }
 
 func TestCreateAuthSession(t *testing.T) {
@@ -255,7 +278,7 @@ func TestGetApplicationData(t *testing.T) {
 		Crashed:        true,
 	})
 
-	appData, err := srv.GetApplicationData(appId)
+	appData, err := srv.GetApplicationData(appId, model.PageQuery{}, model.PageQuery{})
 	if err != nil {
 		t.Fatalf("Getting app data failed with err: %v\n", err)
 	}
@@ -278,7 +301,7 @@ func TestGetApplicationData(t *testing.T) {
 	}
 }
 
-func TestValidateApiKey(t *testing.T) {
+func TestGetApiKey(t *testing.T) {
 	srv := New(config)
 
 	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
@@ -299,16 +322,119 @@ func TestValidateApiKey(t *testing.T) {
 		AppId: appId,
 	})
 
-	if !srv.ValidateApiKey(hash) {
-		t.Fatalf("ValidateApiKey returned false with key: %s\n", key)
+	ent, err := srv.GetApiKey(hash, time.Now().UnixMilli())
+	if err != nil {
+		t.Fatalf("GetApiKey failed with key %s: %v\n", key, err)
+	}
+	if ent.AppId != appId {
+		t.Fatalf("Owner ids did not match. expected %d, but got %d\n", appId, ent.AppId)
+	}
+}
+
+func TestApiKeyLifecycle(t *testing.T) {
+	srv := New(config)
+
+	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
+	appId, err := srv.CreateApplication(model.NewApplicationData{
+		Name:   "TestApp",
+		TeamId: teamId,
+	})
+	if err != nil {
+		t.Fatalf("Could not create application: %v\n", err)
+	}
+
+	now := time.Now().UnixMilli()
+	newKey := func(expiresAt int64) (int, string) {
+		key, err := auth.GenerateApiKey()
+		if err != nil {
+			t.Fatalf("Could not generate api key: %v\n", err)
+		}
+		hash := auth.HashApiKey(key)
+		id, err := srv.CreateApiKey(model.NewApiKeyData{
+			Key:       hash,
+			AppId:     appId,
+			Name:      "ci",
+			Scopes:    []string{"ingest:events", "read"},
+			CreatedAt: now,
+			ExpiresAt: expiresAt,
+		})
+		if err != nil {
+			t.Fatalf("Could not create api key: %v\n", err)
+		}
+		return id, hash
+	}
+
+	_, expired := newKey(now - 1)
+	if _, err := srv.GetApiKey(expired, now); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected GetApiKey to return sql.ErrNoRows for an expired key, got %v", err)
+	}
+
+	revokedId, revoked := newKey(0)
+	if err := srv.RevokeApiKey(appId, revokedId, now); err != nil {
+		t.Fatalf("RevokeApiKey failed: %v\n", err)
+	}
+	if _, err := srv.GetApiKey(revoked, now); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected GetApiKey to return sql.ErrNoRows for a revoked key, got %v", err)
+	}
+	if err := srv.RevokeApiKey(appId, revokedId, now); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected revoking a revoked key to return sql.ErrNoRows, got %v", err)
+	}
+
+	oldId, old := newKey(0)
+	if err := srv.MarkApiKeyUsed(old, now); err != nil {
+		t.Fatalf("MarkApiKeyUsed failed: %v\n", err)
+	}
+	rotatedHash := auth.HashApiKey("rotated")
+	rotatedId, previousExpiry, err := srv.RotateApiKey(oldId, model.NewApiKeyData{
+		Key:       rotatedHash,
+		AppId:     appId,
+		CreatedAt: now,
+	}, now+60000)
+	if err != nil {
+		t.Fatalf("RotateApiKey failed: %v\n", err)
+	}
+	if previousExpiry != now+60000 {
+		t.Errorf("Expected rotated key to expire at %d, got %d", now+60000, previousExpiry)
+	}
+	_, oldErr := srv.GetApiKey(old, now)
+	_, rotatedErr := srv.GetApiKey(rotatedHash, now)
+	if oldErr != nil || rotatedErr != nil {
+		t.Errorf("Expected both keys to be valid during the grace period")
+	}
+	if _, _, err := srv.RotateApiKey(revokedId, model.NewApiKeyData{Key: auth.HashApiKey("other"), AppId: appId, CreatedAt: now}, now); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected rotating a revoked key to return sql.ErrNoRows, got %v", err)
+	}
+
+	keys, err := srv.GetApiKeys(appId)
+	if err != nil {
+		t.Fatalf("GetApiKeys failed: %v\n", err)
+	}
+	if len(keys) != 4 {
+		t.Fatalf("Expected 4 keys, got %d", len(keys))
+	}
+	byId := make(map[int]model.ApiKeyEntity)
+	for _, key := range keys {
+		byId[key.Id] = key
+	}
+	if byId[rotatedId].Name != "ci" || !slices.Equal(byId[rotatedId].Scopes, []string{"ingest:events", "read"}) || byId[rotatedId].ExpiresAt != 0 {
+		t.Errorf("Expected new key to keep the name and scopes and not expire, got %+v", byId[rotatedId])
 	}
 
-	id, err := srv.GetAppId(hash)
+	key, err := srv.GetApiKey(rotatedHash, now)
 	if err != nil {
-		t.Fatalf("GetOwnerId failed with error: %v\n", err)
+		t.Fatalf("GetApiKey failed: %v\n", err)
+	}
+	if key.Id != rotatedId || key.AppId != appId || !slices.Equal(key.Scopes, byId[rotatedId].Scopes) {
+		t.Errorf("Expected GetApiKey to return the new key, got %+v", key)
 	}
-	if id != appId {
-		t.Fatalf("Owner ids did not match. expected %d, but got %d\n", appId, id)
+	if byId[oldId].LastUsedAt != now || byId[oldId].ExpiresAt != now+60000 {
+		t.Errorf("Expected rotated key to be used and expire after the grace period, got %+v", byId[oldId])
+	}
+	if byId[revokedId].RevokedAt != now {
+		t.Errorf("Expected revoked key to have revokedAt %d, got %+v", now, byId[revokedId])
+	}
+	if _, err := srv.GetApiKey(old, now+60000); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected GetApiKey to return sql.ErrNoRows after the grace period, got %v", err)
 	}
 }
 
@@ -337,9 +463,10 @@ func TestCreateInstallation(t *testing.T) {
 		t.Fatalf("CreateInstallation failed: %v\n", err)
 	}
 
+	// Replaying the same installation must be treated as a success
 	err = srv.CreateInstallation(data)
-	if err == nil {
-		t.Fatalf("CreateInstallation was expected to fail, but didnt!")
+	if err != nil {
+		t.Fatalf("CreateInstallation replay failed: %v\n", err)
 	}
 }
 
@@ -365,9 +492,35 @@ func TestCreateSession(t *testing.T) {
 		t.Fatalf("CreateSession failed: %v\n", err)
 	}
 
+	// Replaying the same session must be treated as a success
+	err = srv.CreateSession(data)
+	if err != nil {
+		t.Fatalf("CreateSession replay failed: %v\n", err)
+	}
+
+	// A replay reporting a crash marks the session as crashed
+	data.Crashed = true
+	err = srv.CreateSession(data)
+	if err != nil {
+		t.Fatalf("CreateSession crashed replay failed: %v\n", err)
+	}
+	entity, err := srv.GetSession(data.Id)
+	if err != nil {
+		t.Fatalf("GetSession failed: %v\n", err)
+	}
+	if !entity.Crashed {
+		t.Fatalf("Expected session to be marked as crashed after replay")
+	}
+
+	// The id cannot be claimed by a session of another app
+	otherAppId, _ := srv.CreateApplication(model.NewApplicationData{
+		Name:   "OtherTestApp",
+		TeamId: teamId,
+	})
+	data.AppId = otherAppId
 	err = srv.CreateSession(data)
 	if err == nil {
-		t.Fatalf("CreateSession was expected to fail, but didnt!")
+		t.Fatalf("CreateSession for another app was expected to fail, but didnt!")
 	}
 }
 
@@ -427,7 +580,7 @@ func TestCreateEvent(t *testing.T) {
 		CreatedAt:      2,
 	}
 
-	err := srv.CreateEvent(eventData)
+	_, err := srv.CreateEvent(eventData)
 	if err != nil {
 		t.Fatalf("CreateEvent failed: %v\n", err)
 	}
@@ -461,12 +614,12 @@ func TestGetEventsBySessionId(t *testing.T) {
 		CreatedAt:      2,
 	}
 
-	err := srv.CreateEvent(eventData1)
+	_, err := srv.CreateEvent(eventData1)
 	if err != nil {
 		t.Fatalf("CreateEvent #1 failed: %v\n", err)
 	}
 
-	entities, err := srv.GetEventsBySessionId(sessionData.Id)
+	entities, _, err := srv.GetEventsBySessionId(sessionData.Id, model.PageQuery{})
 	if err != nil {
 		t.Fatalf("GetEventsBySessionId failed: %v\n", err)
 	}
@@ -481,6 +634,261 @@ func TestGetEventsBySessionId(t *testing.T) {
 	}
 }
 
+// getAllEvents follows the cursors of GetEventsBySessionId until the last page
+func getAllEvents(t *testing.T, srv Service, sessionId string, page model.PageQuery) []model.EventEntity {
+	entities := make([]model.EventEntity, 0)
+	for {
+		events, next, err := srv.GetEventsBySessionId(sessionId, page)
+		if err != nil {
+			t.Fatalf("GetEventsBySessionId failed: %v\n", err)
+		}
+		entities = append(entities, events...)
+		if next == "" {
+			return entities
+		}
+		page.Cursor = next
+	}
+}
+
+func TestGetEventsBySessionIdPaginated(t *testing.T) {
+	srv := New(config)
+
+	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
+	appId, _ := srv.CreateApplication(model.NewApplicationData{
+		Name:   "TestApp",
+		TeamId: teamId,
+	})
+
+	sessionData := model.NewSessionData{
+		Id:             "TestSessionPaginatedEvents",
+		InstallationId: "InstallationIdForTestSession123",
+		AppId:          appId,
+		CreatedAt:      1,
+		Crashed:        false,
+	}
+	_ = srv.CreateSession(sessionData)
+
+	// Several events share a timestamp, so the order has to fall back to the id
+	events := make([]model.NewEventData, 7)
+	for i := range events {
+		events[i] = model.NewEventData{
+			Id:             fmt.Sprintf("TestPageEvent%d", len(events)-i),
+			SessionId:      sessionData.Id,
+			AppId:          appId,
+			Type:           "TestEvent",
+			SerializedData: "{}",
+			CreatedAt:      int64(10 + i/3),
+		}
+	}
+	if _, err := srv.CreateEvents(events); err != nil {
+		t.Fatalf("CreateEvents failed: %v\n", err)
+	}
+
+	first, next, err := srv.GetEventsBySessionId(sessionData.Id, model.PageQuery{Limit: 2})
+	if err != nil {
+		t.Fatalf("GetEventsBySessionId failed: %v\n", err)
+	}
+	if len(first) != 2 || next == "" {
+		t.Fatalf("Got %d events and cursor '%s' on first page, but expected 2 and a cursor\n", len(first), next)
+	}
+
+	entities := getAllEvents(t, srv, sessionData.Id, model.PageQuery{Limit: 2})
+	if len(entities) != len(events) {
+		t.Fatalf("Got %d events across all pages, but expected %d\n", len(entities), len(events))
+	}
+	seen := make(map[string]bool)
+	for i, ent := range entities {
+		if seen[ent.Id] {
+			t.Errorf("Event %s was returned twice\n", ent.Id)
+		}
+		seen[ent.Id] = true
+
+		if i > 0 {
+			prev := entities[i-1]
+			if prev.CreatedAt > ent.CreatedAt || (prev.CreatedAt == ent.CreatedAt && prev.Id > ent.Id) {
+				t.Errorf("Events are not ordered: %v before %v\n", prev, ent)
+			}
+		}
+	}
+
+	filtered := getAllEvents(t, srv, sessionData.Id, model.PageQuery{From: 11, To: 11})
+	if len(filtered) != 3 {
+		t.Errorf("Got %d events between 11 and 11, but expected 3\n", len(filtered))
+	}
+
+	_, _, err = srv.GetEventsBySessionId(sessionData.Id, model.PageQuery{Cursor: "not a cursor"})
+	if err == nil {
+		t.Errorf("Expected malformed cursor to fail\n")
+	}
+}
+
+func TestCreateEvents(t *testing.T) {
+	srv := New(config)
+
+	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
+	appId, _ := srv.CreateApplication(model.NewApplicationData{
+		Name:   "TestApp",
+		TeamId: teamId,
+	})
+
+	sessionData := model.NewSessionData{
+		Id:             "TestSessionBatchEvents",
+		InstallationId: "InstallationIdForTestSession123",
+		AppId:          appId,
+		CreatedAt:      1,
+		Crashed:        false,
+	}
+
+	_ = srv.CreateSession(sessionData)
+
+	events := make([]model.NewEventData, maxBatchRows+5)
+	for i := range events {
+		events[i] = model.NewEventData{
+			Id:             fmt.Sprintf("TestBatchEvent%d", i),
+			SessionId:      sessionData.Id,
+			AppId:          appId,
+			Type:           "TestEvent",
+			SerializedData: "{}",
+			CreatedAt:      int64(i),
+		}
+	}
+
+	inserted, err := srv.CreateEvents(events)
+	if err != nil {
+		t.Fatalf("CreateEvents failed: %v\n", err)
+	}
+	if inserted != len(events) {
+		t.Errorf("CreateEvents inserted %d events, expected %d\n", inserted, len(events))
+	}
+
+	entities := getAllEvents(t, srv, sessionData.Id, model.PageQuery{})
+	if len(entities) != len(events) {
+		t.Fatalf("Got %d event entities, but expected %d\n", len(entities), len(events))
+	}
+
+	// A replayed batch only inserts the events not already stored
+	replay := []model.NewEventData{
+		{Id: "TestBatchEventNew", SessionId: sessionData.Id, AppId: appId, Type: "TestEvent", CreatedAt: 1},
+		events[0],
+	}
+	inserted, err = srv.CreateEvents(replay)
+	if err != nil {
+		t.Fatalf("CreateEvents replay failed: %v\n", err)
+	}
+	if inserted != 1 {
+		t.Errorf("CreateEvents replay inserted %d events, expected 1\n", inserted)
+	}
+
+	entities = getAllEvents(t, srv, sessionData.Id, model.PageQuery{})
+	if len(entities) != len(events)+1 {
+		t.Fatalf("Got %d event entities after replay, but expected %d\n", len(entities), len(events)+1)
+	}
+}
+
+func TestSearchEvents(t *testing.T) {
+	srv := New(config)
+
+	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
+	appId, _ := srv.CreateApplication(model.NewApplicationData{
+		Name:   "TestApp",
+		TeamId: teamId,
+	})
+
+	sessions := []model.NewSessionData{
+		{Id: "TestSearchSession1", InstallationId: "TestSearchInstallation", AppVersion: "1.0.0"},
+		{Id: "TestSearchSession2", InstallationId: "TestSearchInstallation", AppVersion: "1.1.0"},
+	}
+	for _, data := range sessions {
+		data.AppId = appId
+		data.CreatedAt = 1
+		if err := srv.CreateSession(data); err != nil {
+			t.Fatalf("CreateSession failed: %v\n", err)
+		}
+	}
+
+	events := []model.NewEventData{
+		{Id: "TestSearchEvent1", SessionId: "TestSearchSession1", Type: "click", Attributes: map[string]any{"screen": "Checkout", "button": "pay", "amount": 12.5}, CreatedAt: 10},
+		{Id: "TestSearchEvent2", SessionId: "TestSearchSession2", Type: "click", Attributes: map[string]any{"screen": "Checkout", "button": "cancel"}, CreatedAt: 20},
+		{Id: "TestSearchEvent3", SessionId: "TestSearchSession2", Type: "click", Attributes: map[string]any{"screen": "Checkout", "button": "pay", "amount": "12.5"}, CreatedAt: 30},
+		{Id: "TestSearchEvent4", SessionId: "TestSearchSession2", Type: "view", Attributes: map[string]any{"screen": "Checkout"}, CreatedAt: 40},
+		{Id: "TestSearchEvent5", SessionId: "TestSearchSession2", Type: "click", CreatedAt: 50},
+	}
+	for i := range events {
+		events[i].AppId = appId
+		events[i].SerializedData = "{}"
+	}
+	if _, err := srv.CreateEvents(events); err != nil {
+		t.Fatalf("CreateEvents failed: %v\n", err)
+	}
+
+	tests := []struct {
+		name     string
+		search   model.EventSearchQuery
+		page     model.PageQuery
+		expected []string
+	}{
+		{
+			name:     "type",
+			search:   model.EventSearchQuery{Type: "view"},
+			expected: []string{"TestSearchEvent4"},
+		},
+		{
+			name: "type and attributes",
+			search: model.EventSearchQuery{Type: "click", Predicates: []model.AttributePredicate{
+				{Key: "screen", Values: []any{"Checkout"}},
+				{Key: "button", Values: []any{"pay"}},
+			}},
+			expected: []string{"TestSearchEvent1", "TestSearchEvent3"},
+		},
+		{
+			name: "number or string",
+			search: model.EventSearchQuery{Predicates: []model.AttributePredicate{
+				{Key: "amount", Values: []any{"12.5", 12.5}},
+			}},
+			expected: []string{"TestSearchEvent1", "TestSearchEvent3"},
+		},
+		{
+			name: "number only",
+			search: model.EventSearchQuery{Predicates: []model.AttributePredicate{
+				{Key: "amount", Values: []any{12.5}},
+			}},
+			expected: []string{"TestSearchEvent1"},
+		},
+		{
+			name: "release",
+			search: model.EventSearchQuery{Predicates: []model.AttributePredicate{
+				{Key: "button", Values: []any{"pay"}},
+			}},
+			page:     model.PageQuery{Release: "1.1.0"},
+			expected: []string{"TestSearchEvent3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entities, _, err := srv.SearchEvents(appId, tt.search, tt.page)
+			if err != nil {
+				t.Fatalf("SearchEvents failed: %v\n", err)
+			}
+			ids := make([]string, len(entities))
+			for i, ent := range entities {
+				ids[i] = ent.Id
+			}
+			if !slices.Equal(ids, tt.expected) {
+				t.Errorf("Got events %v, but expected %v\n", ids, tt.expected)
+			}
+		})
+	}
+
+	entities, _, err := srv.GetEventsBySessionId("TestSearchSession1", model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetEventsBySessionId failed: %v\n", err)
+	}
+	if len(entities) != 1 || entities[0].Attributes["button"] != "pay" || entities[0].Attributes["amount"] != 12.5 {
+		t.Errorf("Got unexpected attributes %+v\n", entities)
+	}
+}
+
 func TestCreateTrace(t *testing.T) {
 	srv := New(config)
 
@@ -514,10 +922,88 @@ func TestCreateTrace(t *testing.T) {
 		HasEnded:     true,
 	}
 
-	err := srv.CreateTrace(traceData)
+	inserted, err := srv.CreateTrace(traceData)
+	if err != nil || !inserted {
+		t.Fatalf("CreateTrace returned %v, %v, expected the trace to be inserted\n", inserted, err)
+	}
+
+	inserted, err = srv.CreateTrace(traceData)
+	if err != nil || inserted {
+		t.Fatalf("CreateTrace replay returned %v, %v, expected nothing to be inserted\n", inserted, err)
+	}
+}
+
+func TestCreateTraceCompletesUnfinishedTrace(t *testing.T) {
+	srv := New(config)
+
+	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
+	appId, _ := srv.CreateApplication(model.NewApplicationData{
+		Name:   "TestApp",
+		TeamId: teamId,
+	})
+
+	sessionData := model.NewSessionData{
+		Id:             "TestSessionUnfinishedTrace",
+		InstallationId: "InstallationIdForTestSession123",
+		AppId:          appId,
+		CreatedAt:      1,
+		Crashed:        false,
+	}
+
+	_ = srv.CreateSession(sessionData)
+
+	traceData := model.NewTraceData{
+		TraceId:    "TestUnfinishedTrace",
+		SessionId:  sessionData.Id,
+		GroupId:    "TestGroup",
+		AppId:      appId,
+		Name:       "TraceTest",
+		Status:     "Ok",
+		StartedAt:  2,
+		HasEnded:   false,
+		Attributes: map[string]any{"screen": "Home"},
+	}
+
+	_, err := srv.CreateTrace(traceData)
 	if err != nil {
 		t.Fatalf("CreateTrace failed: %v\n", err)
 	}
+
+	traceData.HasEnded = true
+	traceData.EndedAt = 8
+	traceData.Status = "Error"
+	traceData.ErrorMessage = "Failed"
+	traceData.Attributes = map[string]any{"items": 3.0}
+	inserted, err := srv.CreateTraces([]model.NewTraceData{traceData})
+	if err != nil {
+		t.Fatalf("CreateTraces failed: %v\n", err)
+	}
+	if inserted != 0 {
+		t.Errorf("CreateTraces inserted %d traces when ending a stored trace, expected 0\n", inserted)
+	}
+
+	// A later unfinished replay must not reopen the trace
+	traceData.HasEnded = false
+	traceData.EndedAt = 0
+	_, err = srv.CreateTrace(traceData)
+	if err != nil {
+		t.Fatalf("CreateTrace replay failed: %v\n", err)
+	}
+
+	entities, _, err := srv.GetTracesBySessionId(sessionData.Id, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetTracesBySessionId failed: %v\n", err)
+	}
+	if len(entities) != 1 {
+		t.Fatalf("Got %d trace entities, but expected 1\n", len(entities))
+	}
+	if !entities[0].HasEnded || entities[0].EndedAt != 8 || entities[0].Status != "Error" || entities[0].ErrorMessage != "Failed" {
+		t.Errorf("Trace was not completed by ended record. Got: %v\n", entities[0])
+	}
+	// Attributes known when the trace started are kept
+	if entities[0].Attributes["screen"] != "Home" || entities[0].Attributes["items"] != 3.0 {
+		t.Errorf("Attributes of the records were not merged. Got: %v\n", entities[0].Attributes)
+	}
 }
 
 func TestGetTracesBySessionId(t *testing.T) {
@@ -553,12 +1039,12 @@ func TestGetTracesBySessionId(t *testing.T) {
 		HasEnded:     true,
 	}
 
-	err := srv.CreateTrace(traceData1)
+	_, err := srv.CreateTrace(traceData1)
 	if err != nil {
 		t.Fatalf("CreateTrace failed: %v\n", err)
 	}
 
-	entities, err := srv.GetTracesBySessionId(sessionData.Id)
+	entities, _, err := srv.GetTracesBySessionId(sessionData.Id, model.PageQuery{})
 	if err != nil {
 		t.Fatalf("GetTracesBySessionId failed: %v\n", err)
 	}
@@ -573,6 +1059,122 @@ func TestGetTracesBySessionId(t *testing.T) {
 	}
 }
 
+func TestCreateCollection(t *testing.T) {
+	srv := New(config)
+
+	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
+	appId, _ := srv.CreateApplication(model.NewApplicationData{
+		Name:   "TestApp",
+		TeamId: teamId,
+	})
+
+	session := model.NewSessionData{
+		Id:             "TestSessionCollection",
+		InstallationId: "InstallationIdForTestSession123",
+		AppId:          appId,
+		CreatedAt:      1,
+	}
+	events := []model.NewEventData{
+		{Id: "TestCollectionEvent1", SessionId: session.Id, AppId: appId, Type: "TestEvent", CreatedAt: 2},
+		{Id: "TestCollectionEvent2", SessionId: session.Id, AppId: appId, Type: "TestEvent", CreatedAt: 3},
+	}
+	traces := []model.NewTraceData{
+		{TraceId: "TestCollectionTrace1", SessionId: session.Id, GroupId: "TestGroup", AppId: appId, Name: "TraceTest", Status: "Ok", StartedAt: 2},
+		{TraceId: "TestCollectionTrace1", SessionId: session.Id, GroupId: "TestGroup", AppId: appId, Name: "TraceTest", Status: "Ok", StartedAt: 2, EndedAt: 4, HasEnded: true},
+		{TraceId: "TestCollectionTrace2", SessionId: "TestSessionUnknown", GroupId: "TestGroup", AppId: appId, Name: "TraceTest", Status: "Ok", StartedAt: 2},
+	}
+
+	_, _, err := srv.CreateCollection(model.NewCollectionData{Session: &session, Events: events, Traces: traces})
+	var itemErr *model.CollectionItemError
+	if !errors.As(err, &itemErr) || itemErr.Kind != model.CollectionTraces || itemErr.Index != 2 {
+		t.Fatalf("Expected CreateCollection to fail with traces[2], got %v\n", err)
+	}
+	if _, err := srv.GetSession(session.Id); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected the session to be rolled back, got %v\n", err)
+	}
+
+	insertedEvents, insertedTraces, err := srv.CreateCollection(model.NewCollectionData{Session: &session, Events: events, Traces: traces[:2]})
+	if err != nil {
+		t.Fatalf("CreateCollection failed: %v\n", err)
+	}
+	if insertedEvents != 2 || insertedTraces != 1 {
+		t.Errorf("CreateCollection inserted %d events and %d traces, expected 2 and 1\n", insertedEvents, insertedTraces)
+	}
+	if entities := getAllEvents(t, srv, session.Id, model.PageQuery{}); len(entities) != 2 {
+		t.Errorf("Got %d event entities, but expected 2\n", len(entities))
+	}
+}
+
+func TestCreateTraces(t *testing.T) {
+	srv := New(config)
+
+	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
+	appId, _ := srv.CreateApplication(model.NewApplicationData{
+		Name:   "TestApp",
+		TeamId: teamId,
+	})
+
+	sessionData := model.NewSessionData{
+		Id:             "TestSessionBatchTraces",
+		InstallationId: "InstallationIdForTestSession123",
+		AppId:          appId,
+		CreatedAt:      1,
+		Crashed:        false,
+	}
+
+	_ = srv.CreateSession(sessionData)
+
+	traces := []model.NewTraceData{
+		{
+			TraceId:   "TestBatchTrace1",
+			SessionId: sessionData.Id,
+			GroupId:   "TestBatchGroup",
+			AppId:     appId,
+			Name:      "Root",
+			Status:    "Ok",
+			StartedAt: 2,
+			EndedAt:   10,
+			HasEnded:  true,
+		},
+		{
+			TraceId:      "TestBatchTrace2",
+			SessionId:    sessionData.Id,
+			GroupId:      "TestBatchGroup",
+			ParentId:     "TestBatchTrace1",
+			AppId:        appId,
+			Name:         "Child",
+			Status:       "Error",
+			ErrorMessage: "Failed",
+			StartedAt:    3,
+		},
+	}
+
+	_, err := srv.CreateTraces(traces)
+	if err != nil {
+		t.Fatalf("CreateTraces failed: %v\n", err)
+	}
+
+	entities, _, err := srv.GetTracesBySessionId(sessionData.Id, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetTracesBySessionId failed: %v\n", err)
+	}
+	if len(entities) != len(traces) {
+		t.Fatalf("Got %d trace entities, but expected %d\n", len(entities), len(traces))
+	}
+}
+
+func TestMultiRowInsert(t *testing.T) {
+	query, args := multiRowInsert("public.test", []string{"a", "b"}, [][]any{{1, "x"}, {2, "y"}})
+
+	expected := "INSERT INTO public.test (a, b) VALUES ($1, $2), ($3, $4)"
+	if query != expected {
+		t.Errorf("Got query '%s', but expected '%s'\n", query, expected)
+	}
+	if len(args) != 4 || args[0] != 1 || args[3] != "y" {
+		t.Errorf("Got unexpected args: %v\n", args)
+	}
+}
+
 func TestCreateMemoryUsage(t *testing.T) {
 	srv := New(config)
 
@@ -629,6 +1231,947 @@ func TestCreateMemoryUsage(t *testing.T) {
 	}
 }
 
+func TestGetMemorySummary(t *testing.T) {
+	srv := New(config)
+
+	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
+	appId, _ := srv.CreateApplication(model.NewApplicationData{
+		Name:   "TestApp",
+		TeamId: teamId,
+	})
+
+	installations := map[string]string{"TestMemoryPixel": "Pixel 7", "TestMemoryGalaxy": "Galaxy S23"}
+	for id, deviceModel := range installations {
+		err := srv.CreateInstallation(model.NewInstallationData{
+			Id:        id,
+			AppId:     appId,
+			Type:      "android",
+			Data:      map[string]any{"model": deviceModel},
+			CreatedAt: 1,
+		})
+		if err != nil {
+			t.Fatalf("CreateInstallation failed: %v\n", err)
+		}
+	}
+
+	sessions := map[string]string{"TestMemorySessionPixel": "TestMemoryPixel", "TestMemorySessionGalaxy": "TestMemoryGalaxy"}
+	for id, installationId := range sessions {
+		err := srv.CreateSession(model.NewSessionData{Id: id, InstallationId: installationId, AppId: appId, CreatedAt: 1})
+		if err != nil {
+			t.Fatalf("CreateSession failed: %v\n", err)
+		}
+	}
+
+	// The Pixel session grows steadily, while the Galaxy session goes up and down
+	samples := map[string][]int64{
+		"TestMemorySessionPixel":  {10, 20, 30, 40},
+		"TestMemorySessionGalaxy": {50, 40, 50, 40},
+	}
+	for sessionId, used := range samples {
+		for i, usedMemory := range used {
+			err := srv.CreateMemoryUsage(model.NewMemoryUsageData{
+				Id:             fmt.Sprintf("%s%d", sessionId, i),
+				SessionId:      sessionId,
+				InstallationId: sessions[sessionId],
+				AppId:          appId,
+				UsedMemory:     usedMemory,
+				MaxMemory:      100,
+				CreatedAt:      int64(i+1) * 1000,
+			})
+			if err != nil {
+				t.Fatalf("CreateMemoryUsage failed: %v\n", err)
+			}
+		}
+	}
+
+	t.Run("summary", func(t *testing.T) {
+		entities, err := srv.GetMemorySummary(appId, model.MemorySummaryQuery{})
+		if err != nil {
+			t.Fatalf("GetMemorySummary failed: %v\n", err)
+		}
+		if len(entities) != 1 || entities[0].Samples != 8 || entities[0].Sessions != 2 || entities[0].UsedP50 != 40 {
+			t.Errorf("Got unexpected summary %+v\n", entities)
+		}
+	})
+
+	t.Run("by model", func(t *testing.T) {
+		entities, err := srv.GetMemorySummary(appId, model.MemorySummaryQuery{GroupBy: "model"})
+		if err != nil {
+			t.Fatalf("GetMemorySummary failed: %v\n", err)
+		}
+		if len(entities) != 2 {
+			t.Fatalf("Expected 2 groups, but got %+v\n", entities)
+		}
+		if entities[0].Group != "Galaxy S23" || entities[0].UsedP50 != 45 || math.Abs(entities[0].RatioP50-0.45) > 1e-9 {
+			t.Errorf("Got unexpected summary of Galaxy S23 %+v\n", entities[0])
+		}
+		if entities[1].Group != "Pixel 7" || entities[1].UsedP50 != 25 || math.Abs(entities[1].RatioP50-0.25) > 1e-9 {
+			t.Errorf("Got unexpected summary of Pixel 7 %+v\n", entities[1])
+		}
+	})
+
+	t.Run("trends", func(t *testing.T) {
+		entities, err := srv.GetMemoryTrends(appId, model.MemorySummaryQuery{}, 3, 10)
+		if err != nil {
+			t.Fatalf("GetMemoryTrends failed: %v\n", err)
+		}
+		if len(entities) != 1 {
+			t.Fatalf("Expected only the growing session, but got %+v\n", entities)
+		}
+		trend := entities[0]
+		if trend.SessionId != "TestMemorySessionPixel" || trend.Samples != 4 || trend.Increases != 3 ||
+			trend.FirstUsed != 10 || trend.LastUsed != 40 || trend.MaxMemory != 100 || trend.Slope <= 0 {
+			t.Errorf("Got unexpected trend %+v\n", trend)
+		}
+	})
+
+	t.Run("trends time range", func(t *testing.T) {
+		entities, err := srv.GetMemoryTrends(appId, model.MemorySummaryQuery{From: 3000}, 3, 10)
+		if err != nil {
+			t.Fatalf("GetMemoryTrends failed: %v\n", err)
+		}
+		if len(entities) != 0 {
+			t.Errorf("Expected no sessions with enough samples, but got %+v\n", entities)
+		}
+	})
+}
+
+func TestResourceMetrics(t *testing.T) {
+	srv := New(config)
+
+	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
+	appId, _ := srv.CreateApplication(model.NewApplicationData{
+		Name:   "TestApp",
+		TeamId: teamId,
+	})
+
+	sessionId := "TestResourceSession"
+	installationId := "TestResourceInstallation"
+	err := srv.CreateSession(model.NewSessionData{Id: sessionId, InstallationId: installationId, AppId: appId, CreatedAt: 1})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v\n", err)
+	}
+
+	err = srv.CreateCpuUsage(model.NewCpuUsageData{
+		Id: "TestCpuUsage", SessionId: sessionId, InstallationId: installationId, AppId: appId,
+		Usage: 42.5, UserTime: 120, SystemTime: 30, Cores: 8, CreatedAt: 1000,
+	})
+	if err != nil {
+		t.Fatalf("CreateCpuUsage failed: %v\n", err)
+	}
+	cpu, _, err := srv.GetCpuUsageBySessionId(sessionId, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetCpuUsageBySessionId failed: %v\n", err)
+	}
+	if len(cpu) != 1 || cpu[0].Usage != 42.5 || cpu[0].Cores != 8 || cpu[0].UserTime != 120 {
+		t.Errorf("Got unexpected CPU usage %+v\n", cpu)
+	}
+
+	frames := []model.NewFrameMetricsData{
+		{Id: "TestFrames1", Screen: "Checkout", TotalFrames: 100, JankyFrames: 10, FrozenFrames: 2, CreatedAt: 1000},
+		{Id: "TestFrames2", Screen: "Checkout", TotalFrames: 100, JankyFrames: 20, CreatedAt: 2000},
+		{Id: "TestFrames3", Screen: "Home", TotalFrames: 300, JankyFrames: 3, CreatedAt: 3000},
+	}
+	for _, data := range frames {
+		data.SessionId = sessionId
+		data.InstallationId = installationId
+		data.AppId = appId
+		if err := srv.CreateFrameMetrics(data); err != nil {
+			t.Fatalf("CreateFrameMetrics failed: %v\n", err)
+		}
+	}
+	frameMetrics, _, err := srv.GetFrameMetricsByInstallationId(installationId, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetFrameMetricsByInstallationId failed: %v\n", err)
+	}
+	if len(frameMetrics) != 3 || frameMetrics[0].Screen != "Checkout" || frameMetrics[0].JankyFrames != 10 {
+		t.Errorf("Got unexpected frame metrics %+v\n", frameMetrics)
+	}
+
+	summary, err := srv.GetFrameSummary(appId, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetFrameSummary failed: %v\n", err)
+	}
+	expected := []model.FrameSummaryEntity{
+		{Screen: "Checkout", Sessions: 1, TotalFrames: 200, JankyFrames: 30, FrozenFrames: 2},
+		{Screen: "Home", Sessions: 1, TotalFrames: 300, JankyFrames: 3},
+	}
+	if !slices.Equal(summary, expected) {
+		t.Errorf("Got %+v, but expected %+v\n", summary, expected)
+	}
+
+	err = srv.CreateBatteryState(model.NewBatteryStateData{
+		Id: "TestBatteryState", SessionId: sessionId, InstallationId: installationId, AppId: appId,
+		Level: 87, Charging: true, Temperature: 31.5, ThermalState: model.ThermalStateModerate, CreatedAt: 1000,
+	})
+	if err != nil {
+		t.Fatalf("CreateBatteryState failed: %v\n", err)
+	}
+	battery, _, err := srv.GetBatteryStateBySessionId(sessionId, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetBatteryStateBySessionId failed: %v\n", err)
+	}
+	if len(battery) != 1 || !battery[0].Charging || battery[0].ThermalState != model.ThermalStateModerate {
+		t.Errorf("Got unexpected battery state %+v\n", battery)
+	}
+
+	err = srv.CreateNetworkUsage(model.NewNetworkUsageData{
+		Id: "TestNetworkUsage", SessionId: sessionId, InstallationId: installationId, AppId: appId,
+		ConnectionType: "wifi", Requests: 20, FailedRequests: 2, BytesSent: 2048, BytesReceived: 65536, CreatedAt: 1000,
+	})
+	if err != nil {
+		t.Fatalf("CreateNetworkUsage failed: %v\n", err)
+	}
+	network, _, err := srv.GetNetworkUsageByInstallationId(installationId, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetNetworkUsageByInstallationId failed: %v\n", err)
+	}
+	if len(network) != 1 || network[0].ConnectionType != "wifi" || network[0].BytesReceived != 65536 {
+		t.Errorf("Got unexpected network usage %+v\n", network)
+	}
+}
+
+func TestAppStarts(t *testing.T) {
+	srv := New(config)
+
+	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
+	appId, _ := srv.CreateApplication(model.NewApplicationData{
+		Name:   "TestApp",
+		TeamId: teamId,
+	})
+
+	installations := map[string]string{"TestStartInstallation1": "Pixel 8", "TestStartInstallation2": "Galaxy S23"}
+	for id, deviceModel := range installations {
+		err := srv.CreateInstallation(model.NewInstallationData{Id: id, AppId: appId, Type: "android", Data: map[string]any{"model": deviceModel}, CreatedAt: 1})
+		if err != nil {
+			t.Fatalf("CreateInstallation failed: %v\n", err)
+		}
+	}
+	sessions := []model.NewSessionData{
+		{Id: "TestStartSession1", InstallationId: "TestStartInstallation1", AppVersion: "1.0.0"},
+		{Id: "TestStartSession2", InstallationId: "TestStartInstallation2", AppVersion: "1.1.0"},
+	}
+	for _, data := range sessions {
+		data.AppId = appId
+		data.CreatedAt = 1
+		if err := srv.CreateSession(data); err != nil {
+			t.Fatalf("CreateSession failed: %v\n", err)
+		}
+	}
+
+	starts := []model.NewAppStartData{
+		{Id: "TestStart1", SessionId: "TestStartSession1", InstallationId: "TestStartInstallation1", StartType: model.StartTypeCold, Duration: 1000, ProcessDuration: 200, CreatedAt: 1000},
+		{Id: "TestStart2", SessionId: "TestStartSession1", InstallationId: "TestStartInstallation1", StartType: model.StartTypeHot, Duration: 100, CreatedAt: 2000},
+		{Id: "TestStart3", SessionId: "TestStartSession2", InstallationId: "TestStartInstallation2", StartType: model.StartTypeCold, Duration: 2000, ProcessDuration: 400, CreatedAt: 3000},
+	}
+	for _, data := range starts {
+		data.AppId = appId
+		if err := srv.CreateAppStart(data); err != nil {
+			t.Fatalf("CreateAppStart failed: %v\n", err)
+		}
+	}
+
+	bySession, _, err := srv.GetAppStartsBySessionId("TestStartSession1", model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetAppStartsBySessionId failed: %v\n", err)
+	}
+	if len(bySession) != 2 || bySession[0].StartType != model.StartTypeCold || bySession[0].ProcessDuration != 200 {
+		t.Errorf("Got unexpected app starts %+v\n", bySession)
+	}
+
+	byInstallation, _, err := srv.GetAppStartsByInstallationId("TestStartInstallation2", model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetAppStartsByInstallationId failed: %v\n", err)
+	}
+	if len(byInstallation) != 1 || byInstallation[0].Id != "TestStart3" {
+		t.Errorf("Got unexpected app starts %+v\n", byInstallation)
+	}
+
+	overall, err := srv.GetStartupSummary(appId, model.StartupQuery{StartType: model.StartTypeCold})
+	if err != nil {
+		t.Fatalf("GetStartupSummary failed: %v\n", err)
+	}
+	if len(overall) != 1 || overall[0].Starts != 2 || overall[0].Sessions != 2 || overall[0].DurationP50 != 1500 || overall[0].ProcessP50 != 300 || overall[0].ApplicationP50 != 1200 {
+		t.Errorf("Got unexpected startup summary %+v\n", overall)
+	}
+
+	byVersion, err := srv.GetStartupSummary(appId, model.StartupQuery{StartType: model.StartTypeCold, GroupBy: model.StartupGroupByAppVersion})
+	if err != nil {
+		t.Fatalf("GetStartupSummary failed: %v\n", err)
+	}
+	if len(byVersion) != 2 || byVersion[0].Group != "1.0.0" || byVersion[0].DurationP50 != 1000 || byVersion[1].Group != "1.1.0" {
+		t.Errorf("Got unexpected startup summary by version %+v\n", byVersion)
+	}
+
+	byModel, err := srv.GetStartupSummary(appId, model.StartupQuery{StartType: model.StartTypeCold, GroupBy: "model", Release: "1.1.0"})
+	if err != nil {
+		t.Fatalf("GetStartupSummary failed: %v\n", err)
+	}
+	if len(byModel) != 1 || byModel[0].Group != "Galaxy S23" || byModel[0].ProcessP99 != 400 {
+		t.Errorf("Got unexpected startup summary by model %+v\n", byModel)
+	}
+
+	hot, err := srv.GetStartupSummary(appId, model.StartupQuery{StartType: model.StartTypeHot})
+	if err != nil {
+		t.Fatalf("GetStartupSummary failed: %v\n", err)
+	}
+	if len(hot) != 1 || hot[0].Starts != 1 || hot[0].DurationP99 != 100 || hot[0].ProcessP50 != 0 {
+		t.Errorf("Got unexpected hot startup summary %+v\n", hot)
+	}
+}
+
+func TestNetworkRequests(t *testing.T) {
+	srv := New(config)
+
+	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
+	appId, _ := srv.CreateApplication(model.NewApplicationData{
+		Name:   "TestApp",
+		TeamId: teamId,
+	})
+
+	sessionId := "TestNetworkSession"
+	err := srv.CreateSession(model.NewSessionData{Id: sessionId, InstallationId: "TestNetworkInstallation", AppId: appId, CreatedAt: 1})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v\n", err)
+	}
+
+	traceId := "TestNetworkTrace"
+	requests := []model.NewNetworkRequestData{
+		{Id: "TestRequest1", TraceId: traceId, Method: "GET", UrlTemplate: "https://api.example.com/users/{id}", StatusCode: 200, Duration: 100, ResponseSize: 1000, StartedAt: 1000},
+		{Id: "TestRequest2", Method: "GET", UrlTemplate: "https://api.example.com/users/{id}", StatusCode: 500, Duration: 300, ResponseSize: 3000, StartedAt: 2000},
+		{Id: "TestRequest3", Method: "GET", UrlTemplate: "https://api.example.com/users/{id}", FailureReason: "timeout", Duration: 200, StartedAt: 3000},
+		{Id: "TestRequest4", Method: "POST", UrlTemplate: "https://api.example.com/orders", StatusCode: 201, Duration: 50, ResponseSize: 100, StartedAt: 4000},
+	}
+	for i := range requests {
+		requests[i].SessionId = sessionId
+		requests[i].AppId = appId
+		requests[i].Host = "api.example.com"
+	}
+	if err := srv.CreateNetworkRequests(requests); err != nil {
+		t.Fatalf("CreateNetworkRequests failed: %v\n", err)
+	}
+	// Creating the same request again is ignored
+	if err := srv.CreateNetworkRequest(requests[0]); err != nil {
+		t.Fatalf("CreateNetworkRequest failed: %v\n", err)
+	}
+
+	bySession, _, err := srv.GetNetworkRequestsBySessionId(sessionId, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetNetworkRequestsBySessionId failed: %v\n", err)
+	}
+	if len(bySession) != 4 || bySession[0].Id != "TestRequest1" || bySession[2].FailureReason != "timeout" {
+		t.Errorf("Got unexpected network requests %+v\n", bySession)
+	}
+
+	byTrace, err := srv.GetNetworkRequestsByTraceId(traceId)
+	if err != nil {
+		t.Fatalf("GetNetworkRequestsByTraceId failed: %v\n", err)
+	}
+	if len(byTrace) != 1 || byTrace[0].Id != "TestRequest1" {
+		t.Errorf("Got unexpected network requests %+v for trace\n", byTrace)
+	}
+
+	endpoints, err := srv.GetNetworkEndpoints(appId, model.NetworkQuery{})
+	if err != nil {
+		t.Fatalf("GetNetworkEndpoints failed: %v\n", err)
+	}
+	if len(endpoints) != 2 {
+		t.Fatalf("Got %d endpoints, but expected 2\n", len(endpoints))
+	}
+	users := endpoints[0]
+	if users.Method != "GET" || users.Requests != 3 || users.Errors != 2 || users.DurationP50 != 200 || math.Abs(users.AvgResponseSize-4000.0/3) > 1e-9 {
+		t.Errorf("Got unexpected endpoint %+v\n", users)
+	}
+	if endpoints[1].Method != "POST" || endpoints[1].Requests != 1 || endpoints[1].Errors != 0 {
+		t.Errorf("Got unexpected endpoint %+v\n", endpoints[1])
+	}
+
+	series, err := srv.GetNetworkEndpoints(appId, model.NetworkQuery{
+		Method:      "GET",
+		UrlTemplate: "https://api.example.com/users/{id}",
+		From:        2000,
+		Interval:    model.StabilityIntervalDay,
+	})
+	if err != nil {
+		t.Fatalf("GetNetworkEndpoints failed: %v\n", err)
+	}
+	if len(series) != 1 || series[0].Requests != 2 || series[0].Errors != 2 || series[0].Bucket != 0 {
+		t.Errorf("Got unexpected series %+v\n", series)
+	}
+}
+
+func TestCreateCrash(t *testing.T) {
+	srv := New(config)
+
+	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
+	appId, _ := srv.CreateApplication(model.NewApplicationData{
+		Name:   "TestApp",
+		TeamId: teamId,
+	})
+
+	sessionIds := []string{"TestCrashSession1", "TestCrashSession2"}
+	for _, id := range sessionIds {
+		err := srv.CreateSession(model.NewSessionData{
+			Id:             id,
+			InstallationId: "InstallationIdForTestCrash",
+			AppId:          appId,
+			CreatedAt:      1,
+		})
+		if err != nil {
+			t.Fatalf("CreateSession failed: %v\n", err)
+		}
+	}
+
+	crashes := []model.NewCrashData{
+		{Id: "TestCrash1", SessionId: sessionIds[0], Fingerprint: "npe", CreatedAt: 20},
+		{Id: "TestCrash2", SessionId: sessionIds[0], Fingerprint: "npe", CreatedAt: 10},
+		{Id: "TestCrash3", SessionId: sessionIds[1], Fingerprint: "npe", CreatedAt: 30},
+		{Id: "TestCrash4", SessionId: sessionIds[1], Fingerprint: "oom", CreatedAt: 40},
+	}
+	for _, crash := range crashes {
+		crash.AppId = appId
+		crash.ExceptionClass = "java.lang.NullPointerException"
+		crash.Frames = []model.StackFrameDTO{{ClassName: "com.example.Main", MethodName: "main", LineNumber: 1}}
+		if err := srv.CreateCrash(crash); err != nil {
+			t.Fatalf("CreateCrash failed: %v\n", err)
+		}
+		// Replays are ignored
+		if err := srv.CreateCrash(crash); err != nil {
+			t.Fatalf("CreateCrash replay failed: %v\n", err)
+		}
+	}
+
+	session, err := srv.GetSession(sessionIds[1])
+	if err != nil || !session.Crashed {
+		t.Errorf("Expected session to be marked as crashed. err = %v\n", err)
+	}
+
+	issues, err := srv.GetIssuesByAppId(appId, "", model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetIssuesByAppId failed: %v\n", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("Got %d issues, but expected 2\n", len(issues))
+	}
+	npe := issues[0]
+	if npe.Fingerprint != "npe" || npe.Occurrences != 3 || npe.Sessions != 2 || npe.FirstSeen != 10 || npe.LastSeen != 30 {
+		t.Errorf("Got issue %+v, but expected 'npe' with 3 occurrences in 2 sessions between 10 and 30\n", npe)
+	}
+
+	issues, err = srv.GetIssuesByAppId(appId, "", model.PageQuery{From: 15, To: 35})
+	if err != nil {
+		t.Fatalf("GetIssuesByAppId failed: %v\n", err)
+	}
+	if len(issues) != 1 || issues[0].Occurrences != 2 {
+		t.Errorf("Got issues %+v within time range, but expected 'npe' with 2 occurrences\n", issues)
+	}
+
+	entities, _, err := srv.GetCrashesByIssue(appId, "npe", model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetCrashesByIssue failed: %v\n", err)
+	}
+	if len(entities) != 3 || entities[0].Id != "TestCrash2" || len(entities[0].Frames) != 1 {
+		t.Errorf("Got crashes %+v, but expected 3 ordered by time\n", entities)
+	}
+
+	err = srv.CreateCrash(model.NewCrashData{Id: "TestCrashOtherApp", SessionId: sessionIds[0], AppId: appId + 1000, Fingerprint: "npe", CreatedAt: 1})
+	if err == nil {
+		t.Errorf("Expected crash for session of another app to fail\n")
+	}
+}
+
+func TestCreateAnr(t *testing.T) {
+	srv := New(config)
+
+	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
+	appId, _ := srv.CreateApplication(model.NewApplicationData{
+		Name:   "TestApp",
+		TeamId: teamId,
+	})
+
+	sessions := []model.NewSessionData{
+		{Id: "TestAnrSession1", AppVersion: "1.0.0", CreatedAt: 1},
+		{Id: "TestAnrSession2", AppVersion: "1.0.0", CreatedAt: 2},
+		{Id: "TestAnrSession3", AppVersion: "1.1.0", CreatedAt: 3},
+	}
+	for _, session := range sessions {
+		session.AppId = appId
+		session.InstallationId = "InstallationIdForTestAnr"
+		if err := srv.CreateSession(session); err != nil {
+			t.Fatalf("CreateSession failed: %v\n", err)
+		}
+	}
+
+	anrs := []model.NewAnrData{
+		{Id: "TestAnr1", SessionId: "TestAnrSession1", AppVersion: "1.0.0", CreatedAt: 10},
+		{Id: "TestAnr2", SessionId: "TestAnrSession1", AppVersion: "1.0.0", CreatedAt: 20},
+		{Id: "TestAnr3", SessionId: "TestAnrSession3", AppVersion: "1.1.0", CreatedAt: 30},
+	}
+	for _, anr := range anrs {
+		anr.AppId = appId
+		anr.Fingerprint = "blocked-io"
+		anr.Duration = 5000
+		anr.MainThread = []model.StackFrameDTO{{ClassName: "com.example.Main", MethodName: "load", LineNumber: 1}}
+		anr.Threads = []model.ThreadDTO{{Name: "worker", State: "BLOCKED"}}
+		if err := srv.CreateAnr(anr); err != nil {
+			t.Fatalf("CreateAnr failed: %v\n", err)
+		}
+		// Replays are ignored
+		if err := srv.CreateAnr(anr); err != nil {
+			t.Fatalf("CreateAnr replay failed: %v\n", err)
+		}
+	}
+
+	err := srv.CreateAnr(model.NewAnrData{Id: "TestAnrOtherApp", SessionId: "TestAnrSession1", AppId: appId + 1000, Fingerprint: "blocked-io", CreatedAt: 1})
+	if err == nil {
+		t.Errorf("Expected ANR for session of another app to fail\n")
+	}
+
+	entities, _, err := srv.GetAnrsByIssue(appId, "blocked-io", model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetAnrsByIssue failed: %v\n", err)
+	}
+	if len(entities) != 3 || entities[0].InstallationId != "InstallationIdForTestAnr" || len(entities[0].Threads) != 1 {
+		t.Errorf("Got ANRs %+v, but expected 3 linked to the installation of their session\n", entities)
+	}
+
+	issues, err := srv.GetIssuesByAppId(appId, model.IssueTypeAnr, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetIssuesByAppId failed: %v\n", err)
+	}
+	if len(issues) != 1 || issues[0].Type != model.IssueTypeAnr || issues[0].Occurrences != 3 || issues[0].Sessions != 2 {
+		t.Errorf("Got issues %+v, but expected 1 ANR issue with 3 occurrences in 2 sessions\n", issues)
+	}
+
+	issues, err = srv.GetIssuesByAppId(appId, model.IssueTypeCrash, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetIssuesByAppId failed: %v\n", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("Got crash issues %+v, but expected none\n", issues)
+	}
+
+	rates, err := srv.GetAnrRates(appId, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetAnrRates failed: %v\n", err)
+	}
+	expected := []model.AnrRateEntity{
+		{AppVersion: "1.1.0", Sessions: 1, AnrSessions: 1, Anrs: 1},
+		{AppVersion: "1.0.0", Sessions: 2, AnrSessions: 1, Anrs: 2},
+	}
+	if !slices.Equal(rates, expected) {
+		t.Errorf("Got ANR rates %+v, but expected %+v\n", rates, expected)
+	}
+}
+
+func TestGetStability(t *testing.T) {
+	srv := New(config)
+
+	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
+	appId, _ := srv.CreateApplication(model.NewApplicationData{
+		Name:   "TestApp",
+		TeamId: teamId,
+	})
+
+	installations := map[string]string{"TestStabilityPixel": "Google", "TestStabilityGalaxy": "Samsung"}
+	for id, brand := range installations {
+		err := srv.CreateInstallation(model.NewInstallationData{
+			Id:        id,
+			AppId:     appId,
+			Type:      "android",
+			Data:      map[string]any{"brand": brand},
+			CreatedAt: 1,
+		})
+		if err != nil {
+			t.Fatalf("CreateInstallation failed: %v\n", err)
+		}
+	}
+
+	day := int64(24 * 60 * 60 * 1000)
+	sessions := []model.NewSessionData{
+		{Id: "TestStability1", InstallationId: "TestStabilityPixel", CreatedAt: day + 1},
+		{Id: "TestStability2", InstallationId: "TestStabilityPixel", CreatedAt: day + 2, Crashed: true},
+		{Id: "TestStability3", InstallationId: "TestStabilityGalaxy", CreatedAt: day + 3},
+		{Id: "TestStability4", InstallationId: "TestStabilityGalaxy", CreatedAt: 2*day + 1},
+	}
+	for _, session := range sessions {
+		session.AppId = appId
+		if err := srv.CreateSession(session); err != nil {
+			t.Fatalf("CreateSession failed: %v\n", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		query    model.StabilityQuery
+		expected []model.StabilityEntity
+	}{
+		{
+			name:     "overall",
+			query:    model.StabilityQuery{},
+			expected: []model.StabilityEntity{{Sessions: 4, CrashedSessions: 1, Installations: 2, CrashedInstallations: 1}},
+		},
+		{
+			name:  "per day",
+			query: model.StabilityQuery{Interval: model.StabilityIntervalDay},
+			expected: []model.StabilityEntity{
+				{Bucket: day, Sessions: 3, CrashedSessions: 1, Installations: 2, CrashedInstallations: 1},
+				{Bucket: 2 * day, Sessions: 1, Installations: 1},
+			},
+		},
+		{
+			name:  "by brand",
+			query: model.StabilityQuery{GroupBy: "brand"},
+			expected: []model.StabilityEntity{
+				{Group: "Google", Sessions: 2, CrashedSessions: 1, Installations: 1, CrashedInstallations: 1},
+				{Group: "Samsung", Sessions: 2, Installations: 1},
+			},
+		},
+		{
+			name:     "time range",
+			query:    model.StabilityQuery{From: 2 * day},
+			expected: []model.StabilityEntity{{Sessions: 1, Installations: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entities, err := srv.GetStability(appId, tt.query)
+			if err != nil {
+				t.Fatalf("GetStability failed: %v\n", err)
+			}
+			if !slices.Equal(entities, tt.expected) {
+				t.Errorf("Got %+v, but expected %+v\n", entities, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetReleases(t *testing.T) {
+	srv := New(config)
+
+	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
+	appId, _ := srv.CreateApplication(model.NewApplicationData{
+		Name:   "TestApp",
+		TeamId: teamId,
+	})
+
+	sessions := []model.NewSessionData{
+		{Id: "TestRelease1", AppVersion: "1.0.0", VersionCode: 10, BuildType: "release", CreatedAt: 20},
+		{Id: "TestRelease2", AppVersion: "1.0.0", VersionCode: 10, BuildType: "release", CreatedAt: 10},
+		{Id: "TestRelease3", AppVersion: "1.1.0", VersionCode: 11, BuildType: "release", CreatedAt: 30, Crashed: true},
+		{Id: "TestRelease4", CreatedAt: 40},
+	}
+	for _, session := range sessions {
+		session.AppId = appId
+		session.InstallationId = "InstallationIdForTestRelease"
+		if err := srv.CreateSession(session); err != nil {
+			t.Fatalf("CreateSession failed: %v\n", err)
+		}
+	}
+
+	releases, err := srv.GetReleases(appId)
+	if err != nil {
+		t.Fatalf("GetReleases failed: %v\n", err)
+	}
+	expected := []model.ReleaseEntity{
+		{AppId: appId, Version: "1.1.0", VersionCode: 11, FirstSeen: 30, LastSeen: 30},
+		{AppId: appId, Version: "1.0.0", VersionCode: 10, FirstSeen: 10, LastSeen: 20},
+	}
+	if !slices.Equal(releases, expected) {
+		t.Errorf("Got releases %+v, but expected %+v\n", releases, expected)
+	}
+
+	session, err := srv.GetSession("TestRelease3")
+	if err != nil || session.VersionCode != 11 || session.BuildType != "release" {
+		t.Errorf("Got session %+v, but expected version code and build type to be stored. err = %v\n", session, err)
+	}
+
+	data, err := srv.GetApplicationData(appId, model.PageQuery{Release: "1.0.0"}, model.PageQuery{Release: "1.0.0"})
+	if err != nil {
+		t.Fatalf("GetApplicationData failed: %v\n", err)
+	}
+	if len(data.Sessions) != 2 {
+		t.Errorf("Got %d sessions of release 1.0.0, but expected 2\n", len(data.Sessions))
+	}
+
+	stability, err := srv.GetStability(appId, model.StabilityQuery{ByRelease: true})
+	if err != nil {
+		t.Fatalf("GetStability failed: %v\n", err)
+	}
+	if len(stability) != 3 || stability[0].Group != "1.0.0" || stability[0].Sessions != 2 {
+		t.Errorf("Got stability %+v, but expected 3 versions with 1.0.0 first\n", stability)
+	}
+
+	stability, err = srv.GetStability(appId, model.StabilityQuery{Release: "1.1.0"})
+	if err != nil {
+		t.Fatalf("GetStability failed: %v\n", err)
+	}
+	if len(stability) != 1 || stability[0].Sessions != 1 || stability[0].CrashedSessions != 1 {
+		t.Errorf("Got stability %+v, but expected 1 crashed session of release 1.1.0\n", stability)
+	}
+}
+
+func TestCreateMapping(t *testing.T) {
+	srv := New(config)
+
+	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
+	appId, _ := srv.CreateApplication(model.NewApplicationData{
+		Name:   "TestApp",
+		TeamId: teamId,
+	})
+
+	_, err := srv.GetMapping(appId, "1.0.0")
+	if err != sql.ErrNoRows {
+		t.Fatalf("Expected no mapping before upload, but got err = %v\n", err)
+	}
+
+	uploads := []model.NewMappingData{
+		{AppId: appId, Version: "1.0.0", Mapping: []byte("com.example.Main -> a:\n"), CreatedAt: 1},
+		{AppId: appId, Version: "1.0.0", Mapping: []byte("com.example.Main -> b:\n"), CreatedAt: 2},
+		{AppId: appId, Version: "1.1.0", Mapping: []byte("com.example.Main -> c:\n"), CreatedAt: 3},
+	}
+	for _, upload := range uploads {
+		if err := srv.CreateMapping(upload); err != nil {
+			t.Fatalf("CreateMapping failed: %v\n", err)
+		}
+	}
+
+	entity, err := srv.GetMapping(appId, "1.0.0")
+	if err != nil {
+		t.Fatalf("GetMapping failed: %v\n", err)
+	}
+	if entity.CreatedAt != 2 || entity.Size != len(uploads[1].Mapping) {
+		t.Errorf("Got mapping %+v, but expected the second upload to replace the first\n", entity)
+	}
+
+	content, err := srv.GetMappingContent(appId, "1.0.0")
+	if err != nil {
+		t.Fatalf("GetMappingContent failed: %v\n", err)
+	}
+	if string(content) != string(uploads[1].Mapping) {
+		t.Errorf("Got mapping content '%s', but expected '%s'\n", content, uploads[1].Mapping)
+	}
+
+	entities, err := srv.GetMappings(appId)
+	if err != nil {
+		t.Fatalf("GetMappings failed: %v\n", err)
+	}
+	if len(entities) != 2 || entities[0].Version != "1.1.0" {
+		t.Errorf("Got mappings %+v, but expected 2 with the newest first\n", entities)
+	}
+}
+
+func TestTakeRateLimitToken(t *testing.T) {
+	srv := New(config)
+
+	key := "key:1:rate-limit-test"
+	now := int64(1700000000000)
+	for i := 0; i < 2; i++ {
+		ok, tokens, err := srv.TakeRateLimitToken(key, 1, 2, now)
+		if err != nil {
+			t.Fatalf("TakeRateLimitToken failed: %v\n", err)
+		}
+		if !ok || tokens != float64(1-i) {
+			t.Errorf("Take %d = %v with %v tokens left, expected a token with %d left\n", i, ok, tokens, 1-i)
+		}
+	}
+
+	ok, tokens, err := srv.TakeRateLimitToken(key, 1, 2, now+500)
+	if err != nil {
+		t.Fatalf("TakeRateLimitToken failed: %v\n", err)
+	}
+	if ok || tokens != 0.5 {
+		t.Errorf("Take of empty bucket = %v with %v tokens, expected no token with 0.5\n", ok, tokens)
+	}
+
+	if ok, _, _ := srv.TakeRateLimitToken(key, 1, 2, now+1000); !ok {
+		t.Errorf("Take after refill = false\n")
+	}
+
+	if err := srv.DeleteFullRateLimits(now + 3000); err != nil {
+		t.Fatalf("DeleteFullRateLimits failed: %v\n", err)
+	}
+	if ok, tokens, _ := srv.TakeRateLimitToken(key, 1, 2, now+1000); !ok || tokens != 1 {
+		t.Errorf("Take of removed bucket = %v with %v tokens, expected a full bucket\n", ok, tokens)
+	}
+}
+
+func TestThrottledRequests(t *testing.T) {
+	srv := New(config)
+
+	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
+	appId, err := srv.CreateApplication(model.NewApplicationData{
+		Name:   "TestApp",
+		TeamId: teamId,
+	})
+	if err != nil {
+		t.Fatalf("Could not create application: %v\n", err)
+	}
+
+	hour := int64(1700002800000)
+	for i := 0; i < 2; i++ {
+		err := srv.AddThrottledRequests([]model.ThrottledRequestsData{
+			{AppId: appId, Bucket: hour, Reason: model.ThrottleReasonInstallation, Count: 3},
+			{AppId: appId, Bucket: hour, Reason: model.ThrottleReasonKey, Count: 1},
+			{AppId: appId, Bucket: hour + 3600000, Reason: model.ThrottleReasonKey, Count: 2},
+		})
+		if err != nil {
+			t.Fatalf("AddThrottledRequests failed: %v\n", err)
+		}
+	}
+
+	series, err := srv.GetThrottledRequests(appId, model.ThrottledRequestsQuery{Interval: model.StabilityIntervalHour})
+	if err != nil {
+		t.Fatalf("GetThrottledRequests failed: %v\n", err)
+	}
+	expected := []model.ThrottledRequestsEntity{
+		{Bucket: hour, Reason: model.ThrottleReasonInstallation, Count: 6},
+		{Bucket: hour, Reason: model.ThrottleReasonKey, Count: 2},
+		{Bucket: hour + 3600000, Reason: model.ThrottleReasonKey, Count: 4},
+	}
+	if !reflect.DeepEqual(series, expected) {
+		t.Errorf("Got series %+v, expected %+v\n", series, expected)
+	}
+
+	overall, err := srv.GetThrottledRequests(appId, model.ThrottledRequestsQuery{From: hour + 1})
+	if err != nil {
+		t.Fatalf("GetThrottledRequests failed: %v\n", err)
+	}
+	if len(overall) != 1 || overall[0].Count != 4 {
+		t.Errorf("Got %+v, expected only the second hour\n", overall)
+	}
+}
+
+func TestIngestionSettings(t *testing.T) {
+	srv := New(config)
+
+	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
+	appId, err := srv.CreateApplication(model.NewApplicationData{
+		Name:   "TestApp",
+		TeamId: teamId,
+	})
+	if err != nil {
+		t.Fatalf("Could not create application: %v\n", err)
+	}
+
+	if _, err := srv.GetIngestionSettings(appId); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Got %v for app without settings, expected sql.ErrNoRows\n", err)
+	}
+
+	crashed := true
+	for _, quota := range []int64{100, 200} {
+		err := srv.SetIngestionSettings(model.IngestionSettingsData{
+			AppId:             appId,
+			MonthlyEventQuota: quota,
+			SamplingRules:     []model.SamplingRule{{Crashed: &crashed, Rate: 1}, {Rate: 0.1}},
+			UpdatedAt:         1700000000000,
+		})
+		if err != nil {
+			t.Fatalf("SetIngestionSettings failed: %v\n", err)
+		}
+	}
+
+	settings, err := srv.GetIngestionSettings(appId)
+	if err != nil {
+		t.Fatalf("GetIngestionSettings failed: %v\n", err)
+	}
+	if settings.MonthlyEventQuota != 200 || settings.MonthlyTraceQuota != 0 || len(settings.SamplingRules) != 2 || *settings.SamplingRules[0].Crashed != true || settings.SamplingRules[1].Rate != 0.1 {
+		t.Errorf("Got settings %+v, expected the last update\n", settings)
+	}
+}
+
+func TestAddIngestionUsage(t *testing.T) {
+	srv := New(config)
+
+	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
+	appId, err := srv.CreateApplication(model.NewApplicationData{
+		Name:   "TestApp",
+		TeamId: teamId,
+	})
+	if err != nil {
+		t.Fatalf("Could not create application: %v\n", err)
+	}
+
+	month := int64(1711929600000)
+	usage, err := srv.GetIngestionUsage(appId, month)
+	if err != nil || usage.Events != 0 || usage.Month != month {
+		t.Errorf("Got usage %+v, %v before ingestion, expected 0\n", usage, err)
+	}
+
+	batches := []struct {
+		data           model.IngestionUsageData
+		expectedEvents int
+		expectedTraces int
+	}{
+		{data: model.IngestionUsageData{Events: 6, Traces: 3, SampledEvents: 2, EventQuota: 10}, expectedEvents: 6, expectedTraces: 3},
+		{data: model.IngestionUsageData{Events: 6, Traces: 3, EventQuota: 10}, expectedEvents: 4, expectedTraces: 3},
+		{data: model.IngestionUsageData{Events: 1, EventQuota: 10}, expectedEvents: 0},
+	}
+	for i, batch := range batches {
+		batch.data.AppId = appId
+		batch.data.Month = month
+		events, traces, err := srv.AddIngestionUsage(batch.data)
+		if err != nil {
+			t.Fatalf("AddIngestionUsage failed: %v\n", err)
+		}
+		if events != batch.expectedEvents || traces != batch.expectedTraces {
+			t.Errorf("Batch %d stores %d events and %d traces, expected %d and %d\n", i, events, traces, batch.expectedEvents, batch.expectedTraces)
+		}
+	}
+
+	usage, err = srv.GetIngestionUsage(appId, month)
+	if err != nil {
+		t.Fatalf("GetIngestionUsage failed: %v\n", err)
+	}
+	expected := model.IngestionUsageEntity{Month: month, Events: 10, Traces: 6, SampledEvents: 2, OverQuotaEvents: 3}
+	if usage != expected {
+		t.Errorf("Got usage %+v, expected %+v\n", usage, expected)
+	}
+
+	err = srv.ReleaseIngestionUsage(appId, model.IngestionUsageEntity{Month: month, Events: 4, Traces: 3, OverQuotaEvents: 2})
+	if err != nil {
+		t.Fatalf("ReleaseIngestionUsage failed: %v\n", err)
+	}
+	usage, err = srv.GetIngestionUsage(appId, month)
+	if err != nil {
+		t.Fatalf("GetIngestionUsage failed: %v\n", err)
+	}
+	expected = model.IngestionUsageEntity{Month: month, Events: 6, Traces: 3, SampledEvents: 2, OverQuotaEvents: 1}
+	if usage != expected {
+		t.Errorf("Got usage %+v after releasing, expected %+v\n", usage, expected)
+	}
+}
+
+func TestGetSessions(t *testing.T) {
+	srv := New(config)
+
+	teamId, _ := srv.CreateTeam(model.NewTeamData{Name: "Test Team"})
+	appId, err := srv.CreateApplication(model.NewApplicationData{
+		Name:   "TestApp",
+		TeamId: teamId,
+	})
+	if err != nil {
+		t.Fatalf("Could not create application: %v\n", err)
+	}
+	installationId := "GetSessionsInstallation"
+	srv.CreateInstallation(model.NewInstallationData{Id: installationId, AppId: appId, Type: "android"})
+	for _, id := range []string{"GetSessions1", "GetSessions2"} {
+		err := srv.CreateSession(model.NewSessionData{Id: id, InstallationId: installationId, AppId: appId, CreatedAt: 1, Crashed: id == "GetSessions2"})
+		if err != nil {
+			t.Fatalf("Could not create session: %v\n", err)
+		}
+	}
+
+	sessions, err := srv.GetSessions(appId, []string{"GetSessions2", "GetSessionsUnknown"})
+	if err != nil {
+		t.Fatalf("GetSessions failed: %v\n", err)
+	}
+	if len(sessions) != 1 || sessions[0].Id != "GetSessions2" || !sessions[0].Crashed {
+		t.Errorf("Got sessions %+v, expected only the crashed session\n", sessions)
+	}
+}
+
 func TestHealth(t *testing.T) {
 	srv := New(config)
 
@@ -0,0 +1,66 @@
+package mapping
+
+import "sync"
+
+// Cache keeps the most recently used mappings in memory, since parsing a
+// mapping takes far longer than deobfuscating a stack trace with it
+type Cache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*Mapping
+	// Keys ordered from least to most recently used
+	order []string
+}
+
+func NewCache(size int) *Cache {
+	return &Cache{
+		size:    size,
+		entries: make(map[string]*Mapping),
+	}
+}
+
+// Get returns the mapping stored under key, loading it if it is not cached.
+// A nil Cache loads the mapping on every call.
+func (c *Cache) Get(key string, load func() (*Mapping, error)) (*Mapping, error) {
+	if c == nil {
+		return load()
+	}
+
+	c.mu.Lock()
+	m, ok := c.entries[key]
+	if ok {
+		c.touch(key)
+	}
+	c.mu.Unlock()
+	if ok {
+		return m, nil
+	}
+
+	m, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		c.entries[key] = m
+		c.order = append(c.order, key)
+		if len(c.order) > c.size {
+			delete(c.entries, c.order[0])
+			c.order = c.order[1:]
+		}
+	}
+
+	return m, nil
+}
+
+func (c *Cache) touch(key string) {
+	for i, k := range c.order {
+		if k == key {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	c.order = append(c.order, key)
+}
@@ -0,0 +1,221 @@
+package mapping
+
+import (
+	"ObservabilityServer/internal/model"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Mapping is a parsed ProGuard/R8 mapping file, which translates the names
+// and line numbers of a minified build back to the original source
+type Mapping struct {
+	// Classes by their obfuscated name
+	classes map[string]*class
+	// Source files by the original class name
+	files map[string]string
+}
+
+type class struct {
+	original string
+	methods  map[string][]method
+}
+
+// method is a single method line. Methods with line ranges that share the
+// same obfuscated name and range are an inline chain, innermost first
+type method struct {
+	original  string
+	startLine int
+	endLine   int
+	origStart int
+	origEnd   int
+}
+
+var (
+	classPattern  = regexp.MustCompile(`^(\S+) -> (\S+):$`)
+	methodPattern = regexp.MustCompile(`^(?:(\d+):(\d+):)?\S+ ([^\s(]+)\([^)]*\)(?::(\d+)(?::(\d+))?)? -> (\S+)$`)
+)
+
+// Parse reads a mapping in the format written by ProGuard and R8
+func Parse(r io.Reader) (*Mapping, error) {
+	m := &Mapping{
+		classes: make(map[string]*class),
+		files:   make(map[string]string),
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var current *class
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "#") {
+			if current != nil {
+				m.parseMetadata(current, trimmed)
+			}
+			continue
+		}
+
+		// Class lines start in the first column, members are indented
+		if line[0] != ' ' && line[0] != '\t' {
+			match := classPattern.FindStringSubmatch(trimmed)
+			if match == nil {
+				return nil, fmt.Errorf("Malformed class mapping on line %d", lineNumber)
+			}
+			current = &class{
+				original: match[1],
+				methods:  make(map[string][]method),
+			}
+			m.classes[match[2]] = current
+			continue
+		}
+
+		if current == nil {
+			return nil, fmt.Errorf("Member mapping without class on line %d", lineNumber)
+		}
+
+		// Fields have no parameter list and are not needed for stack traces
+		match := methodPattern.FindStringSubmatch(trimmed)
+		if match == nil {
+			continue
+		}
+		current.methods[match[6]] = append(current.methods[match[6]], method{
+			original:  match[3],
+			startLine: atoi(match[1]),
+			endLine:   atoi(match[2]),
+			origStart: atoi(match[4]),
+			origEnd:   atoi(match[5]),
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// parseMetadata reads the source file of a class, which R8 writes as
+// '# {"id":"sourceFile","fileName":"Foo.kt"}' after the class line
+func (m *Mapping) parseMetadata(c *class, line string) {
+	var metadata struct {
+		Id       string `json:"id"`
+		FileName string `json:"fileName"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "#"))), &metadata); err != nil {
+		return
+	}
+	if metadata.Id == "sourceFile" && metadata.FileName != "" {
+		m.files[c.original] = metadata.FileName
+	}
+}
+
+func atoi(s string) int {
+	if s == "" {
+		return 0
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// Class returns the original name of an obfuscated class, or the name
+// itself if it is not part of the mapping
+func (m *Mapping) Class(obfuscated string) string {
+	if c, ok := m.classes[obfuscated]; ok {
+		return c.original
+	}
+	return obfuscated
+}
+
+// Frames deobfuscates a stack trace. A frame can expand into several
+// frames, if the minifier inlined methods into it.
+func (m *Mapping) Frames(frames []model.StackFrameDTO) []model.StackFrameDTO {
+	result := make([]model.StackFrameDTO, 0, len(frames))
+	for _, frame := range frames {
+		result = append(result, m.Frame(frame)...)
+	}
+	return result
+}
+
+// Frame deobfuscates a single stack frame
+func (m *Mapping) Frame(frame model.StackFrameDTO) []model.StackFrameDTO {
+	c, ok := m.classes[frame.ClassName]
+	if !ok {
+		return []model.StackFrameDTO{frame}
+	}
+
+	candidates := c.methods[frame.MethodName]
+	if len(candidates) == 0 {
+		return []model.StackFrameDTO{m.frame(c.original, frame.MethodName, frame.FileName, frame.LineNumber)}
+	}
+
+	// Line ranges tell apart methods with the same obfuscated name, and list
+	// every method inlined at that line
+	matched := make([]method, 0)
+	for _, candidate := range candidates {
+		if candidate.startLine > 0 && candidate.startLine <= frame.LineNumber && frame.LineNumber <= candidate.endLine {
+			matched = append(matched, candidate)
+		}
+	}
+	if len(matched) == 0 {
+		for _, candidate := range candidates {
+			if candidate.startLine == 0 {
+				matched = append(matched, candidate)
+			}
+		}
+	}
+	if len(matched) == 0 {
+		matched = candidates[:1]
+	}
+
+	result := make([]model.StackFrameDTO, 0, len(matched))
+	for _, mm := range matched {
+		className, methodName := c.original, mm.original
+		// Methods inlined from other classes are qualified with their class
+		if i := strings.LastIndex(mm.original, "."); i >= 0 {
+			className, methodName = mm.original[:i], mm.original[i+1:]
+		}
+
+		result = append(result, m.frame(className, methodName, frame.FileName, originalLine(mm, frame.LineNumber)))
+
+		// Without line ranges it is ambiguous which overload was called
+		if mm.startLine == 0 {
+			break
+		}
+	}
+
+	return result
+}
+
+func (m *Mapping) frame(className, methodName, fileName string, line int) model.StackFrameDTO {
+	if file, ok := m.files[className]; ok {
+		fileName = file
+	}
+	return model.StackFrameDTO{
+		ClassName:  className,
+		MethodName: methodName,
+		FileName:   fileName,
+		LineNumber: line,
+	}
+}
+
+func originalLine(mm method, line int) int {
+	if mm.origStart == 0 {
+		return line
+	}
+	if mm.origEnd > mm.origStart && mm.startLine > 0 {
+		return mm.origStart + line - mm.startLine
+	}
+	return mm.origStart
+}
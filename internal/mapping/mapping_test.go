@@ -0,0 +1,157 @@
+package mapping
+
+import (
+	"ObservabilityServer/internal/model"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testMapping = `# compiler: R8
+# pg_map_id: 1a2b3c
+com.example.cart.CartViewModel -> a.b:
+# {"id":"sourceFile","fileName":"CartViewModel.kt"}
+    java.lang.String total -> a
+    1:4:void load(int):42:45 -> a
+    5:5:void com.example.cart.Prices.format(long):12:12 -> b
+    5:5:void refresh():60 -> b
+    6:9:void refresh():61:64 -> b
+    void clear() -> c
+    void clear(int) -> c
+com.example.cart.CartActivity -> a.c:
+    1:1:void onCreate(android.os.Bundle):20:20 -> onCreate
+`
+
+func TestParse(t *testing.T) {
+	m, err := Parse(strings.NewReader(testMapping))
+	if err != nil {
+		t.Fatalf("Parse failed: %v\n", err)
+	}
+
+	if got := m.Class("a.b"); got != "com.example.cart.CartViewModel" {
+		t.Fatalf("Expected class 'com.example.cart.CartViewModel' but was '%s'", got)
+	}
+	if got := m.Class("z.z"); got != "z.z" {
+		t.Fatalf("Expected unknown class to be kept but was '%s'", got)
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	_, err := Parse(strings.NewReader("    void load() -> a\n"))
+	if err == nil {
+		t.Fatalf("Expected member without class to fail")
+	}
+
+	_, err = Parse(strings.NewReader("com.example.Foo a.b\n"))
+	if err == nil {
+		t.Fatalf("Expected malformed class line to fail")
+	}
+}
+
+func TestFrame(t *testing.T) {
+	m, err := Parse(strings.NewReader(testMapping))
+	if err != nil {
+		t.Fatalf("Parse failed: %v\n", err)
+	}
+
+	tests := []struct {
+		name     string
+		frame    model.StackFrameDTO
+		expected []model.StackFrameDTO
+	}{
+		{
+			name:  "line range",
+			frame: model.StackFrameDTO{ClassName: "a.b", MethodName: "a", FileName: "SourceFile", LineNumber: 3},
+			expected: []model.StackFrameDTO{
+				{ClassName: "com.example.cart.CartViewModel", MethodName: "load", FileName: "CartViewModel.kt", LineNumber: 44},
+			},
+		},
+		{
+			name:  "inlined from other class",
+			frame: model.StackFrameDTO{ClassName: "a.b", MethodName: "b", FileName: "SourceFile", LineNumber: 5},
+			expected: []model.StackFrameDTO{
+				{ClassName: "com.example.cart.Prices", MethodName: "format", FileName: "SourceFile", LineNumber: 12},
+				{ClassName: "com.example.cart.CartViewModel", MethodName: "refresh", FileName: "CartViewModel.kt", LineNumber: 60},
+			},
+		},
+		{
+			name:  "same name different range",
+			frame: model.StackFrameDTO{ClassName: "a.b", MethodName: "b", FileName: "SourceFile", LineNumber: 7},
+			expected: []model.StackFrameDTO{
+				{ClassName: "com.example.cart.CartViewModel", MethodName: "refresh", FileName: "CartViewModel.kt", LineNumber: 62},
+			},
+		},
+		{
+			name:  "no line numbers",
+			frame: model.StackFrameDTO{ClassName: "a.b", MethodName: "c", FileName: "SourceFile", LineNumber: 0},
+			expected: []model.StackFrameDTO{
+				{ClassName: "com.example.cart.CartViewModel", MethodName: "clear", FileName: "CartViewModel.kt", LineNumber: 0},
+			},
+		},
+		{
+			name:  "unknown method",
+			frame: model.StackFrameDTO{ClassName: "a.c", MethodName: "d", FileName: "SourceFile", LineNumber: 3},
+			expected: []model.StackFrameDTO{
+				{ClassName: "com.example.cart.CartActivity", MethodName: "d", FileName: "SourceFile", LineNumber: 3},
+			},
+		},
+		{
+			name:  "unknown class",
+			frame: model.StackFrameDTO{ClassName: "android.view.View", MethodName: "performClick", FileName: "View.java", LineNumber: 7448},
+			expected: []model.StackFrameDTO{
+				{ClassName: "android.view.View", MethodName: "performClick", FileName: "View.java", LineNumber: 7448},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := m.Frame(tt.frame)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Fatalf("Expected %+v but was %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFrames(t *testing.T) {
+	m, err := Parse(strings.NewReader(testMapping))
+	if err != nil {
+		t.Fatalf("Parse failed: %v\n", err)
+	}
+
+	frames := m.Frames([]model.StackFrameDTO{
+		{ClassName: "a.b", MethodName: "b", LineNumber: 5},
+		{ClassName: "a.c", MethodName: "onCreate", LineNumber: 1},
+	})
+	if len(frames) != 3 {
+		t.Fatalf("Expected 3 frames but was %d", len(frames))
+	}
+	if frames[2].ClassName != "com.example.cart.CartActivity" || frames[2].LineNumber != 20 {
+		t.Fatalf("Expected last frame to be CartActivity.onCreate:20 but was %+v", frames[2])
+	}
+}
+
+func TestCache(t *testing.T) {
+	cache := NewCache(2)
+	loads := 0
+	load := func() (*Mapping, error) {
+		loads++
+		return Parse(strings.NewReader(testMapping))
+	}
+
+	for _, key := range []string{"1", "1", "2", "1", "3", "2"} {
+		if _, err := cache.Get(key, load); err != nil {
+			t.Fatalf("Get failed: %v\n", err)
+		}
+	}
+	// '2' was evicted when '3' was added, since '1' was used more recently
+	if loads != 4 {
+		t.Fatalf("Expected 4 loads but was %d", loads)
+	}
+
+	var disabled *Cache
+	if _, err := disabled.Get("1", load); err != nil {
+		t.Fatalf("Get without cache failed: %v\n", err)
+	}
+}
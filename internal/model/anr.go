@@ -0,0 +1,81 @@
+package model
+
+type ThreadDTO struct {
+	Name   string          `json:"name" validate:"required"`
+	State  string          `json:"state"`
+	Frames []StackFrameDTO `json:"frames" validate:"dive"`
+}
+
+// AnrDTO is an Application Not Responding report, sent when the main thread
+// was blocked for too long to handle input
+type AnrDTO struct {
+	Id         string          `json:"id" validate:"required,uuid"`
+	SessionId  string          `param:"id" json:"sessionId" validate:"required,uuid"`
+	Duration   int64           `json:"duration" validate:"gte=0"`
+	MainThread []StackFrameDTO `json:"mainThread" validate:"required,dive"`
+	Threads    []ThreadDTO     `json:"threads" validate:"dive"`
+	Screen     string          `json:"screen"`
+	AppVersion string          `json:"appVersion"`
+	CreatedAt  int64           `json:"createdAt" validate:"required"`
+}
+
+type NewAnrData struct {
+	Id           string
+	SessionId    string
+	AppId        int
+	Fingerprint  string
+	Duration     int64
+	MainThread   []StackFrameDTO
+	Threads      []ThreadDTO
+	Screen       string
+	AppVersion   string
+	Deobfuscated bool
+	CreatedAt    int64
+}
+
+type AnrEntity struct {
+	Id             string
+	SessionId      string
+	InstallationId string
+	AppId          int
+	Fingerprint    string
+	Duration       int64
+	MainThread     []StackFrameDTO
+	Threads        []ThreadDTO
+	Screen         string
+	AppVersion     string
+	Deobfuscated   bool
+	CreatedAt      int64
+}
+
+type GetAnrDTO struct {
+	Id             string          `json:"id"`
+	SessionId      string          `json:"sessionId"`
+	InstallationId string          `json:"installationId"`
+	Fingerprint    string          `json:"fingerprint"`
+	Duration       int64           `json:"duration"`
+	MainThread     []StackFrameDTO `json:"mainThread"`
+	Threads        []ThreadDTO     `json:"threads"`
+	Screen         string          `json:"screen"`
+	AppVersion     string          `json:"appVersion"`
+	Deobfuscated   bool            `json:"deobfuscated"`
+	CreatedAt      int64           `json:"createdAt"`
+}
+
+// AnrRateEntity counts the ANRs of an app version. Sessions without an app
+// version are counted under the empty version.
+type AnrRateEntity struct {
+	AppVersion  string
+	Sessions    int
+	AnrSessions int
+	Anrs        int
+}
+
+type AnrRateDTO struct {
+	AppVersion  string `json:"appVersion"`
+	Sessions    int    `json:"sessions"`
+	AnrSessions int    `json:"anrSessions"`
+	Anrs        int    `json:"anrs"`
+	// Share of sessions with at least one ANR
+	Rate float64 `json:"rate"`
+}
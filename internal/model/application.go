@@ -23,11 +23,15 @@ type GetApplicationDTO struct {
 }
 
 type ApplicationDataEntity struct {
-	Installations []InstallationEntity
-	Sessions      []SessionEntity
+	Installations          []InstallationEntity
+	Sessions               []SessionEntity
+	NextInstallationCursor string
+	NextSessionCursor      string
 }
 
 type ApplicationDataDTO struct {
-	Installations []InstallationDTO `json:"installations"`
-	Sessions      []SessionDTO      `json:"sessions"`
+	Installations     []InstallationDTO `json:"installations"`
+	Sessions          []SessionDTO      `json:"sessions"`
+	InstallationsPage PageDTO           `json:"installationsPage"`
+	SessionsPage      PageDTO           `json:"sessionsPage"`
 }
@@ -51,6 +51,16 @@ type UserDTO struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// Roles of users in a team. Owners and admins manage the team and the api
+// keys of its apps, developers can change its apps, and viewers can only
+// read their data
+const (
+	RoleOwner     = "owner"
+	RoleAdmin     = "admin"
+	RoleDeveloper = "developer"
+	RoleViewer    = "viewer"
+)
+
 type NewTeamUserLinkData struct {
 	TeamId int
 	UserId int
@@ -60,19 +70,89 @@ type NewTeamUserLinkData struct {
 type TeamUserLinkDTO struct {
 	TeamId int    `param:"id" validate:"required"`
 	UserId int    `json:"userId" validate:"required"`
-	Role   string `json:"role" validate:"required"`
+	Role   string `json:"role" validate:"required,oneof=owner admin developer viewer"`
 }
 
+// Scopes of api keys
+const (
+	// Send any data of the app to the ingestion routes
+	ScopeIngest = "ingest"
+	// Prefix of scopes which only allow sending one type of data, e.g.
+	// 'ingest:events'
+	ScopeIngestPrefix = "ingest:"
+	// Read the data of the app through the GET routes of /app/v1, e.g. for
+	// CI tooling
+	ScopeRead = "read"
+)
+
 type NewApiKeyData struct {
-	Key   string
-	AppId int
+	Key       string
+	AppId     int
+	Name      string
+	Scopes    []string
+	CreatedBy int
+	CreatedAt int64
+	// 0 if the key never expires
+	ExpiresAt int64
 }
 
 type NewApiKeyDTO struct {
-	AppId int `param:"appId" validate:"required"`
+	AppId int    `param:"appId" validate:"required"`
+	Name  string `json:"name" validate:"max=128"`
+	// Keys without scopes can ingest any data
+	Scopes []string `json:"scopes" validate:"max=16,unique,dive,apikeyscope"`
+	// Milliseconds since epoch after which the key stops working. Keys
+	// without it never expire
+	ExpiresAt int64 `json:"expiresAt" validate:"gte=0"`
 }
 
 type ApiKeyDTO struct {
 	Key   string `json:"id" validate:"required"`
 	AppId int    `json:"appId" validate:"required"`
 }
+
+// RotateApiKeyDTO replaces a key with a new one with the same scopes. The
+// replaced key keeps working for GracePeriod seconds, so clients can switch
+// to the new key without dropping data.
+type RotateApiKeyDTO struct {
+	GracePeriod *int64 `json:"gracePeriod" validate:"omitempty,gte=0,lte=2592000"`
+	ExpiresAt   int64  `json:"expiresAt" validate:"gte=0"`
+}
+
+// States of an api key
+const (
+	ApiKeyActive  = "active"
+	ApiKeyExpired = "expired"
+	ApiKeyRevoked = "revoked"
+)
+
+// Keys are used for every request of their app, so the time they were last
+// used is only updated once per minute. In milliseconds.
+const ApiKeyUsageResolution = int64(60 * 1000)
+
+// ApiKeyEntity is the metadata of an api key, without the key itself. Times
+// are 0 if they are not set, and CreatedAt and CreatedBy are 0 for keys
+// created before they were recorded.
+type ApiKeyEntity struct {
+	Id         int
+	AppId      int
+	Name       string
+	Scopes     []string
+	CreatedAt  int64
+	CreatedBy  int
+	LastUsedAt int64
+	ExpiresAt  int64
+	RevokedAt  int64
+}
+
+type GetApiKeyDTO struct {
+	Id         int      `json:"id"`
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	Status     string   `json:"status"`
+	CreatedAt  int64    `json:"createdAt,omitempty"`
+	CreatedBy  int      `json:"createdBy,omitempty"`
+	LastUsedAt int64    `json:"lastUsedAt,omitempty"`
+	ExpiresAt  int64    `json:"expiresAt,omitempty"`
+	RevokedAt  int64    `json:"revokedAt,omitempty"`
+}
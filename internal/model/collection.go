@@ -1,7 +1,56 @@
 package model
 
+import "fmt"
+
 type CollectionDTO struct {
 	Session *SessionDTO `json:"session" validation:"omitnil,required"`
 	Events  []EventDTO  `json:"events"`
 	Traces  []TraceDTO  `json:"traces"`
+	Crashes []CrashDTO  `json:"crashes"`
+	Anrs    []AnrDTO    `json:"anrs"`
+	// HTTP calls made by the app
+	NetworkRequests []NetworkRequestDTO `json:"networkRequests"`
+}
+
+// NewCollectionData is a collection prepared for storing, which is written
+// in a single transaction
+type NewCollectionData struct {
+	Session         *NewSessionData
+	Events          []NewEventData
+	Traces          []NewTraceData
+	Crashes         []NewCrashData
+	Anrs            []NewAnrData
+	NetworkRequests []NewNetworkRequestData
+}
+
+// Kinds of the items of a collection, named like their fields in
+// CollectionDTO
+const (
+	CollectionSession         = "session"
+	CollectionEvents          = "events"
+	CollectionTraces          = "traces"
+	CollectionCrashes         = "crashes"
+	CollectionAnrs            = "anrs"
+	CollectionNetworkRequests = "networkRequests"
+)
+
+// CollectionItemError is returned if an item of a collection could not be
+// written, in which case nothing of the collection is. Index is the position
+// of the item among the items of its Kind, or -1 if the failing item is
+// not known.
+type CollectionItemError struct {
+	Kind  string
+	Index int
+	Err   error
+}
+
+func (e *CollectionItemError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("%s: %v", e.Kind, e.Err)
+	}
+	return fmt.Sprintf("%s[%d]: %v", e.Kind, e.Index, e.Err)
+}
+
+func (e *CollectionItemError) Unwrap() error {
+	return e.Err
 }
@@ -1,8 +1,16 @@
 package model
 
 type Config struct {
-	Port     int `goenv:"OBSERVE_API_PORT,default=8080"`
-	Database DatabaseConfig
+	Port int `goenv:"OBSERVE_API_PORT,default=8080"`
+	// Max size in bytes of a request body after it has been decompressed
+	MaxDecompressedBytes int `goenv:"OBSERVE_API_MAX_DECOMPRESSED_BYTES,default=10485760"`
+	// Max size in bytes of an uploaded R8/ProGuard mapping
+	MaxMappingBytes int `goenv:"OBSERVE_API_MAX_MAPPING_BYTES,default=104857600"`
+	// Number of parsed mappings kept in memory
+	MappingCacheSize int `goenv:"OBSERVE_API_MAPPING_CACHE_SIZE,default=16"`
+	Database         DatabaseConfig
+	Queue            QueueConfig
+	RateLimit        RateLimitConfig
 }
 
 type DatabaseConfig struct {
@@ -13,3 +21,26 @@ type DatabaseConfig struct {
 	Host     string `goenv:"OBSERVE_DB_HOST,required"`
 	Schema   string `goenv:"OBSERVE_DB_SCHEMA,required"`
 }
+
+type QueueConfig struct {
+	Dir                 string `goenv:"OBSERVE_QUEUE_DIR,default=data/queue"`
+	Capacity            int    `goenv:"OBSERVE_QUEUE_CAPACITY,default=1000"`
+	Workers             int    `goenv:"OBSERVE_QUEUE_WORKERS,default=4"`
+	MaxAttempts         int    `goenv:"OBSERVE_QUEUE_MAX_ATTEMPTS,default=5"`
+	BackoffMillis       int    `goenv:"OBSERVE_QUEUE_BACKOFF_MS,default=500"`
+	DrainTimeoutSeconds int    `goenv:"OBSERVE_QUEUE_DRAIN_TIMEOUT,default=30"`
+}
+
+// RateLimitConfig limits the ingestion requests of each api key and of each
+// installation. A limit with 0 requests per minute or burst is disabled.
+type RateLimitConfig struct {
+	// 'memory' limits the requests of each replica on its own, 'postgres'
+	// shares the limits across replicas
+	Store                 string `goenv:"OBSERVE_RATE_LIMIT_STORE,default=memory"`
+	KeyPerMinute          int    `goenv:"OBSERVE_RATE_LIMIT_KEY_PER_MINUTE,default=6000"`
+	KeyBurst              int    `goenv:"OBSERVE_RATE_LIMIT_KEY_BURST,default=500"`
+	InstallationPerMinute int    `goenv:"OBSERVE_RATE_LIMIT_INSTALLATION_PER_MINUTE,default=60"`
+	InstallationBurst     int    `goenv:"OBSERVE_RATE_LIMIT_INSTALLATION_BURST,default=20"`
+	// Seconds between writes of the counts of throttled requests
+	FlushSeconds int `goenv:"OBSERVE_RATE_LIMIT_FLUSH_INTERVAL,default=10"`
+}
@@ -0,0 +1,94 @@
+package model
+
+const (
+	IssueTypeCrash = "crash"
+	IssueTypeAnr   = "anr"
+)
+
+type StackFrameDTO struct {
+	ClassName  string `json:"className" validate:"required"`
+	MethodName string `json:"methodName" validate:"required"`
+	FileName   string `json:"fileName"`
+	LineNumber int    `json:"lineNumber"`
+}
+
+type CrashDTO struct {
+	Id             string          `json:"id" validate:"required,uuid"`
+	SessionId      string          `param:"id" json:"sessionId" validate:"required,uuid"`
+	ExceptionClass string          `json:"exceptionClass" validate:"required"`
+	Message        string          `json:"message"`
+	Frames         []StackFrameDTO `json:"frames" validate:"dive"`
+	ThreadName     string          `json:"threadName"`
+	Screen         string          `json:"screen"`
+	AppVersion     string          `json:"appVersion"`
+	CreatedAt      int64           `json:"createdAt" validate:"required"`
+}
+
+type NewCrashData struct {
+	Id             string
+	SessionId      string
+	AppId          int
+	Fingerprint    string
+	ExceptionClass string
+	Message        string
+	Frames         []StackFrameDTO
+	ThreadName     string
+	Screen         string
+	AppVersion     string
+	Deobfuscated   bool
+	CreatedAt      int64
+}
+
+type CrashEntity struct {
+	Id             string
+	SessionId      string
+	AppId          int
+	Fingerprint    string
+	ExceptionClass string
+	Message        string
+	Frames         []StackFrameDTO
+	ThreadName     string
+	Screen         string
+	AppVersion     string
+	Deobfuscated   bool
+	CreatedAt      int64
+}
+
+type GetCrashDTO struct {
+	Id             string          `json:"id"`
+	SessionId      string          `json:"sessionId"`
+	Fingerprint    string          `json:"fingerprint"`
+	ExceptionClass string          `json:"exceptionClass"`
+	Message        string          `json:"message"`
+	Frames         []StackFrameDTO `json:"frames"`
+	ThreadName     string          `json:"threadName"`
+	Screen         string          `json:"screen"`
+	AppVersion     string          `json:"appVersion"`
+	Deobfuscated   bool            `json:"deobfuscated"`
+	CreatedAt      int64           `json:"createdAt"`
+}
+
+// IssueEntity is a group of crashes or ANRs sharing the same fingerprint.
+// The counts only include occurrences within the requested time range.
+type IssueEntity struct {
+	Fingerprint    string
+	AppId          int
+	Type           string
+	ExceptionClass string
+	Message        string
+	FirstSeen      int64
+	LastSeen       int64
+	Occurrences    int
+	Sessions       int
+}
+
+type IssueDTO struct {
+	Fingerprint    string `json:"fingerprint"`
+	Type           string `json:"type"`
+	ExceptionClass string `json:"exceptionClass"`
+	Message        string `json:"message"`
+	FirstSeen      int64  `json:"firstSeen"`
+	LastSeen       int64  `json:"lastSeen"`
+	Occurrences    int    `json:"occurrences"`
+	Sessions       int    `json:"sessions"`
+}
@@ -6,6 +6,7 @@ type NewEventData struct {
 	AppId          int
 	Type           string
 	SerializedData string
+	Attributes     map[string]any
 	CreatedAt      int64
 }
 
@@ -15,6 +16,7 @@ type EventEntity struct {
 	AppId          int
 	Type           string
 	SerializedData string
+	Attributes     map[string]any
 	CreatedAt      int64
 }
 
@@ -23,5 +25,23 @@ type EventDTO struct {
 	SessionId      string `json:"sessionId" validate:"required,uuid"`
 	Type           string `json:"type" validate:"required"`
 	SerializedData string `json:"serializedData"`
-	CreatedAt      int64  `json:"createdAt" validate:"required"`
+	// Typed attributes which events can be searched by. Values must be
+	// strings, numbers or booleans.
+	Attributes map[string]any `json:"attributes,omitempty" validate:"max=64,dive,keys,attributekey,endkeys,attributevalue"`
+	CreatedAt  int64          `json:"createdAt" validate:"required"`
+}
+
+// EventSearchQuery selects the events of an app of a type, if set, whose
+// attributes match every predicate
+type EventSearchQuery struct {
+	Type       string
+	Predicates []AttributePredicate
+}
+
+// AttributePredicate matches records with an attribute Key equal to any of
+// Values. A value in a query is ambiguous, fx. '42' may be a string or a
+// number, so it has a candidate for each type it can be.
+type AttributePredicate struct {
+	Key    string
+	Values []any
 }
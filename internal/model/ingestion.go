@@ -0,0 +1,146 @@
+package model
+
+const (
+	IngestionStatusPending   = "pending"
+	IngestionStatusRetrying  = "retrying"
+	IngestionStatusCompleted = "completed"
+	IngestionStatusFailed    = "failed"
+)
+
+// IngestionFailure describes why a collection could not be stored. Path names
+// the item which failed in the same format as validation errors, fx.
+// 'events[3]', and is empty if the failure is not caused by a single item
+type IngestionFailure struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+type NewIngestionBatchData struct {
+	Id        string
+	AppId     int
+	CreatedAt int64
+}
+
+type IngestionBatchResultData struct {
+	Id        string
+	AppId     int
+	Status    string
+	Attempts  int
+	Failures  []IngestionFailure
+	CreatedAt int64
+	UpdatedAt int64
+}
+
+type IngestionBatchEntity struct {
+	Id        string
+	AppId     int
+	Status    string
+	Attempts  int
+	Failures  []IngestionFailure
+	CreatedAt int64
+	UpdatedAt int64
+}
+
+type IngestionBatchDTO struct {
+	Id        string             `json:"id"`
+	Status    string             `json:"status"`
+	Attempts  int                `json:"attempts"`
+	Failures  []IngestionFailure `json:"failures"`
+	CreatedAt int64              `json:"createdAt"`
+	UpdatedAt int64              `json:"updatedAt"`
+}
+
+// SamplingRule keeps Rate of the sessions it matches, from 0 to 1. Either
+// all events and traces of a session are kept, or none of them.
+type SamplingRule struct {
+	// Only match sessions which crashed, or which did not. Matches both if
+	// not set
+	Crashed *bool `json:"crashed,omitempty"`
+	// Only match sessions of this app version. Matches every version if empty
+	AppVersion string  `json:"appVersion,omitempty" validate:"max=128"`
+	Rate       float64 `json:"rate" validate:"gte=0,lte=1"`
+}
+
+// IngestionSettingsDTO limits the events and traces stored for an app. A
+// quota of 0 is unlimited. The first sampling rule matching a session
+// applies, and sessions matching no rule are kept.
+type IngestionSettingsDTO struct {
+	MonthlyEventQuota int64          `json:"monthlyEventQuota" validate:"gte=0"`
+	MonthlyTraceQuota int64          `json:"monthlyTraceQuota" validate:"gte=0"`
+	SamplingRules     []SamplingRule `json:"samplingRules" validate:"max=32,dive"`
+	UpdatedAt         int64          `json:"updatedAt,omitempty"`
+}
+
+type IngestionSettingsData struct {
+	AppId             int
+	MonthlyEventQuota int64
+	MonthlyTraceQuota int64
+	SamplingRules     []SamplingRule
+	UpdatedBy         int
+	UpdatedAt         int64
+}
+
+type IngestionSettingsEntity struct {
+	AppId             int
+	MonthlyEventQuota int64
+	MonthlyTraceQuota int64
+	SamplingRules     []SamplingRule
+	UpdatedBy         int
+	UpdatedAt         int64
+}
+
+// IngestionUsageData adds a batch to the usage of an app in the month
+// starting at Month. Events and Traces are the ones kept by sampling, which
+// are stored as far as the quotas allow.
+type IngestionUsageData struct {
+	AppId         int
+	Month         int64
+	Events        int
+	Traces        int
+	SampledEvents int
+	SampledTraces int
+	EventQuota    int64
+	TraceQuota    int64
+}
+
+// IngestionUsageEntity counts the events and traces of an app in a month
+// which were stored, dropped by sampling, or dropped for exceeding the quota
+type IngestionUsageEntity struct {
+	Month           int64
+	Events          int64
+	Traces          int64
+	SampledEvents   int64
+	SampledTraces   int64
+	OverQuotaEvents int64
+	OverQuotaTraces int64
+}
+
+type IngestionUsageDTO struct {
+	Month  int64         `json:"month"`
+	Events QuotaUsageDTO `json:"events"`
+	Traces QuotaUsageDTO `json:"traces"`
+}
+
+// QuotaUsageDTO compares the records stored in a month with the quota. The
+// quota fields are left out if the quota is unlimited.
+type QuotaUsageDTO struct {
+	Stored    int64 `json:"stored"`
+	Sampled   int64 `json:"sampled"`
+	OverQuota int64 `json:"overQuota"`
+	// Records stored by the end of the month, if they keep coming at the
+	// rate of the month so far
+	Projected int64  `json:"projected"`
+	Quota     int64  `json:"quota,omitempty"`
+	Remaining *int64 `json:"remaining,omitempty"`
+	// Fraction of the quota used so far
+	Used *float64 `json:"used,omitempty"`
+}
+
+// IngestionDropsDTO counts the events and traces of a request which were not
+// stored, since they were sampled out or exceeded the quota of the app
+type IngestionDropsDTO struct {
+	SampledEvents   int `json:"sampledEvents"`
+	SampledTraces   int `json:"sampledTraces"`
+	OverQuotaEvents int `json:"overQuotaEvents"`
+	OverQuotaTraces int `json:"overQuotaTraces"`
+}
@@ -0,0 +1,23 @@
+package model
+
+type NewMappingData struct {
+	AppId     int
+	Version   string
+	Mapping   []byte
+	CreatedAt int64
+}
+
+// MappingEntity describes an uploaded mapping. The mapping itself is only
+// loaded on demand, since it can be several megabytes large
+type MappingEntity struct {
+	AppId     int
+	Version   string
+	Size      int
+	CreatedAt int64
+}
+
+type MappingDTO struct {
+	Version   string `json:"version"`
+	Size      int    `json:"size"`
+	CreatedAt int64  `json:"createdAt"`
+}
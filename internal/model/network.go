@@ -0,0 +1,94 @@
+package model
+
+// NetworkRequestDTO is a single HTTP call made by the app. UrlTemplate is
+// the url without its query, and with ids replaced by placeholders, fx.
+// 'https://api.example.com/users/{id}'. StatusCode is 0 if the call failed
+// before a response was received, in which case FailureReason tells why.
+// TraceId links the call to the trace of the span which made it.
+type NetworkRequestDTO struct {
+	Id            string `json:"id" validate:"required,uuid"`
+	SessionId     string `json:"sessionId" validate:"required,uuid"`
+	TraceId       string `json:"traceId" validate:"isdefault|uuid"`
+	UrlTemplate   string `json:"urlTemplate" validate:"required,url"`
+	Method        string `json:"method" validate:"required,oneof=GET HEAD POST PUT PATCH DELETE OPTIONS CONNECT TRACE"`
+	StatusCode    int    `json:"statusCode" validate:"gte=0,lte=599"`
+	Duration      int64  `json:"duration" validate:"gte=0"`
+	RequestSize   int64  `json:"requestSize" validate:"gte=0"`
+	ResponseSize  int64  `json:"responseSize" validate:"gte=0"`
+	FailureReason string `json:"failureReason" validate:"required_if=StatusCode 0"`
+	StartedAt     int64  `json:"startTime" validate:"required"`
+}
+
+type NewNetworkRequestData struct {
+	Id            string
+	SessionId     string
+	TraceId       string
+	AppId         int
+	Host          string
+	UrlTemplate   string
+	Method        string
+	StatusCode    int
+	Duration      int64
+	RequestSize   int64
+	ResponseSize  int64
+	FailureReason string
+	StartedAt     int64
+}
+
+type NetworkRequestEntity struct {
+	Id            string
+	SessionId     string
+	TraceId       string
+	AppId         int
+	Host          string
+	UrlTemplate   string
+	Method        string
+	StatusCode    int
+	Duration      int64
+	RequestSize   int64
+	ResponseSize  int64
+	FailureReason string
+	StartedAt     int64
+}
+
+// NetworkQuery selects which network requests of an app are aggregated.
+// Without a method and url template all endpoints are included, and without
+// an interval all requests in the time range form one bucket.
+type NetworkQuery struct {
+	From        int64
+	To          int64
+	Release     string
+	Host        string
+	Method      string
+	UrlTemplate string
+	Interval    string
+	Limit       int
+}
+
+// NetworkEndpointEntity aggregates the requests to an endpoint in a bucket.
+// Errors are requests which failed or got a 4xx or 5xx response. Durations
+// are in milliseconds.
+type NetworkEndpointEntity struct {
+	Bucket          int64
+	Method          string
+	UrlTemplate     string
+	Requests        int
+	Errors          int
+	DurationP50     float64
+	DurationP95     float64
+	DurationP99     float64
+	AvgResponseSize float64
+}
+
+type NetworkEndpointDTO struct {
+	Start           int64   `json:"start,omitempty"`
+	Method          string  `json:"method,omitempty"`
+	UrlTemplate     string  `json:"urlTemplate,omitempty"`
+	Requests        int     `json:"requests"`
+	Errors          int     `json:"errors"`
+	ErrorRate       float64 `json:"errorRate"`
+	DurationP50     float64 `json:"durationP50"`
+	DurationP95     float64 `json:"durationP95"`
+	DurationP99     float64 `json:"durationP99"`
+	AvgResponseSize float64 `json:"avgResponseSize"`
+}
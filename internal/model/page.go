@@ -0,0 +1,69 @@
+package model
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultPageLimit = 100
+	MaxPageLimit     = 1000
+)
+
+// PageQuery selects one page of a list ordered by time and id.
+// From and To are inclusive bounds on the time of the records, and are
+// ignored when 0. Cursor is the NextCursor of the previous page.
+// Release limits lists of a whole app to records of that app version.
+type PageQuery struct {
+	From    int64
+	To      int64
+	Limit   int
+	Cursor  string
+	Release string
+}
+
+// PageCursor is the position of the last record of a page, which the next
+// page continues after
+type PageCursor struct {
+	Time int64
+	Id   string
+}
+
+type PageDTO struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"nextCursor"`
+}
+
+// NormalizedLimit returns the limit of the query, clamped to MaxPageLimit,
+// or DefaultPageLimit if none is set
+func (q PageQuery) NormalizedLimit() int {
+	if q.Limit <= 0 {
+		return DefaultPageLimit
+	}
+	return min(q.Limit, MaxPageLimit)
+}
+
+// EncodeCursor returns an opaque cursor pointing after the given record
+func EncodeCursor(time int64, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s", time, id)))
+}
+
+func DecodeCursor(cursor string) (PageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return PageCursor{}, fmt.Errorf("Malformed cursor")
+	}
+
+	timeString, id, ok := strings.Cut(string(data), ":")
+	if !ok {
+		return PageCursor{}, fmt.Errorf("Malformed cursor")
+	}
+	time, err := strconv.ParseInt(timeString, 10, 64)
+	if err != nil {
+		return PageCursor{}, fmt.Errorf("Malformed cursor")
+	}
+
+	return PageCursor{Time: time, Id: id}, nil
+}
@@ -0,0 +1,39 @@
+package model
+
+// Requests are throttled by the limit of their api key, or of the
+// installation which sent them
+const (
+	ThrottleReasonKey          = "key"
+	ThrottleReasonInstallation = "installation"
+)
+
+// ThrottledRequestsData counts the requests of an app throttled for reason
+// within the hour starting at Bucket
+type ThrottledRequestsData struct {
+	AppId  int
+	Bucket int64
+	Reason string
+	Count  int
+}
+
+// ThrottledRequestsQuery selects how the throttled requests of an app are
+// aggregated. Without an interval all requests in the time range form one
+// bucket.
+type ThrottledRequestsQuery struct {
+	From     int64
+	To       int64
+	Interval string
+}
+
+type ThrottledRequestsEntity struct {
+	Bucket int64
+	Reason string
+	Count  int
+}
+
+type ThrottledRequestsDTO struct {
+	Start        int64 `json:"start,omitempty"`
+	Key          int   `json:"key"`
+	Installation int   `json:"installation"`
+	Total        int   `json:"total"`
+}
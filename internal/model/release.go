@@ -0,0 +1,60 @@
+package model
+
+// ReleaseEntity is an app version, which is created when the first session
+// reporting it is received
+type ReleaseEntity struct {
+	AppId       int
+	Version     string
+	VersionCode int64
+	FirstSeen   int64
+	LastSeen    int64
+}
+
+type ReleaseDTO struct {
+	Version     string `json:"version"`
+	VersionCode int64  `json:"versionCode"`
+	FirstSeen   int64  `json:"firstSeen"`
+	LastSeen    int64  `json:"lastSeen"`
+}
+
+// ReleaseHealthDTO summarizes the sessions of a release within a time range.
+// Adoption is the share of all sessions of the app in that range.
+type ReleaseHealthDTO struct {
+	ReleaseDTO
+	Sessions                  int                   `json:"sessions"`
+	CrashedSessions           int                   `json:"crashedSessions"`
+	CrashFreeSessionRate      float64               `json:"crashFreeSessionRate"`
+	Installations             int                   `json:"installations"`
+	CrashedInstallations      int                   `json:"crashedInstallations"`
+	CrashFreeInstallationRate float64               `json:"crashFreeInstallationRate"`
+	AnrSessions               int                   `json:"anrSessions"`
+	AnrRate                   float64               `json:"anrRate"`
+	Adoption                  float64               `json:"adoption"`
+	Comparison                *ReleaseComparisonDTO `json:"comparison,omitempty"`
+}
+
+// ReleaseComparisonDTO compares a release with the one before it. Changes
+// are the rate of the release minus the rate of the previous release.
+type ReleaseComparisonDTO struct {
+	PreviousVersion   string  `json:"previousVersion"`
+	CrashRate         float64 `json:"crashRate"`
+	PreviousCrashRate float64 `json:"previousCrashRate"`
+	CrashRateChange   float64 `json:"crashRateChange"`
+	PreviousAnrRate   float64 `json:"previousAnrRate"`
+	AnrRateChange     float64 `json:"anrRateChange"`
+}
+
+type ReleaseAdoptionDTO struct {
+	Start         int64                       `json:"start"`
+	Sessions      int                         `json:"sessions"`
+	Installations int                         `json:"installations"`
+	Releases      []ReleaseAdoptionVersionDTO `json:"releases"`
+}
+
+type ReleaseAdoptionVersionDTO struct {
+	Version           string  `json:"version"`
+	Sessions          int     `json:"sessions"`
+	Installations     int     `json:"installations"`
+	SessionShare      float64 `json:"sessionShare"`
+	InstallationShare float64 `json:"installationShare"`
+}
@@ -50,3 +50,316 @@ type MemoryUsageEntity struct {
 	AvailableHeapSpace int64
 	CreatedAt          int64
 }
+
+// MemorySummaryQuery selects how memory samples are aggregated. Without an
+// interval all samples in the time range form one bucket, and without a
+// group by they are not broken down by installation data.
+type MemorySummaryQuery struct {
+	From     int64
+	To       int64
+	Interval string
+	GroupBy  string
+	Release  string
+}
+
+// MemoryPercentilesEntity holds percentiles of the used memory in bytes, and
+// of the used memory as a share of the max memory of a bucket
+type MemoryPercentilesEntity struct {
+	Bucket   int64
+	Group    string
+	Samples  int
+	Sessions int
+	UsedP50  float64
+	UsedP90  float64
+	UsedP99  float64
+	RatioP50 float64
+	RatioP90 float64
+	RatioP99 float64
+}
+
+// MemoryTrendEntity describes how the used memory of a session changed
+// across its samples. Increases counts the samples that used more memory
+// than the one before, and Slope is the linear trend in bytes per millisecond.
+type MemoryTrendEntity struct {
+	SessionId      string
+	InstallationId string
+	Samples        int
+	Increases      int
+	FirstUsed      int64
+	LastUsed       int64
+	MaxMemory      int64
+	FirstAt        int64
+	LastAt         int64
+	Slope          float64
+}
+
+type PercentilesDTO struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+}
+
+type MemorySummaryDTO struct {
+	Start      int64              `json:"start,omitempty"`
+	Value      *string            `json:"value,omitempty"`
+	Samples    int                `json:"samples"`
+	Sessions   int                `json:"sessions"`
+	UsedMemory PercentilesDTO     `json:"usedMemory"`
+	UsedRatio  PercentilesDTO     `json:"usedRatio"`
+	Series     []MemorySummaryDTO `json:"series,omitempty"`
+}
+
+// MemoryLeakDTO is the memory trend of a session. Growth is in bytes per
+// minute, and TimeToOom the milliseconds until the used memory reaches the
+// max memory at that rate, if it grows.
+type MemoryLeakDTO struct {
+	SessionId       string  `json:"sessionId"`
+	InstallationId  string  `json:"installationId"`
+	Samples         int     `json:"samples"`
+	FirstUsed       int64   `json:"firstUsed"`
+	LastUsed        int64   `json:"lastUsed"`
+	MaxMemory       int64   `json:"maxMemory"`
+	Growth          float64 `json:"growth"`
+	IncreasingShare float64 `json:"increasingShare"`
+	LastRatio       float64 `json:"lastRatio"`
+	TimeToOom       *int64  `json:"timeToOom,omitempty"`
+	LeakSuspected   bool    `json:"leakSuspected"`
+}
+
+type NewCpuUsageDTO struct {
+	Id             string `json:"id" validate:"required,uuid"`
+	SessionId      string `json:"sessionId" validate:"required,uuid"`
+	InstallationId string `json:"installationId" validate:"required,uuid"`
+	// Share of the capacity of all cores used by the app, in percent
+	Usage float64 `json:"usage" validate:"gte=0,lte=100"`
+	// Milliseconds of CPU time spent in user and kernel mode since the
+	// previous sample
+	UserTime   int64 `json:"userTime" validate:"gte=0"`
+	SystemTime int64 `json:"systemTime" validate:"gte=0"`
+	Cores      int   `json:"cores" validate:"gte=0"`
+	CreatedAt  int64 `json:"createdAt" validate:"required"`
+}
+
+type NewCpuUsageData struct {
+	Id             string
+	SessionId      string
+	InstallationId string
+	AppId          int
+	Usage          float64
+	UserTime       int64
+	SystemTime     int64
+	Cores          int
+	CreatedAt      int64
+}
+
+type GetCpuUsageDTO struct {
+	Id             string  `json:"id"`
+	SessionId      string  `json:"sessionId"`
+	InstallationId string  `json:"installationId"`
+	AppId          int     `json:"appId"`
+	Usage          float64 `json:"usage"`
+	UserTime       int64   `json:"userTime"`
+	SystemTime     int64   `json:"systemTime"`
+	Cores          int     `json:"cores"`
+	CreatedAt      int64   `json:"createdAt"`
+}
+
+type CpuUsageEntity struct {
+	Id             string
+	SessionId      string
+	InstallationId string
+	AppId          int
+	Usage          float64
+	UserTime       int64
+	SystemTime     int64
+	Cores          int
+	CreatedAt      int64
+}
+
+// NewFrameMetricsDTO counts the frames rendered on a screen since the
+// previous sample. Janky frames missed their deadline, and frozen frames
+// took longer than 700ms to render.
+type NewFrameMetricsDTO struct {
+	Id             string `json:"id" validate:"required,uuid"`
+	SessionId      string `json:"sessionId" validate:"required,uuid"`
+	InstallationId string `json:"installationId" validate:"required,uuid"`
+	Screen         string `json:"screen" validate:"required"`
+	TotalFrames    int    `json:"totalFrames" validate:"gte=0"`
+	JankyFrames    int    `json:"jankyFrames" validate:"gte=0,ltefield=TotalFrames"`
+	FrozenFrames   int    `json:"frozenFrames" validate:"gte=0,ltefield=TotalFrames"`
+	CreatedAt      int64  `json:"createdAt" validate:"required"`
+}
+
+type NewFrameMetricsData struct {
+	Id             string
+	SessionId      string
+	InstallationId string
+	AppId          int
+	Screen         string
+	TotalFrames    int
+	JankyFrames    int
+	FrozenFrames   int
+	CreatedAt      int64
+}
+
+type GetFrameMetricsDTO struct {
+	Id             string `json:"id"`
+	SessionId      string `json:"sessionId"`
+	InstallationId string `json:"installationId"`
+	AppId          int    `json:"appId"`
+	Screen         string `json:"screen"`
+	TotalFrames    int    `json:"totalFrames"`
+	JankyFrames    int    `json:"jankyFrames"`
+	FrozenFrames   int    `json:"frozenFrames"`
+	CreatedAt      int64  `json:"createdAt"`
+}
+
+type FrameMetricsEntity struct {
+	Id             string
+	SessionId      string
+	InstallationId string
+	AppId          int
+	Screen         string
+	TotalFrames    int
+	JankyFrames    int
+	FrozenFrames   int
+	CreatedAt      int64
+}
+
+// FrameSummaryEntity sums the frames rendered on a screen
+type FrameSummaryEntity struct {
+	Screen       string
+	Sessions     int
+	TotalFrames  int64
+	JankyFrames  int64
+	FrozenFrames int64
+}
+
+type FrameSummaryDTO struct {
+	Screen       string  `json:"screen"`
+	Sessions     int     `json:"sessions"`
+	TotalFrames  int64   `json:"totalFrames"`
+	JankyFrames  int64   `json:"jankyFrames"`
+	JankyRate    float64 `json:"jankyRate"`
+	FrozenFrames int64   `json:"frozenFrames"`
+	FrozenRate   float64 `json:"frozenRate"`
+}
+
+const (
+	ThermalStateNone      = "none"
+	ThermalStateLight     = "light"
+	ThermalStateModerate  = "moderate"
+	ThermalStateSevere    = "severe"
+	ThermalStateCritical  = "critical"
+	ThermalStateEmergency = "emergency"
+	ThermalStateShutdown  = "shutdown"
+)
+
+// NewBatteryStateDTO is a snapshot of the battery and thermal state of the
+// device. ThermalState mirrors the thermal status of Android's PowerManager.
+type NewBatteryStateDTO struct {
+	Id             string `json:"id" validate:"required,uuid"`
+	SessionId      string `json:"sessionId" validate:"required,uuid"`
+	InstallationId string `json:"installationId" validate:"required,uuid"`
+	// Battery level in percent
+	Level    float64 `json:"level" validate:"gte=0,lte=100"`
+	Charging bool    `json:"charging"`
+	// Battery temperature in degrees Celsius
+	Temperature  float64 `json:"temperature"`
+	ThermalState string  `json:"thermalState" validate:"omitempty,oneof=none light moderate severe critical emergency shutdown"`
+	PowerSave    bool    `json:"powerSave"`
+	CreatedAt    int64   `json:"createdAt" validate:"required"`
+}
+
+type NewBatteryStateData struct {
+	Id             string
+	SessionId      string
+	InstallationId string
+	AppId          int
+	Level          float64
+	Charging       bool
+	Temperature    float64
+	ThermalState   string
+	PowerSave      bool
+	CreatedAt      int64
+}
+
+type GetBatteryStateDTO struct {
+	Id             string  `json:"id"`
+	SessionId      string  `json:"sessionId"`
+	InstallationId string  `json:"installationId"`
+	AppId          int     `json:"appId"`
+	Level          float64 `json:"level"`
+	Charging       bool    `json:"charging"`
+	Temperature    float64 `json:"temperature"`
+	ThermalState   string  `json:"thermalState"`
+	PowerSave      bool    `json:"powerSave"`
+	CreatedAt      int64   `json:"createdAt"`
+}
+
+type BatteryStateEntity struct {
+	Id             string
+	SessionId      string
+	InstallationId string
+	AppId          int
+	Level          float64
+	Charging       bool
+	Temperature    float64
+	ThermalState   string
+	PowerSave      bool
+	CreatedAt      int64
+}
+
+// NewNetworkUsageDTO sums the network requests of the app since the
+// previous sample
+type NewNetworkUsageDTO struct {
+	Id             string `json:"id" validate:"required,uuid"`
+	SessionId      string `json:"sessionId" validate:"required,uuid"`
+	InstallationId string `json:"installationId" validate:"required,uuid"`
+	// Fx. 'wifi', 'cellular' or 'none'
+	ConnectionType string `json:"connectionType"`
+	Requests       int    `json:"requests" validate:"gte=0"`
+	FailedRequests int    `json:"failedRequests" validate:"gte=0,ltefield=Requests"`
+	BytesSent      int64  `json:"bytesSent" validate:"gte=0"`
+	BytesReceived  int64  `json:"bytesReceived" validate:"gte=0"`
+	CreatedAt      int64  `json:"createdAt" validate:"required"`
+}
+
+type NewNetworkUsageData struct {
+	Id             string
+	SessionId      string
+	InstallationId string
+	AppId          int
+	ConnectionType string
+	Requests       int
+	FailedRequests int
+	BytesSent      int64
+	BytesReceived  int64
+	CreatedAt      int64
+}
+
+type GetNetworkUsageDTO struct {
+	Id             string `json:"id"`
+	SessionId      string `json:"sessionId"`
+	InstallationId string `json:"installationId"`
+	AppId          int    `json:"appId"`
+	ConnectionType string `json:"connectionType"`
+	Requests       int    `json:"requests"`
+	FailedRequests int    `json:"failedRequests"`
+	BytesSent      int64  `json:"bytesSent"`
+	BytesReceived  int64  `json:"bytesReceived"`
+	CreatedAt      int64  `json:"createdAt"`
+}
+
+type NetworkUsageEntity struct {
+	Id             string
+	SessionId      string
+	InstallationId string
+	AppId          int
+	ConnectionType string
+	Requests       int
+	FailedRequests int
+	BytesSent      int64
+	BytesReceived  int64
+	CreatedAt      int64
+}
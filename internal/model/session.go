@@ -4,13 +4,21 @@ type NewSessionData struct {
 	Id             string
 	InstallationId string
 	AppId          int
+	AppVersion     string
+	VersionCode    int64
+	BuildType      string
 	CreatedAt      int64
 	Crashed        bool
 }
 
+// SessionDTO is a session of an installation. AppVersion is the version
+// name of the app, fx. '2.3.1', and BuildType fx. 'release' or 'debug'.
 type SessionDTO struct {
 	Id             string `json:"id" validate:"required,uuid"`
 	InstallationId string `json:"installationId" validate:"required"`
+	AppVersion     string `json:"appVersion"`
+	VersionCode    int64  `json:"versionCode" validate:"gte=0"`
+	BuildType      string `json:"buildType"`
 	CreatedAt      int64  `json:"createdAt" validate:"required"`
 	Crashed        bool   `json:"crashed"`
 }
@@ -18,6 +26,9 @@ type SessionDTO struct {
 type SessionEntity struct {
 	Id             string
 	InstallationId string
+	AppVersion     string
+	VersionCode    int64
+	BuildType      string
 	CreatedAt      int64
 	Crashed        bool
 	AppId          int
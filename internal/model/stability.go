@@ -0,0 +1,47 @@
+package model
+
+const (
+	StabilityIntervalHour  = "hour"
+	StabilityIntervalDay   = "day"
+	StabilityIntervalWeek  = "week"
+	StabilityIntervalMonth = "month"
+)
+
+// StabilityQuery selects how sessions are aggregated into stability metrics.
+// Without an interval all sessions in the time range form one bucket, and
+// without a group by they are not broken down by installation data.
+type StabilityQuery struct {
+	From     int64
+	To       int64
+	Interval string
+	// Key of the installation data to break the metrics down by, fx. 'brand'
+	GroupBy string
+	// Break the metrics down by app version instead of installation data
+	ByRelease bool
+	Release   string
+}
+
+// StabilityEntity counts the sessions and installations of a bucket, and how
+// many of them crashed. Bucket is the start of the interval and Group the
+// value of the installation data attribute, if the query used them.
+type StabilityEntity struct {
+	Bucket               int64
+	Group                string
+	Sessions             int
+	CrashedSessions      int
+	Installations        int
+	CrashedInstallations int
+}
+
+type StabilityDTO struct {
+	Start                     int64   `json:"start,omitempty"`
+	Value                     *string `json:"value,omitempty"`
+	Sessions                  int     `json:"sessions"`
+	CrashedSessions           int     `json:"crashedSessions"`
+	CrashFreeSessionRate      float64 `json:"crashFreeSessionRate"`
+	Installations             int     `json:"installations"`
+	CrashedInstallations      int     `json:"crashedInstallations"`
+	CrashFreeInstallationRate float64 `json:"crashFreeInstallationRate"`
+	// Metrics of the group over time, in a breakdown
+	Series []StabilityDTO `json:"series,omitempty"`
+}
@@ -0,0 +1,105 @@
+package model
+
+const (
+	// The process of the app was started
+	StartTypeCold = "cold"
+	// The process was running, but the activity had to be created
+	StartTypeWarm = "warm"
+	// The activity was brought back to the foreground
+	StartTypeHot = "hot"
+)
+
+// Startup measurements can be grouped by the app version of their session,
+// or by an attribute of the installation data
+const StartupGroupByAppVersion = "appVersion"
+
+// NewAppStartDTO is a single start of the app. Duration is the milliseconds
+// from the start until the first frame was drawn. A cold start is broken
+// down into the phase from process start until Application.onCreate, given
+// by ProcessDuration, and from Application.onCreate until the first frame.
+type NewAppStartDTO struct {
+	Id              string `json:"id" validate:"required,uuid"`
+	SessionId       string `json:"sessionId" validate:"required,uuid"`
+	InstallationId  string `json:"installationId" validate:"required,uuid"`
+	StartType       string `json:"startType" validate:"required,oneof=cold warm hot"`
+	Duration        int64  `json:"duration" validate:"gt=0"`
+	ProcessDuration int64  `json:"processDuration" validate:"required_if=StartType cold,excluded_unless=StartType cold,gte=0,ltefield=Duration"`
+	CreatedAt       int64  `json:"createdAt" validate:"required"`
+}
+
+type NewAppStartData struct {
+	Id              string
+	SessionId       string
+	InstallationId  string
+	AppId           int
+	StartType       string
+	Duration        int64
+	ProcessDuration int64
+	CreatedAt       int64
+}
+
+type GetAppStartDTO struct {
+	Id              string `json:"id"`
+	SessionId       string `json:"sessionId"`
+	InstallationId  string `json:"installationId"`
+	AppId           int    `json:"appId"`
+	StartType       string `json:"startType"`
+	Duration        int64  `json:"duration"`
+	ProcessDuration *int64 `json:"processDuration,omitempty"`
+	// Milliseconds from Application.onCreate until the first frame
+	ApplicationDuration *int64 `json:"applicationDuration,omitempty"`
+	CreatedAt           int64  `json:"createdAt"`
+}
+
+type AppStartEntity struct {
+	Id              string
+	SessionId       string
+	InstallationId  string
+	AppId           int
+	StartType       string
+	Duration        int64
+	ProcessDuration int64
+	CreatedAt       int64
+}
+
+// StartupQuery selects how the starts of one type are aggregated. Without an
+// interval all starts in the time range form one bucket, and without a group
+// by they are not broken down.
+type StartupQuery struct {
+	From      int64
+	To        int64
+	Release   string
+	StartType string
+	Interval  string
+	GroupBy   string
+}
+
+// StartupPercentilesEntity holds percentiles in milliseconds of the total
+// duration of starts, and of their phases. The phases are 0 for warm and
+// hot starts.
+type StartupPercentilesEntity struct {
+	Bucket         int64
+	Group          string
+	Starts         int
+	Sessions       int
+	DurationP50    float64
+	DurationP90    float64
+	DurationP99    float64
+	ProcessP50     float64
+	ProcessP90     float64
+	ProcessP99     float64
+	ApplicationP50 float64
+	ApplicationP90 float64
+	ApplicationP99 float64
+}
+
+type StartupSummaryDTO struct {
+	Start    int64          `json:"start,omitempty"`
+	Value    *string        `json:"value,omitempty"`
+	Starts   int            `json:"starts"`
+	Sessions int            `json:"sessions"`
+	Duration PercentilesDTO `json:"duration"`
+	// Only set for cold starts
+	ProcessDuration     *PercentilesDTO `json:"processDuration,omitempty"`
+	ApplicationDuration *PercentilesDTO `json:"applicationDuration,omitempty"`
+}
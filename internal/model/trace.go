@@ -12,6 +12,7 @@ type NewTraceData struct {
 	StartedAt    int64
 	EndedAt      int64
 	HasEnded     bool
+	Attributes   map[string]any
 }
 
 type TraceEntity struct {
@@ -26,6 +27,7 @@ type TraceEntity struct {
 	StartedAt    int64
 	EndedAt      int64
 	HasEnded     bool
+	Attributes   map[string]any
 }
 
 type TraceDTO struct {
@@ -39,4 +41,32 @@ type TraceDTO struct {
 	StartedAt    int64  `json:"startTime" validate:"required"`
 	EndedAt      int64  `json:"endTime" validate:"required_if=HasEnded true"`
 	HasEnded     bool   `json:"hasEnded"`
+	// Typed attributes of the span, like those of events
+	Attributes map[string]any `json:"attributes,omitempty" validate:"max=64,dive,keys,attributekey,endkeys,attributevalue"`
+}
+
+// TraceNodeDTO is a trace with its children, as returned by the trace tree.
+// Durations are in the same unit as the timestamps of the trace.
+// Traces which have not ended are measured up to the end of their group.
+type TraceNodeDTO struct {
+	TraceDTO
+	Duration       int64          `json:"duration"`
+	SelfTime       int64          `json:"selfTime"`
+	OnCriticalPath bool           `json:"onCriticalPath"`
+	Orphan         bool           `json:"orphan"`
+	Children       []TraceNodeDTO `json:"children"`
+}
+
+// TraceGroupDTO is the span hierarchy of all traces sharing a group id.
+// Roots contains the top level traces and all orphans, whose parent is
+// not part of the group.
+type TraceGroupDTO struct {
+	GroupId      string         `json:"groupId"`
+	StartedAt    int64          `json:"startTime"`
+	EndedAt      int64          `json:"endTime"`
+	Duration     int64          `json:"duration"`
+	CriticalPath []string       `json:"criticalPath"`
+	Orphans      []string       `json:"orphans"`
+	Unended      []string       `json:"unended"`
+	Roots        []TraceNodeDTO `json:"roots"`
 }
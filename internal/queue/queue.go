@@ -0,0 +1,343 @@
+package queue
+
+import (
+	"ObservabilityServer/internal/model"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	pendingDir = "pending"
+	deadDir    = "dead"
+	maxBackoff = time.Minute
+)
+
+var (
+	ErrQueueFull   = errors.New("queue is full")
+	ErrQueueClosed = errors.New("queue is closed")
+)
+
+// Job is a unit of work, which is kept on disk until it has been handled
+// successfully or moved to the dead-letter store
+type Job struct {
+	Id        string          `json:"id"`
+	Kind      string          `json:"kind"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"lastError,omitempty"`
+	CreatedAt int64           `json:"createdAt"`
+
+	// Final is set when the current attempt is the last one before the job
+	// is moved to the dead-letter store
+	Final bool `json:"-"`
+}
+
+// Handler processes a single job. A returned error causes the job to be
+// retried with backoff until the max number of attempts is reached
+type Handler func(ctx context.Context, job Job) error
+
+// Queue is a bounded, disk backed job queue processed by a pool of workers.
+// Every accepted job is written to the pending directory before Enqueue
+// returns, and is only removed once the handler has succeeded, so jobs
+// that are in flight during a crash are replayed on the next start.
+type Queue struct {
+	dir         string
+	jobs        chan Job
+	handler     Handler
+	workers     int
+	maxAttempts int
+	backoff     time.Duration
+
+	seq    atomic.Uint64
+	mu     sync.RWMutex
+	closed bool
+
+	replayWg sync.WaitGroup
+	workerWg sync.WaitGroup
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func New(config model.QueueConfig, handler Handler) (*Queue, error) {
+	for _, sub := range []string{pendingDir, deadDir} {
+		if err := os.MkdirAll(filepath.Join(config.Dir, sub), 0o755); err != nil {
+			return nil, fmt.Errorf("Could not create queue directory: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &Queue{
+		dir:         config.Dir,
+		jobs:        make(chan Job, max(config.Capacity, 1)),
+		handler:     handler,
+		workers:     max(config.Workers, 1),
+		maxAttempts: max(config.MaxAttempts, 1),
+		backoff:     time.Duration(config.BackoffMillis) * time.Millisecond,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+
+	pending, err := q.readDir(pendingDir)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if len(pending) > 0 {
+		log.Printf("Replaying %d pending jobs from %s\n", len(pending), q.dir)
+	}
+
+	for i := 0; i < q.workers; i++ {
+		q.workerWg.Add(1)
+		go q.work()
+	}
+
+	q.replayWg.Add(1)
+	go q.replay(pending)
+
+	return q, nil
+}
+
+// Enqueue persists a new job and hands it to the workers.
+// ErrQueueFull is returned if the queue is at capacity, in which case the
+// job has not been stored and the caller should ask the client to retry.
+func (q *Queue) Enqueue(kind string, payload any) (string, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return "", ErrQueueClosed
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	job := Job{
+		Id:        fmt.Sprintf("%020d-%06d", now.UnixNano(), q.seq.Add(1)%1_000_000),
+		Kind:      kind,
+		Payload:   data,
+		CreatedAt: now.UnixMilli(),
+	}
+
+	if len(q.jobs) == cap(q.jobs) {
+		return "", ErrQueueFull
+	}
+
+	if err := q.write(pendingDir, job); err != nil {
+		return "", err
+	}
+
+	select {
+	case q.jobs <- job:
+		return job.Id, nil
+	default:
+		q.remove(pendingDir, job.Id)
+		return "", ErrQueueFull
+	}
+}
+
+// Len returns the number of jobs waiting to be picked up by a worker
+func (q *Queue) Len() int {
+	return len(q.jobs)
+}
+
+// Pending returns the job with the given id, if it is still waiting to be
+// processed successfully by this queue
+func (q *Queue) Pending(id string) (Job, bool) {
+	if strings.ContainsAny(id, `/\`) {
+		return Job{}, false
+	}
+	data, err := os.ReadFile(filepath.Join(q.dir, pendingDir, id+".json"))
+	if err != nil {
+		return Job{}, false
+	}
+
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return Job{}, false
+	}
+	return job, true
+}
+
+// DeadLetters returns every job, which failed on all of its attempts
+func (q *Queue) DeadLetters() ([]Job, error) {
+	return q.readDir(deadDir)
+}
+
+// Shutdown stops accepting new jobs and waits for the workers to drain the
+// queue. If ctx expires first, the workers are cancelled and any unfinished
+// jobs are left on disk to be replayed on the next start.
+func (q *Queue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil
+	}
+	q.closed = true
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.replayWg.Wait()
+		close(q.jobs)
+		q.workerWg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		return ctx.Err()
+	}
+}
+
+func (q *Queue) replay(jobs []Job) {
+	defer q.replayWg.Done()
+
+	for _, job := range jobs {
+		select {
+		case q.jobs <- job:
+		case <-q.ctx.Done():
+			return
+		}
+	}
+}
+
+func (q *Queue) work() {
+	defer q.workerWg.Done()
+
+	for job := range q.jobs {
+		if q.ctx.Err() != nil {
+			return
+		}
+		q.process(job)
+	}
+}
+
+func (q *Queue) process(job Job) {
+	for {
+		job.Attempts++
+		job.Final = job.Attempts >= q.maxAttempts
+		err := q.handler(q.ctx, job)
+		if err == nil {
+			q.remove(pendingDir, job.Id)
+			return
+		}
+
+		job.LastError = err.Error()
+		if job.Final {
+			log.Printf("Job %s (%s) failed after %d attempts, moving to dead-letter store: %v\n", job.Id, job.Kind, job.Attempts, err)
+			if err := q.write(deadDir, job); err != nil {
+				log.Printf("Error writing job %s to dead-letter store: %v\n", job.Id, err)
+				return
+			}
+			q.remove(pendingDir, job.Id)
+			return
+		}
+
+		log.Printf("Job %s (%s) failed on attempt %d: %v\n", job.Id, job.Kind, job.Attempts, err)
+		if err := q.write(pendingDir, job); err != nil {
+			log.Printf("Error updating pending job %s: %v\n", job.Id, err)
+		}
+
+		select {
+		case <-time.After(q.backoffFor(job.Attempts)):
+		case <-q.ctx.Done():
+			return
+		}
+	}
+}
+
+func (q *Queue) backoffFor(attempt int) time.Duration {
+	d := q.backoff
+	for i := 1; i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxBackoff)
+}
+
+// write stores the job atomically by writing to a temp file, syncing it
+// and renaming it into place
+func (q *Queue) write(sub string, job Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(q.dir, sub, job.Id+".json")
+	tmp, err := os.CreateTemp(filepath.Join(q.dir, sub), job.Id+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (q *Queue) remove(sub, id string) {
+	err := os.Remove(filepath.Join(q.dir, sub, id+".json"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Error removing job %s from %s: %v\n", id, sub, err)
+	}
+}
+
+func (q *Queue) readDir(sub string) ([]Job, error) {
+	entries, err := os.ReadDir(filepath.Join(q.dir, sub))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	jobs := make([]Job, 0, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(q.dir, sub, name))
+		if err != nil {
+			return nil, err
+		}
+
+		var job Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			log.Printf("Skipping malformed job file %s: %v\n", name, err)
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
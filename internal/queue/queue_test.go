@@ -0,0 +1,182 @@
+package queue
+
+import (
+	"ObservabilityServer/internal/model"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testConfig(dir string) model.QueueConfig {
+	return model.QueueConfig{
+		Dir:           dir,
+		Capacity:      10,
+		Workers:       2,
+		MaxAttempts:   3,
+		BackoffMillis: 1,
+	}
+}
+
+func TestEnqueueProcessesJob(t *testing.T) {
+	var mu sync.Mutex
+	handled := make([]string, 0)
+
+	q, err := New(testConfig(t.TempDir()), func(ctx context.Context, job Job) error {
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, job.Kind)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v\n", err)
+	}
+
+	if _, err := q.Enqueue("test", map[string]int{"a": 1}); err != nil {
+		t.Fatalf("Enqueue() failed: %v\n", err)
+	}
+
+	if err := q.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() failed: %v\n", err)
+	}
+
+	if len(handled) != 1 || handled[0] != "test" {
+		t.Fatalf("Expected 1 handled job of kind 'test', but got %v\n", handled)
+	}
+
+	pending, err := q.readDir(pendingDir)
+	if err != nil {
+		t.Fatalf("readDir() failed: %v\n", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("Expected no pending jobs after drain, but got %d\n", len(pending))
+	}
+}
+
+func TestFailingJobIsDeadLettered(t *testing.T) {
+	var attempts atomic.Int32
+
+	q, err := New(testConfig(t.TempDir()), func(ctx context.Context, job Job) error {
+		attempts.Add(1)
+		return errors.New("always failing")
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v\n", err)
+	}
+
+	if _, err := q.Enqueue("test", "payload"); err != nil {
+		t.Fatalf("Enqueue() failed: %v\n", err)
+	}
+
+	if err := q.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() failed: %v\n", err)
+	}
+
+	if attempts.Load() != 3 {
+		t.Errorf("Expected 3 attempts, but got %d\n", attempts.Load())
+	}
+
+	dead, err := q.DeadLetters()
+	if err != nil {
+		t.Fatalf("DeadLetters() failed: %v\n", err)
+	}
+	if len(dead) != 1 || dead[0].LastError != "always failing" || dead[0].Attempts != 3 {
+		t.Fatalf("Expected 1 dead-lettered job with 3 attempts, but got %v\n", dead)
+	}
+}
+
+func TestPendingJobsAreReplayed(t *testing.T) {
+	dir := t.TempDir()
+	block := make(chan struct{})
+
+	q, err := New(testConfig(dir), func(ctx context.Context, job Job) error {
+		select {
+		case <-block:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v\n", err)
+	}
+
+	if _, err := q.Enqueue("test", "payload"); err != nil {
+		t.Fatalf("Enqueue() failed: %v\n", err)
+	}
+
+	// Simulate a shutdown where the job could not be finished in time
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := q.Shutdown(ctx); err == nil {
+		t.Fatalf("Expected Shutdown() to time out")
+	}
+
+	replayed := make(chan string, 1)
+	q, err = New(testConfig(dir), func(ctx context.Context, job Job) error {
+		replayed <- job.Kind
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v\n", err)
+	}
+	defer q.Shutdown(context.Background())
+
+	select {
+	case kind := <-replayed:
+		if kind != "test" {
+			t.Errorf("Expected replayed job of kind 'test', but got '%s'\n", kind)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Pending job was not replayed")
+	}
+}
+
+func TestEnqueueAfterShutdown(t *testing.T) {
+	q, err := New(testConfig(t.TempDir()), func(ctx context.Context, job Job) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v\n", err)
+	}
+
+	q.Shutdown(context.Background())
+
+	if _, err := q.Enqueue("test", "payload"); !errors.Is(err, ErrQueueClosed) {
+		t.Fatalf("Expected ErrQueueClosed, but got %v\n", err)
+	}
+}
+
+func TestPending(t *testing.T) {
+	release := make(chan struct{})
+	q, err := New(testConfig(t.TempDir()), func(ctx context.Context, job Job) error {
+		<-release
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v\n", err)
+	}
+
+	id, err := q.Enqueue("test", map[string]int{"appId": 7})
+	if err != nil {
+		t.Fatalf("Enqueue() failed: %v\n", err)
+	}
+
+	job, ok := q.Pending(id)
+	if !ok || job.Kind != "test" || string(job.Payload) != `{"appId":7}` {
+		t.Errorf("Expected pending job with its payload, but got %+v, %v\n", job, ok)
+	}
+	if _, ok := q.Pending("../" + id); ok {
+		t.Errorf("Expected ids with path separators to be unknown\n")
+	}
+
+	close(release)
+	if err := q.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() failed: %v\n", err)
+	}
+	if _, ok := q.Pending(id); ok {
+		t.Errorf("Expected processed job to no longer be pending\n")
+	}
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Number of takes between removals of full buckets
+const sweepInterval = 1024
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	fullAt    time.Time
+}
+
+// Memory keeps the buckets in memory, so each replica limits requests on
+// its own
+type Memory struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	takes   int
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		buckets: make(map[string]*bucket),
+	}
+}
+
+func (m *Memory) Take(key string, limit Limit, now time.Time) (bool, time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.takes++
+	if m.takes%sweepInterval == 0 {
+		m.sweep(now)
+	}
+
+	tokens := float64(limit.Burst)
+	b, ok := m.buckets[key]
+	if ok {
+		tokens = refill(b.tokens, now.Sub(b.updatedAt), limit)
+	} else {
+		b = &bucket{}
+		m.buckets[key] = b
+	}
+
+	b.updatedAt = now
+	if tokens < 1 {
+		b.tokens = tokens
+		b.fullAt = fullAt(tokens, now, limit)
+		return false, retryAfter(tokens, limit), nil
+	}
+
+	b.tokens = tokens - 1
+	b.fullAt = fullAt(b.tokens, now, limit)
+	return true, 0, nil
+}
+
+// sweep removes the buckets that have refilled completely
+func (m *Memory) sweep(now time.Time) {
+	for key, b := range m.buckets {
+		if !now.Before(b.fullAt) {
+			delete(m.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Time between removals of full buckets from the store
+const cleanupInterval = 10 * time.Minute
+
+// Store persists buckets, so replicas sharing the store share their limits
+type Store interface {
+	// TakeRateLimitToken takes a token from the bucket of key if it has one
+	// after refilling it until now, and returns the tokens left either way.
+	// Times are milliseconds since epoch.
+	TakeRateLimitToken(key string, rate float64, burst int, now int64) (bool, float64, error)
+	// DeleteFullRateLimits removes the buckets which are full at now
+	DeleteFullRateLimits(now int64) error
+}
+
+// Postgres keeps the buckets in a Store, which is backed by Postgres
+type Postgres struct {
+	store Store
+
+	mu          sync.Mutex
+	lastCleanup time.Time
+}
+
+func NewPostgres(store Store) *Postgres {
+	return &Postgres{store: store}
+}
+
+func (p *Postgres) Take(key string, limit Limit, now time.Time) (bool, time.Duration, error) {
+	p.cleanup(now)
+
+	ok, tokens, err := p.store.TakeRateLimitToken(key, limit.Rate, limit.Burst, now.UnixMilli())
+	if err != nil || ok {
+		return ok, 0, err
+	}
+	return false, retryAfter(tokens, limit), nil
+}
+
+func (p *Postgres) cleanup(now time.Time) {
+	p.mu.Lock()
+	if now.Sub(p.lastCleanup) < cleanupInterval {
+		p.mu.Unlock()
+		return
+	}
+	p.lastCleanup = now
+	p.mu.Unlock()
+
+	// Rows of full buckets only take up space, so a failed cleanup is
+	// retried on the next interval
+	go func() {
+		if err := p.store.DeleteFullRateLimits(now.UnixMilli()); err != nil {
+			log.Printf("Error removing full rate limit buckets: %v\n", err)
+		}
+	}()
+}
@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// Limit of a token bucket, which holds at most Burst tokens and refills at
+// Rate tokens per second. Each request takes one token.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// LimitPerMinute returns the limit of perMinute requests per minute, with
+// bursts of up to burst requests
+func LimitPerMinute(perMinute, burst int) Limit {
+	return Limit{Rate: float64(perMinute) / 60, Burst: burst}
+}
+
+// Enabled reports whether the limit lets any request through, a zero limit
+// means requests are not limited
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+// Limiter keeps a token bucket per key. Buckets that do not exist yet are
+// full.
+type Limiter interface {
+	// Take takes a token from the bucket of key. If the bucket has no token
+	// left, it returns false and the time until it has one again.
+	Take(key string, limit Limit, now time.Time) (bool, time.Duration, error)
+}
+
+// refill returns the tokens of a bucket which had tokens, after elapsed
+func refill(tokens float64, elapsed time.Duration, limit Limit) float64 {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return math.Min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.Rate)
+}
+
+// retryAfter returns the time until a bucket with tokens has a whole token
+func retryAfter(tokens float64, limit Limit) time.Duration {
+	if tokens >= 1 {
+		return 0
+	}
+	return time.Duration(math.Ceil((1 - tokens) / limit.Rate * float64(time.Second)))
+}
+
+// fullAt returns when a bucket with tokens at now has refilled completely.
+// Buckets which are full can be forgotten, since a missing bucket is full.
+func fullAt(tokens float64, now time.Time, limit Limit) time.Time {
+	missing := float64(limit.Burst) - tokens
+	if missing <= 0 {
+		return now
+	}
+	return now.Add(time.Duration(math.Ceil(missing / limit.Rate * float64(time.Second))))
+}
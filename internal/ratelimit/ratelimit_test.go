@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryTake(t *testing.T) {
+	limit := Limit{Rate: 2, Burst: 3}
+	m := NewMemory()
+	start := time.UnixMilli(1700000000000)
+
+	for i := 0; i < limit.Burst; i++ {
+		ok, _, err := m.Take("a", limit, start)
+		if err != nil || !ok {
+			t.Fatalf("Take %d = %v, %v, expected a token of the full bucket", i, ok, err)
+		}
+	}
+
+	ok, wait, _ := m.Take("a", limit, start)
+	if ok {
+		t.Fatalf("Take of an empty bucket = true")
+	}
+	if wait != 500*time.Millisecond {
+		t.Errorf("Retry after = %v, expected 500ms", wait)
+	}
+
+	if ok, _, _ := m.Take("b", limit, start); !ok {
+		t.Errorf("Take of another key = false, expected its own bucket")
+	}
+
+	ok, _, _ = m.Take("a", limit, start.Add(wait))
+	if !ok {
+		t.Errorf("Take after retry after = false")
+	}
+	if ok, _, _ := m.Take("a", limit, start.Add(wait)); ok {
+		t.Errorf("Take after the refilled token was taken = true")
+	}
+}
+
+func TestMemorySweep(t *testing.T) {
+	limit := Limit{Rate: 1, Burst: 1}
+	m := NewMemory()
+	start := time.UnixMilli(1700000000000)
+
+	m.Take("a", limit, start)
+	m.Take("b", limit, start.Add(2*time.Second))
+	m.sweep(start.Add(2 * time.Second))
+
+	if _, ok := m.buckets["a"]; ok {
+		t.Errorf("Full bucket was not removed")
+	}
+	if _, ok := m.buckets["b"]; !ok {
+		t.Errorf("Bucket which is not full was removed")
+	}
+}
+
+func TestRefill(t *testing.T) {
+	limit := Limit{Rate: 0.5, Burst: 10}
+
+	tests := []struct {
+		name     string
+		tokens   float64
+		elapsed  time.Duration
+		expected float64
+	}{
+		{name: "refills at rate", tokens: 1, elapsed: 4 * time.Second, expected: 3},
+		{name: "stops at burst", tokens: 9, elapsed: time.Hour, expected: 10},
+		{name: "ignores clock going back", tokens: 2, elapsed: -time.Second, expected: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := refill(tt.tokens, tt.elapsed, limit); got != tt.expected {
+				t.Errorf("refill = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	limit := LimitPerMinute(60, 5)
+
+	if got := retryAfter(1, limit); got != 0 {
+		t.Errorf("retryAfter with a token = %v, expected 0", got)
+	}
+	if got := retryAfter(0.25, limit); got != 750*time.Millisecond {
+		t.Errorf("retryAfter = %v, expected 750ms", got)
+	}
+	if got := fullAt(4, time.UnixMilli(0), limit); got != time.UnixMilli(1000) {
+		t.Errorf("fullAt = %v, expected after 1s", got)
+	}
+}
+
+func TestLimitEnabled(t *testing.T) {
+	if LimitPerMinute(0, 10).Enabled() || LimitPerMinute(10, 0).Enabled() {
+		t.Errorf("Limit without rate or burst is enabled")
+	}
+	if !LimitPerMinute(10, 10).Enabled() {
+		t.Errorf("Limit with rate and burst is disabled")
+	}
+}
@@ -0,0 +1,431 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"ObservabilityServer/internal/pb"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	MIMEApplicationProtobuf = "application/x-protobuf"
+)
+
+// Binder extends the default echo binder with support for protobuf bodies.
+// Protobuf messages are converted to the same DTOs as JSON bodies, so
+// handlers validate them with the same rules
+type Binder struct {
+	echo.DefaultBinder
+}
+
+func NewBinder() *Binder {
+	return &Binder{}
+}
+
+func (b *Binder) Bind(i interface{}, c echo.Context) error {
+	if !isProtobufRequest(c.Request()) {
+		return b.DefaultBinder.Bind(i, c)
+	}
+
+	if err := b.BindPathParams(c, i); err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+
+	if err := bindProtobuf(body, i); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+
+	return nil
+}
+
+func isProtobufRequest(req *http.Request) bool {
+	base, _, _ := strings.Cut(req.Header.Get(echo.HeaderContentType), ";")
+	return strings.TrimSpace(base) == MIMEApplicationProtobuf
+}
+
+func bindProtobuf(body []byte, i interface{}) error {
+	switch dto := i.(type) {
+	case *model.CollectionDTO:
+		var msg pb.Collection
+		if err := proto.Unmarshal(body, &msg); err != nil {
+			return err
+		}
+		*dto = collectionFromProto(&msg)
+
+	case *model.SessionDTO:
+		var msg pb.Session
+		if err := proto.Unmarshal(body, &msg); err != nil {
+			return err
+		}
+		*dto = sessionFromProto(&msg)
+
+	case *model.EventDTO:
+		var msg pb.Event
+		if err := proto.Unmarshal(body, &msg); err != nil {
+			return err
+		}
+		*dto = eventFromProto(&msg)
+
+	case *model.TraceDTO:
+		var msg pb.Trace
+		if err := proto.Unmarshal(body, &msg); err != nil {
+			return err
+		}
+		*dto = traceFromProto(&msg)
+
+	case *model.CrashDTO:
+		var msg pb.Crash
+		if err := proto.Unmarshal(body, &msg); err != nil {
+			return err
+		}
+		*dto = crashFromProto(&msg)
+
+	case *model.AnrDTO:
+		var msg pb.Anr
+		if err := proto.Unmarshal(body, &msg); err != nil {
+			return err
+		}
+		*dto = anrFromProto(&msg)
+
+	case *[]model.NewMemoryUsageDTO:
+		var msg pb.MemoryUsageList
+		if err := proto.Unmarshal(body, &msg); err != nil {
+			return err
+		}
+		usages := make([]model.NewMemoryUsageDTO, len(msg.MemoryUsages))
+		for i, usage := range msg.MemoryUsages {
+			usages[i] = memoryUsageFromProto(usage)
+		}
+		*dto = usages
+
+	case *[]model.NewCpuUsageDTO:
+		var msg pb.CpuUsageList
+		if err := proto.Unmarshal(body, &msg); err != nil {
+			return err
+		}
+		usages := make([]model.NewCpuUsageDTO, len(msg.CpuUsages))
+		for i, usage := range msg.CpuUsages {
+			usages[i] = cpuUsageFromProto(usage)
+		}
+		*dto = usages
+
+	case *[]model.NewFrameMetricsDTO:
+		var msg pb.FrameMetricsList
+		if err := proto.Unmarshal(body, &msg); err != nil {
+			return err
+		}
+		metrics := make([]model.NewFrameMetricsDTO, len(msg.FrameMetrics))
+		for i, metric := range msg.FrameMetrics {
+			metrics[i] = frameMetricsFromProto(metric)
+		}
+		*dto = metrics
+
+	case *[]model.NewBatteryStateDTO:
+		var msg pb.BatteryStateList
+		if err := proto.Unmarshal(body, &msg); err != nil {
+			return err
+		}
+		states := make([]model.NewBatteryStateDTO, len(msg.BatteryStates))
+		for i, state := range msg.BatteryStates {
+			states[i] = batteryStateFromProto(state)
+		}
+		*dto = states
+
+	case *[]model.NewNetworkUsageDTO:
+		var msg pb.NetworkUsageList
+		if err := proto.Unmarshal(body, &msg); err != nil {
+			return err
+		}
+		usages := make([]model.NewNetworkUsageDTO, len(msg.NetworkUsages))
+		for i, usage := range msg.NetworkUsages {
+			usages[i] = networkUsageFromProto(usage)
+		}
+		*dto = usages
+
+	case *[]model.NewAppStartDTO:
+		var msg pb.AppStartList
+		if err := proto.Unmarshal(body, &msg); err != nil {
+			return err
+		}
+		starts := make([]model.NewAppStartDTO, len(msg.AppStarts))
+		for i, start := range msg.AppStarts {
+			starts[i] = appStartFromProto(start)
+		}
+		*dto = starts
+
+	case *[]model.NetworkRequestDTO:
+		var msg pb.NetworkRequestList
+		if err := proto.Unmarshal(body, &msg); err != nil {
+			return err
+		}
+		requests := make([]model.NetworkRequestDTO, len(msg.NetworkRequests))
+		for i, request := range msg.NetworkRequests {
+			requests[i] = networkRequestFromProto(request)
+		}
+		*dto = requests
+
+	case *model.AndroidInstallationDTO:
+		var msg pb.AndroidInstallation
+		if err := proto.Unmarshal(body, &msg); err != nil {
+			return err
+		}
+		*dto = model.AndroidInstallationDTO{
+			Id:         msg.Id,
+			SdkVersion: int(msg.SdkVersion),
+			Model:      msg.Model,
+			Brand:      msg.Brand,
+			CreatedAt:  msg.CreatedAt,
+		}
+
+	case *model.InstallationDTO:
+		var msg pb.Installation
+		if err := proto.Unmarshal(body, &msg); err != nil {
+			return err
+		}
+		dto.Id = msg.Id
+		dto.Data = msg.Data.AsMap()
+		dto.CreatedAt = msg.CreatedAt
+
+	default:
+		return fmt.Errorf("Protobuf is not supported for this endpoint")
+	}
+
+	return nil
+}
+
+func collectionFromProto(msg *pb.Collection) model.CollectionDTO {
+	collection := model.CollectionDTO{
+		Events:  make([]model.EventDTO, len(msg.Events)),
+		Traces:  make([]model.TraceDTO, len(msg.Traces)),
+		Crashes: make([]model.CrashDTO, len(msg.Crashes)),
+		Anrs:    make([]model.AnrDTO, len(msg.Anrs)),
+
+		NetworkRequests: make([]model.NetworkRequestDTO, len(msg.NetworkRequests)),
+	}
+
+	if msg.Session != nil {
+		session := sessionFromProto(msg.Session)
+		collection.Session = &session
+	}
+	for i, e := range msg.Events {
+		collection.Events[i] = eventFromProto(e)
+	}
+	for i, t := range msg.Traces {
+		collection.Traces[i] = traceFromProto(t)
+	}
+	for i, c := range msg.Crashes {
+		collection.Crashes[i] = crashFromProto(c)
+	}
+	for i, a := range msg.Anrs {
+		collection.Anrs[i] = anrFromProto(a)
+	}
+	for i, r := range msg.NetworkRequests {
+		collection.NetworkRequests[i] = networkRequestFromProto(r)
+	}
+
+	return collection
+}
+
+func sessionFromProto(msg *pb.Session) model.SessionDTO {
+	return model.SessionDTO{
+		Id:             msg.Id,
+		InstallationId: msg.InstallationId,
+		AppVersion:     msg.AppVersion,
+		VersionCode:    msg.VersionCode,
+		BuildType:      msg.BuildType,
+		CreatedAt:      msg.CreatedAt,
+		Crashed:        msg.Crashed,
+	}
+}
+
+func eventFromProto(msg *pb.Event) model.EventDTO {
+	return model.EventDTO{
+		Id:             msg.Id,
+		SessionId:      msg.SessionId,
+		Type:           msg.Type,
+		SerializedData: msg.SerializedData,
+		Attributes:     attributesFromProto(msg.Attributes),
+		CreatedAt:      msg.CreatedAt,
+	}
+}
+
+func traceFromProto(msg *pb.Trace) model.TraceDTO {
+	return model.TraceDTO{
+		TraceId:      msg.TraceId,
+		SessionId:    msg.SessionId,
+		GroupId:      msg.GroupId,
+		ParentId:     msg.ParentId,
+		Name:         msg.Name,
+		Status:       msg.Status,
+		ErrorMessage: msg.ErrorMessage,
+		StartedAt:    msg.StartTime,
+		EndedAt:      msg.EndTime,
+		HasEnded:     msg.HasEnded,
+		Attributes:   attributesFromProto(msg.Attributes),
+	}
+}
+
+// attributesFromProto keeps missing attributes nil, so they are not
+// mistaken for an empty set
+func attributesFromProto(msg *structpb.Struct) map[string]any {
+	if msg == nil {
+		return nil
+	}
+	return msg.AsMap()
+}
+
+func crashFromProto(msg *pb.Crash) model.CrashDTO {
+	return model.CrashDTO{
+		Id:             msg.Id,
+		SessionId:      msg.SessionId,
+		ExceptionClass: msg.ExceptionClass,
+		Message:        msg.Message,
+		Frames:         framesFromProto(msg.Frames),
+		ThreadName:     msg.ThreadName,
+		Screen:         msg.Screen,
+		AppVersion:     msg.AppVersion,
+		CreatedAt:      msg.CreatedAt,
+	}
+}
+
+func anrFromProto(msg *pb.Anr) model.AnrDTO {
+	threads := make([]model.ThreadDTO, len(msg.Threads))
+	for i, thread := range msg.Threads {
+		threads[i] = model.ThreadDTO{
+			Name:   thread.Name,
+			State:  thread.State,
+			Frames: framesFromProto(thread.Frames),
+		}
+	}
+
+	return model.AnrDTO{
+		Id:         msg.Id,
+		SessionId:  msg.SessionId,
+		Duration:   msg.Duration,
+		MainThread: framesFromProto(msg.MainThread),
+		Threads:    threads,
+		Screen:     msg.Screen,
+		AppVersion: msg.AppVersion,
+		CreatedAt:  msg.CreatedAt,
+	}
+}
+
+func framesFromProto(msgs []*pb.StackFrame) []model.StackFrameDTO {
+	frames := make([]model.StackFrameDTO, len(msgs))
+	for i, frame := range msgs {
+		frames[i] = model.StackFrameDTO{
+			ClassName:  frame.ClassName,
+			MethodName: frame.MethodName,
+			FileName:   frame.FileName,
+			LineNumber: int(frame.LineNumber),
+		}
+	}
+	return frames
+}
+
+func memoryUsageFromProto(msg *pb.MemoryUsage) model.NewMemoryUsageDTO {
+	return model.NewMemoryUsageDTO{
+		Id:                 msg.Id,
+		SessionId:          msg.SessionId,
+		InstallationId:     msg.InstallationId,
+		FreeMemory:         msg.FreeMemory,
+		UsedMemory:         msg.UsedMemory,
+		MaxMemory:          msg.MaxMemory,
+		TotalMemory:        msg.TotalMemory,
+		AvailableHeapSpace: msg.AvailableHeapSpace,
+		CreatedAt:          msg.CreatedAt,
+	}
+}
+
+func cpuUsageFromProto(msg *pb.CpuUsage) model.NewCpuUsageDTO {
+	return model.NewCpuUsageDTO{
+		Id:             msg.Id,
+		SessionId:      msg.SessionId,
+		InstallationId: msg.InstallationId,
+		Usage:          msg.Usage,
+		UserTime:       msg.UserTime,
+		SystemTime:     msg.SystemTime,
+		Cores:          int(msg.Cores),
+		CreatedAt:      msg.CreatedAt,
+	}
+}
+
+func frameMetricsFromProto(msg *pb.FrameMetrics) model.NewFrameMetricsDTO {
+	return model.NewFrameMetricsDTO{
+		Id:             msg.Id,
+		SessionId:      msg.SessionId,
+		InstallationId: msg.InstallationId,
+		Screen:         msg.Screen,
+		TotalFrames:    int(msg.TotalFrames),
+		JankyFrames:    int(msg.JankyFrames),
+		FrozenFrames:   int(msg.FrozenFrames),
+		CreatedAt:      msg.CreatedAt,
+	}
+}
+
+func batteryStateFromProto(msg *pb.BatteryState) model.NewBatteryStateDTO {
+	return model.NewBatteryStateDTO{
+		Id:             msg.Id,
+		SessionId:      msg.SessionId,
+		InstallationId: msg.InstallationId,
+		Level:          msg.Level,
+		Charging:       msg.Charging,
+		Temperature:    msg.Temperature,
+		ThermalState:   msg.ThermalState,
+		PowerSave:      msg.PowerSave,
+		CreatedAt:      msg.CreatedAt,
+	}
+}
+
+func networkUsageFromProto(msg *pb.NetworkUsage) model.NewNetworkUsageDTO {
+	return model.NewNetworkUsageDTO{
+		Id:             msg.Id,
+		SessionId:      msg.SessionId,
+		InstallationId: msg.InstallationId,
+		ConnectionType: msg.ConnectionType,
+		Requests:       int(msg.Requests),
+		FailedRequests: int(msg.FailedRequests),
+		BytesSent:      msg.BytesSent,
+		BytesReceived:  msg.BytesReceived,
+		CreatedAt:      msg.CreatedAt,
+	}
+}
+
+func networkRequestFromProto(msg *pb.NetworkRequest) model.NetworkRequestDTO {
+	return model.NetworkRequestDTO{
+		Id:            msg.Id,
+		SessionId:     msg.SessionId,
+		TraceId:       msg.TraceId,
+		UrlTemplate:   msg.UrlTemplate,
+		Method:        msg.Method,
+		StatusCode:    int(msg.StatusCode),
+		Duration:      msg.Duration,
+		RequestSize:   msg.RequestSize,
+		ResponseSize:  msg.ResponseSize,
+		FailureReason: msg.FailureReason,
+		StartedAt:     msg.StartTime,
+	}
+}
+
+func appStartFromProto(msg *pb.AppStart) model.NewAppStartDTO {
+	return model.NewAppStartDTO{
+		Id:              msg.Id,
+		SessionId:       msg.SessionId,
+		InstallationId:  msg.InstallationId,
+		StartType:       msg.StartType,
+		Duration:        msg.Duration,
+		ProcessDuration: msg.ProcessDuration,
+		CreatedAt:       msg.CreatedAt,
+	}
+}
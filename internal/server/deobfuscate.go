@@ -0,0 +1,80 @@
+package server
+
+import (
+	"ObservabilityServer/internal/mapping"
+	"ObservabilityServer/internal/model"
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// getMapping returns the parsed mapping of an app version, or nil if no
+// mapping was uploaded for it
+func (s *Server) getMapping(appId int, version string) (*mapping.Mapping, error) {
+	if version == "" {
+		return nil, nil
+	}
+
+	entity, err := s.db.GetMapping(appId, version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Uploading the mapping again replaces it, so the upload time is part of the key
+	key := fmt.Sprintf("%d/%s/%d", entity.AppId, entity.Version, entity.CreatedAt)
+	return s.mappings.Get(key, func() (*mapping.Mapping, error) {
+		content, err := s.db.GetMappingContent(appId, version)
+		if err != nil {
+			return nil, err
+		}
+		return mapping.Parse(bytes.NewReader(content))
+	})
+}
+
+// versionMappings looks up the mappings of the app versions in a page of
+// query results, which were received before their mapping was uploaded.
+// Every version is only looked up once.
+type versionMappings struct {
+	s        *Server
+	appId    int
+	mappings map[string]*mapping.Mapping
+}
+
+func (s *Server) newVersionMappings(appId int) *versionMappings {
+	return &versionMappings{
+		s:        s,
+		appId:    appId,
+		mappings: make(map[string]*mapping.Mapping),
+	}
+}
+
+// get returns nil if there is no mapping for the version
+func (v *versionMappings) get(version string) *mapping.Mapping {
+	m, ok := v.mappings[version]
+	if !ok {
+		var err error
+		m, err = v.s.getMapping(v.appId, version)
+		if err != nil {
+			log.Printf("Getting mapping of version %s failed: %v\n", version, err)
+		}
+		v.mappings[version] = m
+	}
+	return m
+}
+
+func deobfuscateThreads(m *mapping.Mapping, threads []model.ThreadDTO) []model.ThreadDTO {
+	result := make([]model.ThreadDTO, len(threads))
+	for i, thread := range threads {
+		result[i] = model.ThreadDTO{
+			Name:   thread.Name,
+			State:  thread.State,
+			Frames: m.Frames(thread.Frames),
+		}
+	}
+	return result
+}
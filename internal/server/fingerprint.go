@@ -0,0 +1,82 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// Number of frames included in a fingerprint. Frames further down the stack
+// mostly differ by how the crashing code was reached, not by the bug itself
+const fingerprintFrames = 5
+
+// Frames from these packages are skipped when fingerprinting, since almost
+// every stack passes through them. They are only used if no other frame is left
+var frameworkPackages = []string{
+	"java.",
+	"javax.",
+	"kotlin.",
+	"kotlinx.",
+	"android.",
+	"androidx.",
+	"dalvik.",
+	"com.android.",
+	"com.google.android.",
+	"sun.",
+	"jdk.",
+	"libcore.",
+}
+
+var (
+	// Anonymous classes like 'MainActivity$1' are numbered by declaration order
+	anonymousClassPattern = regexp.MustCompile(`\$\d+`)
+	// Lambdas and synthetic accessors are numbered by the compiler
+	syntheticPattern = regexp.MustCompile(`\$\$?(ExternalSynthetic)?Lambda(\$|_)?\w*|\$lambda[-$]?\d+|lambda\$\w+\$\d+|access\$\d+`)
+)
+
+// crashFingerprint groups crashes caused by the same bug. It is built from
+// the exception class and the top frames of the stack, ignoring line numbers,
+// the message and names generated by the compiler, which differ between
+// builds or occurrences of the same crash. ANRs have no exception and are
+// fingerprinted by the stack of their main thread.
+func crashFingerprint(kind, exceptionClass string, frames []model.StackFrameDTO) string {
+	normalized := make([]string, 0, fingerprintFrames)
+	for _, frame := range frames {
+		if len(normalized) == fingerprintFrames {
+			break
+		}
+		if isFrameworkFrame(frame) {
+			continue
+		}
+		normalized = append(normalized, normalizeFrame(frame))
+	}
+
+	if len(normalized) == 0 {
+		for _, frame := range frames[:min(len(frames), fingerprintFrames)] {
+			normalized = append(normalized, normalizeFrame(frame))
+		}
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(kind + "\n" + exceptionClass + "\n" + strings.Join(normalized, "\n")))
+	return hex.EncodeToString(hash.Sum(nil))[:32]
+}
+
+func isFrameworkFrame(frame model.StackFrameDTO) bool {
+	for _, pkg := range frameworkPackages {
+		if strings.HasPrefix(frame.ClassName, pkg) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeFrame(frame model.StackFrameDTO) string {
+	className := syntheticPattern.ReplaceAllString(frame.ClassName, "")
+	className = anonymousClassPattern.ReplaceAllString(className, "$")
+	methodName := syntheticPattern.ReplaceAllString(frame.MethodName, "")
+
+	return className + "." + methodName
+}
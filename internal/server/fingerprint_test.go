@@ -0,0 +1,102 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"testing"
+)
+
+func TestCrashFingerprint(t *testing.T) {
+	frames := []model.StackFrameDTO{
+		{ClassName: "java.util.Objects", MethodName: "requireNonNull", LineNumber: 203},
+		{ClassName: "com.example.cart.CartViewModel$load$1", MethodName: "invokeSuspend", LineNumber: 42},
+		{ClassName: "com.example.cart.CartActivity$$ExternalSyntheticLambda3", MethodName: "onClick", LineNumber: 0},
+		{ClassName: "android.view.View", MethodName: "performClick", LineNumber: 7448},
+	}
+	fingerprint := crashFingerprint(model.IssueTypeCrash, "java.lang.NullPointerException", frames)
+
+	tests := []struct {
+		name           string
+		exceptionClass string
+		frames         []model.StackFrameDTO
+		same           bool
+	}{
+		{
+			name:           "different line numbers",
+			exceptionClass: "java.lang.NullPointerException",
+			frames: []model.StackFrameDTO{
+				{ClassName: "java.util.Objects", MethodName: "requireNonNull", LineNumber: 210},
+				{ClassName: "com.example.cart.CartViewModel$load$1", MethodName: "invokeSuspend", LineNumber: 45},
+				{ClassName: "com.example.cart.CartActivity$$ExternalSyntheticLambda0", MethodName: "onClick", LineNumber: 0},
+				{ClassName: "android.view.View", MethodName: "performClick", LineNumber: 7500},
+			},
+			same: true,
+		},
+		{
+			name:           "different framework frames",
+			exceptionClass: "java.lang.NullPointerException",
+			frames: []model.StackFrameDTO{
+				{ClassName: "com.example.cart.CartViewModel$load$2", MethodName: "invokeSuspend", LineNumber: 42},
+				{ClassName: "com.example.cart.CartActivity$$ExternalSyntheticLambda3", MethodName: "onClick", LineNumber: 0},
+				{ClassName: "androidx.appcompat.widget.AppCompatButton", MethodName: "performClick", LineNumber: 1},
+			},
+			same: true,
+		},
+		{
+			name:           "different exception",
+			exceptionClass: "java.lang.IllegalStateException",
+			frames:         frames,
+			same:           false,
+		},
+		{
+			name:           "different method",
+			exceptionClass: "java.lang.NullPointerException",
+			frames: []model.StackFrameDTO{
+				{ClassName: "com.example.cart.CartViewModel$save$1", MethodName: "invokeSuspend", LineNumber: 42},
+				{ClassName: "com.example.cart.CartActivity$$ExternalSyntheticLambda3", MethodName: "onClick", LineNumber: 0},
+			},
+			same: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := crashFingerprint(model.IssueTypeCrash, tt.exceptionClass, tt.frames)
+			if (actual == fingerprint) != tt.same {
+				t.Errorf("crashFingerprint() expected same = %v, but was %s and %s", tt.same, fingerprint, actual)
+			}
+		})
+	}
+}
+
+func TestCrashFingerprintFrameworkOnly(t *testing.T) {
+	frames := []model.StackFrameDTO{
+		{ClassName: "android.os.Handler", MethodName: "dispatchMessage", LineNumber: 106},
+	}
+	other := []model.StackFrameDTO{
+		{ClassName: "android.os.Looper", MethodName: "loop", LineNumber: 223},
+	}
+
+	if crashFingerprint(model.IssueTypeCrash, "java.lang.RuntimeException", frames) == crashFingerprint(model.IssueTypeCrash, "java.lang.RuntimeException", other) {
+		t.Errorf("crashFingerprint() expected stacks with only framework frames to be told apart")
+	}
+}
+
+func TestAnrFingerprint(t *testing.T) {
+	mainThread := []model.StackFrameDTO{
+		{ClassName: "java.io.FileInputStream", MethodName: "read", LineNumber: 320},
+		{ClassName: "com.example.cart.CartRepository", MethodName: "load", LineNumber: 18},
+		{ClassName: "android.os.Looper", MethodName: "loop", LineNumber: 223},
+	}
+	other := []model.StackFrameDTO{
+		{ClassName: "java.io.FileInputStream", MethodName: "read", LineNumber: 301},
+		{ClassName: "com.example.cart.CartRepository", MethodName: "load", LineNumber: 21},
+		{ClassName: "android.os.Looper", MethodName: "loop", LineNumber: 230},
+	}
+
+	if crashFingerprint(model.IssueTypeAnr, "", mainThread) != crashFingerprint(model.IssueTypeAnr, "", other) {
+		t.Errorf("crashFingerprint() expected ANRs blocked at the same place to be grouped")
+	}
+	if crashFingerprint(model.IssueTypeAnr, "", mainThread) == crashFingerprint(model.IssueTypeCrash, "", mainThread) {
+		t.Errorf("crashFingerprint() expected ANRs and crashes to be told apart")
+	}
+}
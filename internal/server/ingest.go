@@ -0,0 +1,276 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"ObservabilityServer/internal/queue"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	collectionJobKind = "collection"
+)
+
+type collectionJob struct {
+	AppId      int                 `json:"appId"`
+	Collection model.CollectionDTO `json:"collection"`
+	// Month in which admitIngestion reserved the events and traces of the
+	// collection in the usage of the app, or 0 if it could not
+	UsageMonth int64 `json:"usageMonth,omitempty"`
+	// Indices of the events and traces of Collection in the collection the
+	// SDK sent, which differ once admitIngestion dropped some of them. Nil
+	// for jobs queued before they were recorded.
+	EventIndices []int `json:"eventIndices,omitempty"`
+	TraceIndices []int `json:"traceIndices,omitempty"`
+}
+
+// collectionJobAppId returns the app of a collection job, or -1 if the job
+// is not a collection
+func collectionJobAppId(job queue.Job) int {
+	var data collectionJob
+	if job.Kind != collectionJobKind || json.Unmarshal(job.Payload, &data) != nil {
+		return -1
+	}
+	return data.AppId
+}
+
+func (s *Server) processJob(ctx context.Context, job queue.Job) error {
+	switch job.Kind {
+	case collectionJobKind:
+		var data collectionJob
+		if err := json.Unmarshal(job.Payload, &data); err != nil {
+			return err
+		}
+		return s.processCollection(job, data)
+
+	default:
+		return fmt.Errorf("Unknown job kind '%s'", job.Kind)
+	}
+}
+
+// processCollection writes the collection and records the outcome on the
+// ingestion batch, so the SDK can find out which items to resend
+func (s *Server) processCollection(job queue.Job, data collectionJob) error {
+	failures, events, traces := s.writeCollection(data)
+	if data.UsageMonth != 0 {
+		reserved := model.IngestionUsageEntity{
+			Month:  data.UsageMonth,
+			Events: int64(len(data.Collection.Events)),
+			Traces: int64(len(data.Collection.Traces)),
+		}
+		s.settleIngestion(data.AppId, reserved, events, traces, job.Attempts)
+	}
+
+	status := model.IngestionStatusCompleted
+	if len(failures) > 0 && job.Final {
+		status = model.IngestionStatusFailed
+	} else if len(failures) > 0 {
+		status = model.IngestionStatusRetrying
+	}
+
+	err := s.db.UpdateIngestionBatch(model.IngestionBatchResultData{
+		Id:        job.Id,
+		AppId:     data.AppId,
+		Status:    status,
+		Attempts:  job.Attempts,
+		Failures:  failures,
+		CreatedAt: job.CreatedAt,
+		UpdatedAt: time.Now().UnixMilli(),
+	})
+	if err != nil {
+		log.Printf("Error updating ingestion batch %s: %v\n", job.Id, err)
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%d items of collection could not be created, first: %s: %s", len(failures), failures[0].Path, failures[0].Message)
+	}
+
+	return nil
+}
+
+// writeCollection writes the collection in a single transaction, so either
+// all of it is stored or nothing is. If an item cannot be written, the
+// failure names that item, so the SDK can send the collection again without
+// it. Sending items which are stored already is safe, since existing records
+// are skipped or completed. Also returns how many events and traces were
+// inserted, leaving out those stored before.
+func (s *Server) writeCollection(job collectionJob) ([]model.IngestionFailure, int, int) {
+	collectionData := job.Collection
+	data := model.NewCollectionData{
+		Events:          make([]model.NewEventData, len(collectionData.Events)),
+		Traces:          make([]model.NewTraceData, len(collectionData.Traces)),
+		Crashes:         make([]model.NewCrashData, len(collectionData.Crashes)),
+		Anrs:            make([]model.NewAnrData, len(collectionData.Anrs)),
+		NetworkRequests: make([]model.NewNetworkRequestData, len(collectionData.NetworkRequests)),
+	}
+
+	if collectionData.Session != nil {
+		sessionDTO := collectionData.Session
+		data.Session = &model.NewSessionData{
+			Id:             sessionDTO.Id,
+			InstallationId: sessionDTO.InstallationId,
+			AppId:          job.AppId,
+			AppVersion:     sessionDTO.AppVersion,
+			VersionCode:    sessionDTO.VersionCode,
+			BuildType:      sessionDTO.BuildType,
+			CreatedAt:      sessionDTO.CreatedAt,
+			Crashed:        sessionDTO.Crashed,
+		}
+	}
+
+	for i, e := range collectionData.Events {
+		data.Events[i] = model.NewEventData{
+			Id:             e.Id,
+			SessionId:      e.SessionId,
+			AppId:          job.AppId,
+			SerializedData: e.SerializedData,
+			Attributes:     e.Attributes,
+			Type:           e.Type,
+			CreatedAt:      e.CreatedAt,
+		}
+	}
+
+	for i, t := range collectionData.Traces {
+		data.Traces[i] = model.NewTraceData{
+			TraceId:      t.TraceId,
+			SessionId:    t.SessionId,
+			GroupId:      t.GroupId,
+			ParentId:     t.ParentId,
+			AppId:        job.AppId,
+			Name:         t.Name,
+			Status:       t.Status,
+			ErrorMessage: t.ErrorMessage,
+			StartedAt:    t.StartedAt,
+			EndedAt:      t.EndedAt,
+			HasEnded:     t.HasEnded,
+			Attributes:   t.Attributes,
+		}
+	}
+
+	for i, crash := range collectionData.Crashes {
+		data.Crashes[i] = s.crashData(job.AppId, crash)
+	}
+	for i, anr := range collectionData.Anrs {
+		data.Anrs[i] = s.anrData(job.AppId, anr)
+	}
+	for i, r := range collectionData.NetworkRequests {
+		data.NetworkRequests[i] = networkRequestData(job.AppId, r)
+	}
+
+	events, traces, err := s.db.CreateCollection(data)
+	if err != nil {
+		return []model.IngestionFailure{collectionFailure(job, err)}, 0, 0
+	}
+
+	return make([]model.IngestionFailure, 0), events, traces
+}
+
+// collectionFailure describes why a collection could not be written, naming
+// the item which failed if it is known by its index in the collection the
+// SDK sent
+func collectionFailure(job collectionJob, err error) model.IngestionFailure {
+	var itemErr *model.CollectionItemError
+	if !errors.As(err, &itemErr) {
+		return model.IngestionFailure{
+			Path:    "",
+			Message: fmt.Sprintf("Collection could not be created: %v", err),
+		}
+	}
+
+	index := itemErr.Index
+	switch itemErr.Kind {
+	case model.CollectionEvents:
+		index = sentIndex(job.EventIndices, index)
+	case model.CollectionTraces:
+		index = sentIndex(job.TraceIndices, index)
+	}
+
+	path := itemErr.Kind
+	if index >= 0 {
+		path = fmt.Sprintf("%s[%d]", itemErr.Kind, index)
+	}
+	return model.IngestionFailure{
+		Path:    path,
+		Message: fmt.Sprintf("Collection could not be created, since %s failed: %v", path, itemErr.Err),
+	}
+}
+
+// sentIndex returns the index in the collection the SDK sent of the item at
+// index i of a job
+func sentIndex(indices []int, i int) int {
+	if i >= 0 && i < len(indices) {
+		return indices[i]
+	}
+	return i
+}
+
+func (s *Server) createCrash(appId int, dto model.CrashDTO) error {
+	return s.db.CreateCrash(s.crashData(appId, dto))
+}
+
+// crashData deobfuscates the crash before it is fingerprinted, so crashes
+// of different builds are grouped by their original names. Crashes without
+// a mapping are stored as is and deobfuscated when queried, once it has been
+// uploaded.
+func (s *Server) crashData(appId int, dto model.CrashDTO) model.NewCrashData {
+	exceptionClass, frames := dto.ExceptionClass, dto.Frames
+	m, err := s.getMapping(appId, dto.AppVersion)
+	if err != nil {
+		log.Printf("Deobfuscating crash %s failed: %v\n", dto.Id, err)
+	}
+	if m != nil {
+		exceptionClass = m.Class(exceptionClass)
+		frames = m.Frames(frames)
+	}
+
+	return model.NewCrashData{
+		Id:             dto.Id,
+		SessionId:      dto.SessionId,
+		AppId:          appId,
+		Fingerprint:    crashFingerprint(model.IssueTypeCrash, exceptionClass, frames),
+		ExceptionClass: exceptionClass,
+		Message:        dto.Message,
+		Frames:         frames,
+		ThreadName:     dto.ThreadName,
+		Screen:         dto.Screen,
+		AppVersion:     dto.AppVersion,
+		Deobfuscated:   m != nil,
+		CreatedAt:      dto.CreatedAt,
+	}
+}
+
+func (s *Server) createAnr(appId int, dto model.AnrDTO) error {
+	return s.db.CreateAnr(s.anrData(appId, dto))
+}
+
+// anrData groups the ANR by where its main thread was blocked. Like
+// crashes, it is deobfuscated before it is fingerprinted.
+func (s *Server) anrData(appId int, dto model.AnrDTO) model.NewAnrData {
+	mainThread, threads := dto.MainThread, dto.Threads
+	m, err := s.getMapping(appId, dto.AppVersion)
+	if err != nil {
+		log.Printf("Deobfuscating ANR %s failed: %v\n", dto.Id, err)
+	}
+	if m != nil {
+		mainThread = m.Frames(mainThread)
+		threads = deobfuscateThreads(m, threads)
+	}
+
+	return model.NewAnrData{
+		Id:           dto.Id,
+		SessionId:    dto.SessionId,
+		AppId:        appId,
+		Fingerprint:  crashFingerprint(model.IssueTypeAnr, "", mainThread),
+		Duration:     dto.Duration,
+		MainThread:   mainThread,
+		Threads:      threads,
+		Screen:       dto.Screen,
+		AppVersion:   dto.AppVersion,
+		Deobfuscated: m != nil,
+		CreatedAt:    dto.CreatedAt,
+	}
+}
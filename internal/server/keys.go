@@ -0,0 +1,52 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"time"
+)
+
+// How long a rotated key keeps working, unless the rotation sets another
+// grace period
+const defaultKeyGracePeriod = 24 * time.Hour
+
+// apiKeyStatus returns whether the key is active, expired or revoked at the
+// time now, in milliseconds since epoch
+func apiKeyStatus(ent model.ApiKeyEntity, now int64) string {
+	switch {
+	case ent.RevokedAt != 0:
+		return model.ApiKeyRevoked
+	case ent.ExpiresAt != 0 && ent.ExpiresAt <= now:
+		return model.ApiKeyExpired
+	default:
+		return model.ApiKeyActive
+	}
+}
+
+// apiKeyUsageStale returns whether the time the key was last used should be
+// updated for a use at the time now, in milliseconds since epoch
+func apiKeyUsageStale(ent model.ApiKeyEntity, now int64) bool {
+	return ent.LastUsedAt == 0 || now-ent.LastUsedAt >= model.ApiKeyUsageResolution
+}
+
+func apiKeyDTO(ent model.ApiKeyEntity, now int64) model.GetApiKeyDTO {
+	return model.GetApiKeyDTO{
+		Id:         ent.Id,
+		Name:       ent.Name,
+		Scopes:     ent.Scopes,
+		Status:     apiKeyStatus(ent, now),
+		CreatedAt:  ent.CreatedAt,
+		CreatedBy:  ent.CreatedBy,
+		LastUsedAt: ent.LastUsedAt,
+		ExpiresAt:  ent.ExpiresAt,
+		RevokedAt:  ent.RevokedAt,
+	}
+}
+
+// keyGraceEnd returns when a key rotated at the time now stops working, in
+// milliseconds since epoch. gracePeriod is in seconds.
+func keyGraceEnd(now int64, gracePeriod *int64) int64 {
+	if gracePeriod == nil {
+		return now + defaultKeyGracePeriod.Milliseconds()
+	}
+	return now + *gracePeriod*int64(time.Second/time.Millisecond)
+}
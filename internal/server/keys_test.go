@@ -0,0 +1,66 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"testing"
+)
+
+func TestApiKeyStatus(t *testing.T) {
+	now := int64(1700000000000)
+	tests := []struct {
+		name     string
+		key      model.ApiKeyEntity
+		expected string
+	}{
+		{name: "never expires", key: model.ApiKeyEntity{}, expected: model.ApiKeyActive},
+		{name: "expires later", key: model.ApiKeyEntity{ExpiresAt: now + 1}, expected: model.ApiKeyActive},
+		{name: "expires now", key: model.ApiKeyEntity{ExpiresAt: now}, expected: model.ApiKeyExpired},
+		{name: "revoked", key: model.ApiKeyEntity{RevokedAt: now - 1}, expected: model.ApiKeyRevoked},
+		{name: "revoked after expiry", key: model.ApiKeyEntity{ExpiresAt: now - 2, RevokedAt: now - 1}, expected: model.ApiKeyRevoked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := apiKeyStatus(tt.key, now); actual != tt.expected {
+				t.Errorf("apiKeyStatus() = %s, expected %s", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestKeyGraceEnd(t *testing.T) {
+	now := int64(1700000000000)
+	zero := int64(0)
+	hour := int64(3600)
+
+	if actual := keyGraceEnd(now, nil); actual != now+24*3600*1000 {
+		t.Errorf("keyGraceEnd() without grace period = %d, expected a day later", actual)
+	}
+	if actual := keyGraceEnd(now, &zero); actual != now {
+		t.Errorf("keyGraceEnd() with no grace period = %d, expected %d", actual, now)
+	}
+	if actual := keyGraceEnd(now, &hour); actual != now+3600*1000 {
+		t.Errorf("keyGraceEnd() with an hour = %d, expected %d", actual, now+3600*1000)
+	}
+}
+
+func TestApiKeyUsageStale(t *testing.T) {
+	now := int64(1700000000000)
+	tests := []struct {
+		name       string
+		lastUsedAt int64
+		expected   bool
+	}{
+		{name: "never used", lastUsedAt: 0, expected: true},
+		{name: "used recently", lastUsedAt: now - model.ApiKeyUsageResolution + 1, expected: false},
+		{name: "used a minute ago", lastUsedAt: now - model.ApiKeyUsageResolution, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := apiKeyUsageStale(model.ApiKeyEntity{LastUsedAt: tt.lastUsedAt}, now); actual != tt.expected {
+				t.Errorf("apiKeyUsageStale() = %v, expected %v", actual, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package server
+
+import "ObservabilityServer/internal/model"
+
+const (
+	// Min share of the samples of a session which must use more memory than
+	// the sample before, for the session to be suspected of leaking
+	leakMinIncreasingShare = 0.8
+	// Min growth of the used memory of a session, as a share of its max
+	// memory, for the session to be suspected of leaking
+	leakMinGrowthRatio = 0.05
+)
+
+func memorySummaryDTOS(entities []model.MemoryPercentilesEntity, withStart, withValue bool) []model.MemorySummaryDTO {
+	DTOS := make([]model.MemorySummaryDTO, len(entities))
+	for i, ent := range entities {
+		DTOS[i] = memorySummaryDTO(ent, withStart, withValue)
+	}
+	return DTOS
+}
+
+func memorySummaryDTO(ent model.MemoryPercentilesEntity, withStart, withValue bool) model.MemorySummaryDTO {
+	dto := model.MemorySummaryDTO{
+		Samples:  ent.Samples,
+		Sessions: ent.Sessions,
+		UsedMemory: model.PercentilesDTO{
+			P50: ent.UsedP50,
+			P90: ent.UsedP90,
+			P99: ent.UsedP99,
+		},
+		UsedRatio: model.PercentilesDTO{
+			P50: ent.RatioP50,
+			P90: ent.RatioP90,
+			P99: ent.RatioP99,
+		},
+	}
+	if withStart {
+		dto.Start = ent.Bucket
+	}
+	if withValue {
+		value := ent.Group
+		dto.Value = &value
+	}
+	return dto
+}
+
+// memoryLeakDTO applies the leak heuristic to the memory trend of a session.
+// A session is suspected of leaking if its used memory increases almost
+// monotonically across its samples, and grew by a noticeable share of its
+// max memory.
+func memoryLeakDTO(ent model.MemoryTrendEntity) model.MemoryLeakDTO {
+	dto := model.MemoryLeakDTO{
+		SessionId:      ent.SessionId,
+		InstallationId: ent.InstallationId,
+		Samples:        ent.Samples,
+		FirstUsed:      ent.FirstUsed,
+		LastUsed:       ent.LastUsed,
+		MaxMemory:      ent.MaxMemory,
+		Growth:         ent.Slope * 60 * 1000,
+	}
+	if ent.Samples > 1 {
+		// The first sample has nothing to be compared with
+		dto.IncreasingShare = rate(ent.Increases, ent.Samples-1)
+	}
+	if ent.MaxMemory > 0 {
+		dto.LastRatio = float64(ent.LastUsed) / float64(ent.MaxMemory)
+	}
+
+	if ent.Slope > 0 && ent.MaxMemory > ent.LastUsed {
+		timeToOom := int64(float64(ent.MaxMemory-ent.LastUsed) / ent.Slope)
+		dto.TimeToOom = &timeToOom
+	}
+
+	growthRatio := 0.0
+	if ent.MaxMemory > 0 {
+		growthRatio = float64(ent.LastUsed-ent.FirstUsed) / float64(ent.MaxMemory)
+	}
+	dto.LeakSuspected = ent.Slope > 0 &&
+		dto.IncreasingShare >= leakMinIncreasingShare &&
+		growthRatio >= leakMinGrowthRatio
+
+	return dto
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"testing"
+)
+
+func TestMemoryLeak(t *testing.T) {
+	tests := []struct {
+		name      string
+		trend     model.MemoryTrendEntity
+		suspected bool
+		timeToOom int64
+	}{
+		{
+			name: "steady growth",
+			trend: model.MemoryTrendEntity{
+				Samples: 11, Increases: 10, FirstUsed: 100, LastUsed: 600, MaxMemory: 1000, Slope: 0.5,
+			},
+			suspected: true,
+			timeToOom: 800,
+		},
+		{
+			name: "fluctuating",
+			trend: model.MemoryTrendEntity{
+				Samples: 11, Increases: 5, FirstUsed: 100, LastUsed: 600, MaxMemory: 1000, Slope: 0.5,
+			},
+			suspected: false,
+			timeToOom: 800,
+		},
+		{
+			name: "negligible growth",
+			trend: model.MemoryTrendEntity{
+				Samples: 11, Increases: 10, FirstUsed: 100, LastUsed: 110, MaxMemory: 1000, Slope: 0.125,
+			},
+			suspected: false,
+			timeToOom: 7120,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leak := memoryLeakDTO(tt.trend)
+			if leak.LeakSuspected != tt.suspected {
+				t.Errorf("Expected leak suspected to be %v, got %+v", tt.suspected, leak)
+			}
+			if leak.TimeToOom == nil || *leak.TimeToOom != tt.timeToOom {
+				t.Errorf("Expected time to OOM of %d, got %+v", tt.timeToOom, leak)
+			}
+		})
+	}
+
+	leak := memoryLeakDTO(model.MemoryTrendEntity{Samples: 5, Increases: 4, FirstUsed: 100, LastUsed: 500, MaxMemory: 1000, Slope: 0.2})
+	if !approx(leak.Growth, 12000) || !approx(leak.IncreasingShare, 1) || !approx(leak.LastRatio, 0.5) {
+		t.Errorf("Wrong growth, increasing share or last ratio: %+v", leak)
+	}
+}
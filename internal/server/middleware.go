@@ -2,19 +2,29 @@ package server
 
 import (
 	"ObservabilityServer/internal/auth"
+	"ObservabilityServer/internal/model"
+	"bytes"
+	"compress/gzip"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/labstack/echo/v4"
 )
 
 var apiAuthSecret = os.Getenv("OBSERVE_API_SECRET")
 
-// Validates the ApiKey passed via Authorization header(if any)
-// and sets the appId of the key on the echo Context
+// Validates the ApiKey passed via Authorization header(if any), which must
+// be neither revoked nor expired, and sets the appId, id and scopes of the
+// key on the echo Context. The scopes are enforced by each route.
 func (s *Server) APIKeyMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		apiKey := c.Request().Header.Get("Authorization")
@@ -24,24 +34,49 @@ func (s *Server) APIKeyMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 
 		apiKey = strings.TrimPrefix(apiKey, "Bearer ")
 
-		hashedApiKey := auth.HashApiKey(apiKey)
-
-		if ok := s.db.ValidateApiKey(hashedApiKey); !ok {
-			return echo.NewHTTPError(http.StatusUnauthorized, "Invalid API key")
-		}
-
-		appId, err := s.db.GetAppId(hashedApiKey)
+		key, ok, err := s.activeApiKey(auth.HashApiKey(apiKey))
 		if err != nil {
 			log.Println(err)
 			return echo.NewHTTPError(http.StatusInternalServerError, "Could not get info based on api key")
 		}
+		if !ok {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Invalid API key")
+		}
 
-		c.Set("appId", appId)
+		c.Set("appId", key.AppId)
+		c.Set("apiKeyId", key.Id)
+		c.Set("scopes", key.Scopes)
 
 		return next(c)
 	}
 }
 
+// activeApiKey returns the key with the hash, and records that it was used
+// if it was last used long enough ago. It returns false if the key does not
+// exist, or is revoked or expired.
+func (s *Server) activeApiKey(hashedApiKey string) (model.ApiKeyEntity, bool, error) {
+	now := time.Now().UnixMilli()
+	key, err := s.db.GetApiKey(hashedApiKey, now)
+	if errors.Is(err, sql.ErrNoRows) {
+		return key, false, nil
+	}
+	if err != nil {
+		return key, false, err
+	}
+
+	if apiKeyUsageStale(key, now) {
+		if err := s.db.MarkApiKeyUsed(hashedApiKey, now); err != nil {
+			log.Printf("Error marking api key as used: %v\n", err)
+		}
+	}
+
+	return key, true, nil
+}
+
+// Validates the session id passed via Authorization header, and sets the
+// session on the echo Context. GET requests may instead pass an ApiKey with
+// the 'read' scope, which sets the appId and scopes of the key like
+// APIKeyMiddleware. Handlers only let such keys read the data of their app.
 func (s *Server) AppAuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		authSecret := c.Request().Header.Get("Authorization")
@@ -57,6 +92,9 @@ func (s *Server) AppAuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 
 		session, err := s.db.GetAuthSession(authSecret)
 		if err != nil {
+			if c.Request().Method == http.MethodGet {
+				return s.readWithApiKey(c, authSecret, next)
+			}
 			return echo.NewHTTPError(http.StatusUnauthorized, "Unauthorized")
 		}
 
@@ -75,3 +113,71 @@ func (s *Server) AppAuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 		return next(c)
 	}
 }
+
+func (s *Server) readWithApiKey(c echo.Context, apiKey string, next echo.HandlerFunc) error {
+	key, ok, err := s.activeApiKey(auth.HashApiKey(apiKey))
+	if err != nil {
+		log.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Could not get info based on api key")
+	}
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Unauthorized")
+	}
+	if !hasAnyScope(key.Scopes, model.ScopeRead) {
+		return echo.NewHTTPError(http.StatusForbidden, "Api key is not allowed to read app data")
+	}
+
+	c.Set("appId", key.AppId)
+	c.Set("scopes", key.Scopes)
+
+	return next(c)
+}
+
+// Decompresses request bodies sent with 'Content-Encoding: gzip' or 'zstd'.
+// The decompressed body is limited to maxDecompressedBytes, to guard
+// against decompression bombs
+func (s *Server) DecompressMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		encoding := strings.ToLower(strings.TrimSpace(req.Header.Get("Content-Encoding")))
+
+		var reader io.ReadCloser
+		switch encoding {
+		case "", "identity":
+			return next(c)
+
+		case "gzip":
+			gz, err := gzip.NewReader(req.Body)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid gzip body: %v", err))
+			}
+			reader = gz
+
+		case "zstd":
+			zr, err := zstd.NewReader(req.Body, zstd.WithDecoderMaxMemory(uint64(s.maxDecompressedBytes)))
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid zstd body: %v", err))
+			}
+			reader = zr.IOReadCloser()
+
+		default:
+			return echo.NewHTTPError(http.StatusUnsupportedMediaType, fmt.Sprintf("Unsupported Content-Encoding '%s'", encoding))
+		}
+		defer reader.Close()
+
+		body, err := io.ReadAll(io.LimitReader(reader, s.maxDecompressedBytes+1))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Body could not be decompressed: %v", err))
+		}
+		if int64(len(body)) > s.maxDecompressedBytes {
+			return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Decompressed body exceeds %d bytes", s.maxDecompressedBytes))
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+		req.Header.Del("Content-Encoding")
+		req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+
+		return next(c)
+	}
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Path segments which are ids rather than part of the route, like numbers,
+// uuids and long hashes
+var idSegmentPattern = regexp.MustCompile(`^(\d+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9a-fA-F]{16,})$`)
+
+// normalizeUrlTemplate strips the query and fragment of the url, and
+// replaces path segments which look like ids with '{id}'. SDKs should send
+// templates, but this keeps the number of endpoints bounded if they don't.
+func normalizeUrlTemplate(rawUrl string) (host string, template string) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return "", rawUrl
+	}
+
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		if idSegmentPattern.MatchString(segment) {
+			segments[i] = "{id}"
+		}
+	}
+
+	template = strings.Join(segments, "/")
+	if u.Host != "" {
+		template = u.Scheme + "://" + u.Host + template
+	}
+	return u.Hostname(), template
+}
+
+func networkRequestData(appId int, dto model.NetworkRequestDTO) model.NewNetworkRequestData {
+	host, template := normalizeUrlTemplate(dto.UrlTemplate)
+	return model.NewNetworkRequestData{
+		Id:            dto.Id,
+		SessionId:     dto.SessionId,
+		TraceId:       dto.TraceId,
+		AppId:         appId,
+		Host:          host,
+		UrlTemplate:   template,
+		Method:        dto.Method,
+		StatusCode:    dto.StatusCode,
+		Duration:      dto.Duration,
+		RequestSize:   dto.RequestSize,
+		ResponseSize:  dto.ResponseSize,
+		FailureReason: dto.FailureReason,
+		StartedAt:     dto.StartedAt,
+	}
+}
+
+func networkRequestDTO(ent model.NetworkRequestEntity) model.NetworkRequestDTO {
+	return model.NetworkRequestDTO{
+		Id:            ent.Id,
+		SessionId:     ent.SessionId,
+		TraceId:       ent.TraceId,
+		UrlTemplate:   ent.UrlTemplate,
+		Method:        ent.Method,
+		StatusCode:    ent.StatusCode,
+		Duration:      ent.Duration,
+		RequestSize:   ent.RequestSize,
+		ResponseSize:  ent.ResponseSize,
+		FailureReason: ent.FailureReason,
+		StartedAt:     ent.StartedAt,
+	}
+}
+
+func networkEndpointDTO(ent model.NetworkEndpointEntity, withStart, withEndpoint bool) model.NetworkEndpointDTO {
+	dto := model.NetworkEndpointDTO{
+		Requests:        ent.Requests,
+		Errors:          ent.Errors,
+		ErrorRate:       rate(ent.Errors, ent.Requests),
+		DurationP50:     ent.DurationP50,
+		DurationP95:     ent.DurationP95,
+		DurationP99:     ent.DurationP99,
+		AvgResponseSize: ent.AvgResponseSize,
+	}
+	if withStart {
+		dto.Start = ent.Bucket
+	}
+	if withEndpoint {
+		dto.Method = ent.Method
+		dto.UrlTemplate = ent.UrlTemplate
+	}
+	return dto
+}
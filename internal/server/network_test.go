@@ -0,0 +1,46 @@
+package server
+
+import "testing"
+
+func TestNormalizeUrlTemplate(t *testing.T) {
+	tests := []struct {
+		url      string
+		host     string
+		template string
+	}{
+		{
+			url:      "https://api.example.com/users/{id}/orders",
+			host:     "api.example.com",
+			template: "https://api.example.com/users/{id}/orders",
+		},
+		{
+			url:      "https://api.example.com:8443/users/1234/orders?page=2#top",
+			host:     "api.example.com",
+			template: "https://api.example.com:8443/users/{id}/orders",
+		},
+		{
+			url:      "https://cdn.example.com/images/3f2b8c1e-5d4a-4b6f-9e8d-7c6b5a4f3e2d/thumb",
+			host:     "cdn.example.com",
+			template: "https://cdn.example.com/images/{id}/thumb",
+		},
+		{
+			url:      "https://cdn.example.com/assets/9f86d081884c7d659a2feaa0c55ad015.js",
+			host:     "cdn.example.com",
+			template: "https://cdn.example.com/assets/9f86d081884c7d659a2feaa0c55ad015.js",
+		},
+		{
+			url:      "https://api.example.com/v2/sha/9f86d081884c7d659a2feaa0c55ad015",
+			host:     "api.example.com",
+			template: "https://api.example.com/v2/sha/{id}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			host, template := normalizeUrlTemplate(tt.url)
+			if host != tt.host || template != tt.template {
+				t.Errorf("Expected %s and %s, got %s and %s", tt.host, tt.template, host, template)
+			}
+		})
+	}
+}
@@ -0,0 +1,669 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"ObservabilityServer/internal/pb"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	otlpSpanStatusOk    = 1
+	otlpSpanStatusError = 2
+
+	otlpScopeName = "ObservabilityServer"
+
+	otlpLogEventType = "otel.log"
+
+	otlpDefaultInstallationId = "otel"
+)
+
+// Attributes used to find the session and installation of OTLP data. Record
+// attributes take precedence over resource attributes
+var (
+	otlpSessionAttributes      = []string{"session.id"}
+	otlpInstallationAttributes = []string{"device.id", "service.instance.id", "service.name"}
+	otlpAppVersionAttributes   = []string{"service.version"}
+)
+
+// otlpSession is a session which is created on the fly for OTLP data, since
+// OTel SDKs never report sessions explicitly
+type otlpSession struct {
+	installationId string
+	appVersion     string
+	createdAt      int64
+}
+
+type otlpBatch struct {
+	appId    int
+	sessions map[string]*otlpSession
+	events   []model.EventDTO
+	traces   []model.TraceDTO
+	rejected int64
+	errors   []string
+}
+
+func newOTLPBatch(appId int) *otlpBatch {
+	return &otlpBatch{
+		appId:    appId,
+		sessions: make(map[string]*otlpSession),
+		events:   make([]model.EventDTO, 0),
+		traces:   make([]model.TraceDTO, 0),
+		errors:   make([]string, 0),
+	}
+}
+
+/**
+* @api {post} /v1/traces Export OTLP traces
+* @apiName ExportOTLPTraces
+* @apiGroup OTLP
+*
+* @apiDescription OpenTelemetry OTLP/HTTP trace receiver. Point the OTLP
+* exporter of an OTel SDK at the server and set the api key header.
+* Both 'application/x-protobuf' and 'application/json' are accepted, and
+* the response uses the same encoding as the request.
+*
+* Each span is stored as a trace. The span id becomes the traceId, the OTel
+* trace id becomes the groupId and the parent span id becomes the parentId.
+* The session is taken from the 'session.id' attribute of the span or its
+* resource. Spans without a session are rejected.
+* Spans which could not be stored, or exceed the monthly quota of the app,
+* are reported as rejected in the partialSuccess of the response. Spans
+* dropped by the sampling rules of the app are not reported.
+*
+* @apiUse ApiKeyAuth
+* @apiUse CompressedBody
+ */
+func (s *Server) exportOTLPTracesHandler(c echo.Context) error {
+	appId := c.Get("appId")
+	if appId == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Missing app id")
+	}
+
+	var req pb.ExportTraceServiceRequest
+	if err := readOTLPRequest(c, &req); err != nil {
+		return err
+	}
+
+	batch := newOTLPBatch(appId.(int))
+	for _, rs := range req.ResourceSpans {
+		resource := rs.Resource.GetAttributes()
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				batch.addSpan(span, resource)
+			}
+		}
+	}
+	s.writeOTLPBatch(batch)
+
+	res := &pb.ExportTraceServiceResponse{}
+	if batch.rejected > 0 {
+		res.PartialSuccess = &pb.ExportTracePartialSuccess{
+			RejectedSpans: batch.rejected,
+			ErrorMessage:  batch.errorMessage(),
+		}
+	}
+
+	return writeOTLPResponse(c, res)
+}
+
+/**
+* @api {post} /v1/logs Export OTLP logs
+* @apiName ExportOTLPLogs
+* @apiGroup OTLP
+*
+* @apiDescription OpenTelemetry OTLP/HTTP log receiver. Point the OTLP
+* exporter of an OTel SDK at the server and set the api key header.
+* Both 'application/x-protobuf' and 'application/json' are accepted, and
+* the response uses the same encoding as the request.
+*
+* Each log record is stored as an event of type 'otel.log', or of its event
+* name if set. The serializedData of the event contains the body, severity,
+* attributes and span context of the record.
+* The session is taken from the 'session.id' attribute of the record or its
+* resource. Records without a session are rejected.
+* Records which could not be stored, or exceed the monthly quota of the app,
+* are reported as rejected in the partialSuccess of the response. Records
+* dropped by the sampling rules of the app are not reported.
+*
+* @apiUse ApiKeyAuth
+* @apiUse CompressedBody
+ */
+func (s *Server) exportOTLPLogsHandler(c echo.Context) error {
+	appId := c.Get("appId")
+	if appId == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Missing app id")
+	}
+
+	var req pb.ExportLogsServiceRequest
+	if err := readOTLPRequest(c, &req); err != nil {
+		return err
+	}
+
+	batch := newOTLPBatch(appId.(int))
+	for _, rl := range req.ResourceLogs {
+		resource := rl.Resource.GetAttributes()
+		for _, sl := range rl.ScopeLogs {
+			for _, record := range sl.LogRecords {
+				batch.addLogRecord(record, resource)
+			}
+		}
+	}
+	s.writeOTLPBatch(batch)
+
+	res := &pb.ExportLogsServiceResponse{}
+	if batch.rejected > 0 {
+		res.PartialSuccess = &pb.ExportLogsPartialSuccess{
+			RejectedLogRecords: batch.rejected,
+			ErrorMessage:       batch.errorMessage(),
+		}
+	}
+
+	return writeOTLPResponse(c, res)
+}
+
+func (b *otlpBatch) reject(format string, args ...any) {
+	b.rejected++
+	b.errors = append(b.errors, fmt.Sprintf(format, args...))
+}
+
+func (b *otlpBatch) errorMessage() string {
+	if len(b.errors) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d records rejected, first: %s", b.rejected, b.errors[0])
+}
+
+func (b *otlpBatch) addSession(id string, resource []*pb.KeyValue, createdAt int64) {
+	session, ok := b.sessions[id]
+	if !ok {
+		installationId := otlpLookupAttribute(nil, resource, otlpInstallationAttributes)
+		if installationId == "" {
+			installationId = otlpDefaultInstallationId
+		}
+		b.sessions[id] = &otlpSession{
+			installationId: installationId,
+			appVersion:     otlpLookupAttribute(nil, resource, otlpAppVersionAttributes),
+			createdAt:      createdAt,
+		}
+		return
+	}
+
+	if createdAt > 0 && (session.createdAt == 0 || createdAt < session.createdAt) {
+		session.createdAt = createdAt
+	}
+}
+
+func (b *otlpBatch) addSpan(span *pb.Span, resource []*pb.KeyValue) {
+	if len(span.SpanId) != 8 || len(span.TraceId) != 16 {
+		b.reject("span '%s' has an invalid span or trace id", span.Name)
+		return
+	}
+	if span.StartTimeUnixNano == 0 {
+		b.reject("span '%s' has no start time", span.Name)
+		return
+	}
+
+	groupId := hex.EncodeToString(span.TraceId)
+	sessionId := otlpLookupAttribute(span.Attributes, resource, otlpSessionAttributes)
+	if sessionId == "" {
+		b.reject("span '%s' has no session.id attribute", span.Name)
+		return
+	}
+
+	trace := model.TraceDTO{
+		TraceId:    hex.EncodeToString(span.SpanId),
+		SessionId:  sessionId,
+		GroupId:    groupId,
+		ParentId:   hex.EncodeToString(span.ParentSpanId),
+		Name:       span.Name,
+		Status:     "Ok",
+		StartedAt:  otlpMillis(span.StartTimeUnixNano),
+		EndedAt:    otlpMillis(span.EndTimeUnixNano),
+		HasEnded:   span.EndTimeUnixNano > 0,
+		Attributes: otlpScalarAttributes(span.Attributes),
+	}
+	if span.Status.GetCode() == otlpSpanStatusError {
+		trace.Status = "Error"
+		trace.ErrorMessage = span.Status.GetMessage()
+		if trace.ErrorMessage == "" {
+			trace.ErrorMessage = "Error"
+		}
+	}
+
+	b.addSession(sessionId, resource, trace.StartedAt)
+	b.traces = append(b.traces, trace)
+}
+
+func (b *otlpBatch) addLogRecord(record *pb.LogRecord, resource []*pb.KeyValue) {
+	createdAt := otlpMillis(record.TimeUnixNano)
+	if createdAt == 0 {
+		createdAt = otlpMillis(record.ObservedTimeUnixNano)
+	}
+	if createdAt == 0 {
+		b.reject("log record has no timestamp")
+		return
+	}
+
+	sessionId := otlpLookupAttribute(record.Attributes, resource, otlpSessionAttributes)
+	if sessionId == "" {
+		b.reject("log record has no session.id attribute")
+		return
+	}
+
+	data := map[string]any{
+		"severityNumber": record.SeverityNumber,
+		"severityText":   record.SeverityText,
+		"body":           otlpValue(record.Body),
+		"attributes":     otlpAttributes(record.Attributes),
+	}
+	if len(record.TraceId) > 0 {
+		data["traceId"] = hex.EncodeToString(record.TraceId)
+	}
+	if len(record.SpanId) > 0 {
+		data["spanId"] = hex.EncodeToString(record.SpanId)
+	}
+	serializedData, err := json.Marshal(data)
+	if err != nil {
+		b.reject("log record could not be serialized: %v", err)
+		return
+	}
+
+	eventType := record.EventName
+	if eventType == "" {
+		eventType = otlpLogEventType
+	}
+
+	b.addSession(sessionId, resource, createdAt)
+	b.events = append(b.events, model.EventDTO{
+		Id:             otlpLogRecordId(record, resource),
+		SessionId:      sessionId,
+		Type:           eventType,
+		SerializedData: string(serializedData),
+		Attributes:     otlpScalarAttributes(record.Attributes),
+		CreatedAt:      createdAt,
+	})
+}
+
+// writeOTLPBatch stores the batch and counts every record that failed, or
+// exceeds the quota of the app, as rejected. Records dropped by sampling are
+// not rejected, since they should not be retried. Only the sessions of
+// admitted records are created, before the records which reference them
+func (s *Server) writeOTLPBatch(batch *otlpBatch) {
+	sessions := make([]model.SessionEntity, 0, len(batch.sessions))
+	for id, session := range batch.sessions {
+		sessions = append(sessions, model.SessionEntity{Id: id, AppVersion: session.appVersion})
+	}
+	admitted := s.admitIngestion(batch.appId, batch.events, batch.traces, sessions)
+
+	created := make(map[string]bool)
+	createSession := func(id string) {
+		if created[id] {
+			return
+		}
+		created[id] = true
+		session := batch.sessions[id]
+		err := s.db.CreateSession(model.NewSessionData{
+			Id:             id,
+			InstallationId: session.installationId,
+			AppId:          batch.appId,
+			AppVersion:     session.appVersion,
+			CreatedAt:      session.createdAt,
+		})
+		if err != nil {
+			log.Printf("Error creating session %s from OTLP data: %v\n", id, err)
+		}
+	}
+	for _, event := range admitted.events {
+		createSession(event.SessionId)
+	}
+	for _, trace := range admitted.traces {
+		createSession(trace.SessionId)
+	}
+
+	if n := admitted.drops.OverQuotaEvents + admitted.drops.OverQuotaTraces; n > 0 {
+		batch.rejected += int64(n)
+		batch.errors = append(batch.errors, fmt.Sprintf("%d records exceed the monthly quota of the app", n))
+	}
+
+	failures, events, traces := s.writeCollection(collectionJob{
+		AppId: batch.appId,
+		Collection: model.CollectionDTO{
+			Events: admitted.events,
+			Traces: admitted.traces,
+		},
+	})
+	if admitted.usage != nil {
+		s.settleIngestion(batch.appId, *admitted.usage, events, traces, 1)
+	}
+	if len(failures) > 0 {
+		// Nothing of the batch is stored if any record fails
+		batch.rejected += int64(len(admitted.events) + len(admitted.traces))
+		batch.errors = append(batch.errors, failures[0].Message)
+	}
+}
+
+// otlpLogRecordId derives a stable id for a log record, which has no id of
+// its own, so that a retried export does not store the record twice
+func otlpLogRecordId(record *pb.LogRecord, resource []*pb.KeyValue) string {
+	opts := proto.MarshalOptions{Deterministic: true}
+	hash := sha256.New()
+	if data, err := opts.Marshal(record); err == nil {
+		hash.Write(data)
+	}
+	if data, err := opts.Marshal(&pb.Resource{Attributes: resource}); err == nil {
+		hash.Write(data)
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+	return fmt.Sprintf("%s-%s-%s-%s-%s", sum[0:8], sum[8:12], sum[12:16], sum[16:20], sum[20:32])
+}
+
+func otlpLookupAttribute(attributes, resource []*pb.KeyValue, keys []string) string {
+	for _, set := range [][]*pb.KeyValue{attributes, resource} {
+		for _, key := range keys {
+			for _, kv := range set {
+				if kv.Key == key && kv.Value.GetStringValue() != "" {
+					return kv.Value.GetStringValue()
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func otlpAttributes(attributes []*pb.KeyValue) map[string]any {
+	values := make(map[string]any, len(attributes))
+	for _, kv := range attributes {
+		values[kv.Key] = otlpValue(kv.Value)
+	}
+	return values
+}
+
+// otlpScalarAttributes keeps the attributes which can be stored as typed
+// attributes of events and traces. The session id is left out, as are
+// attributes which would not pass validation.
+func otlpScalarAttributes(attributes []*pb.KeyValue) map[string]any {
+	values := make(map[string]any)
+	for _, kv := range attributes {
+		if len(values) == maxAttributes {
+			break
+		}
+		if slices.Contains(otlpSessionAttributes, kv.Key) || !attributeKeyPattern.MatchString(kv.Key) {
+			continue
+		}
+
+		switch value := otlpValue(kv.Value).(type) {
+		case string:
+			if len(value) <= maxAttributeValueLength {
+				values[kv.Key] = value
+			}
+		case bool, int64, float64:
+			values[kv.Key] = value
+		}
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+func otlpValue(value *pb.AnyValue) any {
+	switch v := value.GetValue().(type) {
+	case *pb.AnyValue_StringValue:
+		return v.StringValue
+	case *pb.AnyValue_BoolValue:
+		return v.BoolValue
+	case *pb.AnyValue_IntValue:
+		return v.IntValue
+	case *pb.AnyValue_DoubleValue:
+		if math.IsNaN(v.DoubleValue) || math.IsInf(v.DoubleValue, 0) {
+			return fmt.Sprint(v.DoubleValue)
+		}
+		return v.DoubleValue
+	case *pb.AnyValue_BytesValue:
+		return base64.StdEncoding.EncodeToString(v.BytesValue)
+	case *pb.AnyValue_ArrayValue:
+		values := make([]any, len(v.ArrayValue.GetValues()))
+		for i, item := range v.ArrayValue.GetValues() {
+			values[i] = otlpValue(item)
+		}
+		return values
+	case *pb.AnyValue_KvlistValue:
+		return otlpAttributes(v.KvlistValue.GetValues())
+	default:
+		return nil
+	}
+}
+
+func otlpMillis(nanos uint64) int64 {
+	return int64(nanos / 1_000_000)
+}
+
+func otlpNanos(millis int64) uint64 {
+	if millis <= 0 {
+		return 0
+	}
+	return uint64(millis) * 1_000_000
+}
+
+// otlpId converts a stored id to an OTel id of the given size. Ids received
+// through OTLP and UUIDs are hex and converted as is, anything else is hashed
+func otlpId(id string, size int) []byte {
+	if id == "" {
+		return nil
+	}
+
+	if raw, err := hex.DecodeString(strings.ReplaceAll(id, "-", "")); err == nil && len(raw) == size {
+		return raw
+	}
+
+	sum := sha256.Sum256([]byte(id))
+	return sum[:size]
+}
+
+// tracesToOTLP converts the traces of a session to an OTLP export request,
+// the format read by tracing backends like Jaeger and Tempo
+func tracesToOTLP(app model.ApplicationEntity, session model.SessionEntity, traces []model.TraceEntity) *pb.ExportTraceServiceRequest {
+	spans := make([]*pb.Span, len(traces))
+	for i, trace := range traces {
+		span := &pb.Span{
+			TraceId:           otlpId(trace.GroupId, 16),
+			SpanId:            otlpId(trace.TraceId, 8),
+			ParentSpanId:      otlpId(trace.ParentId, 8),
+			Name:              trace.Name,
+			StartTimeUnixNano: otlpNanos(trace.StartedAt),
+			Status:            &pb.Status{Code: otlpSpanStatusOk},
+			Attributes: []*pb.KeyValue{
+				otlpStringAttribute("session.id", trace.SessionId),
+			},
+		}
+		for _, key := range slices.Sorted(maps.Keys(trace.Attributes)) {
+			if value := otlpAnyValue(trace.Attributes[key]); value != nil {
+				span.Attributes = append(span.Attributes, &pb.KeyValue{Key: key, Value: value})
+			}
+		}
+		if trace.HasEnded {
+			span.EndTimeUnixNano = otlpNanos(trace.EndedAt)
+		}
+		if trace.Status == "Error" {
+			span.Status = &pb.Status{
+				Code:    otlpSpanStatusError,
+				Message: trace.ErrorMessage,
+			}
+		}
+		spans[i] = span
+	}
+
+	return &pb.ExportTraceServiceRequest{
+		ResourceSpans: []*pb.ResourceSpans{
+			{
+				Resource: &pb.Resource{
+					Attributes: []*pb.KeyValue{
+						otlpStringAttribute("service.name", app.Name),
+						otlpStringAttribute("session.id", session.Id),
+						otlpStringAttribute("device.id", session.InstallationId),
+					},
+				},
+				ScopeSpans: []*pb.ScopeSpans{
+					{
+						Scope: &pb.InstrumentationScope{Name: otlpScopeName},
+						Spans: spans,
+					},
+				},
+			},
+		},
+	}
+}
+
+// otlpAnyValue converts a stored attribute value. Numbers are stored as JSON,
+// so whole numbers are exported as integers.
+func otlpAnyValue(value any) *pb.AnyValue {
+	switch v := value.(type) {
+	case string:
+		return &pb.AnyValue{Value: &pb.AnyValue_StringValue{StringValue: v}}
+	case bool:
+		return &pb.AnyValue{Value: &pb.AnyValue_BoolValue{BoolValue: v}}
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return &pb.AnyValue{Value: &pb.AnyValue_IntValue{IntValue: int64(v)}}
+		}
+		return &pb.AnyValue{Value: &pb.AnyValue_DoubleValue{DoubleValue: v}}
+	default:
+		return nil
+	}
+}
+
+func otlpStringAttribute(key, value string) *pb.KeyValue {
+	return &pb.KeyValue{
+		Key:   key,
+		Value: &pb.AnyValue{Value: &pb.AnyValue_StringValue{StringValue: value}},
+	}
+}
+
+func isJSONRequest(req *http.Request) bool {
+	base, _, _ := strings.Cut(req.Header.Get(echo.HeaderContentType), ";")
+	return strings.TrimSpace(base) == echo.MIMEApplicationJSON
+}
+
+func readOTLPRequest(c echo.Context, msg proto.Message) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Body could not be read: %v", err))
+	}
+
+	switch {
+	case isProtobufRequest(c.Request()):
+		err = proto.Unmarshal(body, msg)
+	case isJSONRequest(c.Request()):
+		err = unmarshalOTLPJSON(body, msg)
+	default:
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, "Content type must be application/x-protobuf or application/json")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Body could not be parsed: %v", err))
+	}
+
+	return nil
+}
+
+func writeOTLPResponse(c echo.Context, msg proto.Message) error {
+	if isJSONRequest(c.Request()) {
+		data, err := protojson.Marshal(msg)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		return c.JSONBlob(http.StatusOK, data)
+	}
+
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.Blob(http.StatusOK, MIMEApplicationProtobuf, data)
+}
+
+// otlpIdFields are encoded as hex strings in OTLP/JSON instead of the base64
+// used by the standard protobuf JSON mapping
+var otlpIdFields = map[string]bool{
+	"traceId":      true,
+	"spanId":       true,
+	"parentSpanId": true,
+}
+
+// unmarshalOTLPJSON decodes the OTLP/JSON encoding, which differs from the
+// standard protobuf JSON mapping only in how ids are encoded
+func unmarshalOTLPJSON(body []byte, msg proto.Message) error {
+	converted, err := otlpConvertJSON(body, hex.DecodeString, base64.StdEncoding.EncodeToString)
+	if err != nil {
+		return err
+	}
+
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(converted, msg)
+}
+
+// marshalOTLPJSON encodes msg in the OTLP/JSON encoding
+func marshalOTLPJSON(msg proto.Message) ([]byte, error) {
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return otlpConvertJSON(data, base64.StdEncoding.DecodeString, hex.EncodeToString)
+}
+
+func otlpConvertJSON(body []byte, decode func(string) ([]byte, error), encode func([]byte) string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+	if err := otlpConvertIds(data, decode, encode); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(data)
+}
+
+func otlpConvertIds(data any, decode func(string) ([]byte, error), encode func([]byte) string) error {
+	switch v := data.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if id, ok := value.(string); ok && otlpIdFields[key] {
+				raw, err := decode(id)
+				if err != nil {
+					return fmt.Errorf("%s '%s' is not encoded correctly", key, id)
+				}
+				v[key] = encode(raw)
+				continue
+			}
+			if err := otlpConvertIds(value, decode, encode); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, value := range v {
+			if err := otlpConvertIds(value, decode, encode); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// parsePageQuery reads the 'from', 'to', 'limit' and 'release' query params
+// and the cursor from cursorParam. Errors are returned as bad request.
+func parsePageQuery(c echo.Context, cursorParam string) (model.PageQuery, error) {
+	var page model.PageQuery
+
+	for param, dest := range map[string]*int64{"from": &page.From, "to": &page.To} {
+		value := c.QueryParam(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 0 {
+			return page, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query param '%s' must be a positive timestamp", param))
+		}
+		*dest = parsed
+	}
+	if page.From > 0 && page.To > 0 && page.From > page.To {
+		return page, echo.NewHTTPError(http.StatusBadRequest, "Query param 'from' must not be after 'to'")
+	}
+
+	if value := c.QueryParam("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > model.MaxPageLimit {
+			return page, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query param 'limit' must be between 1 and %d", model.MaxPageLimit))
+		}
+		page.Limit = limit
+	}
+
+	page.Release = c.QueryParam("release")
+
+	page.Cursor = c.QueryParam(cursorParam)
+	if page.Cursor != "" {
+		if _, err := model.DecodeCursor(page.Cursor); err != nil {
+			return page, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query param '%s' is not a valid cursor", cursorParam))
+		}
+	}
+
+	return page, nil
+}
+
+func pageDTO(page model.PageQuery, nextCursor string) model.PageDTO {
+	return model.PageDTO{
+		Limit:      page.NormalizedLimit(),
+		NextCursor: nextCursor,
+	}
+}
+
+// getAllSessionTraces loads every trace of a session within the time range
+// of page, for views which need the complete set of traces, like the trace
+// tree. The cursor and limit of page are ignored.
+func (s *Server) getAllSessionTraces(sessionId string, page model.PageQuery) ([]model.TraceEntity, error) {
+	page.Cursor = ""
+	page.Limit = model.MaxPageLimit
+
+	traces := make([]model.TraceEntity, 0)
+	for {
+		entities, next, err := s.db.GetTracesBySessionId(sessionId, page)
+		if err != nil {
+			return nil, err
+		}
+		traces = append(traces, entities...)
+		if next == "" {
+			return traces, nil
+		}
+		page.Cursor = next
+	}
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/labstack/echo/v4"
+)
+
+// permission is what a route lets a member of a team do. Each permission
+// includes the ones before it.
+type permission int
+
+const (
+	// Read the data of the team and its apps
+	permissionRead permission = iota
+	// Create apps and upload mappings
+	permissionWrite
+	// Add users to the team and create api keys for its apps
+	permissionManage
+)
+
+// The highest permission granted by each role
+var rolePermissions = map[string]permission{
+	model.RoleOwner:     permissionManage,
+	model.RoleAdmin:     permissionManage,
+	model.RoleDeveloper: permissionWrite,
+	model.RoleViewer:    permissionRead,
+}
+
+// roleAllows reports whether the role grants the permission. Unknown roles
+// grant nothing.
+func roleAllows(role string, p permission) bool {
+	granted, ok := rolePermissions[role]
+	return ok && granted >= p
+}
+
+// canGrantRole reports whether a user with the role granter may add users
+// with the given role. Only owners can make others owners.
+func canGrantRole(granter, role string) bool {
+	if !roleAllows(granter, permissionManage) {
+		return false
+	}
+	return role != model.RoleOwner || granter == model.RoleOwner
+}
+
+// teamRole returns the role of the signed in user in the team, and false if
+// they are not a member of it
+func (s *Server) teamRole(c echo.Context, teamId int) (string, bool) {
+	session, ok := c.Get("session").(model.AuthSessionEntity)
+	if !ok {
+		return "", false
+	}
+	role, err := s.db.GetTeamUserRole(teamId, session.UserId)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Error getting role of user '%d' in team '%d': %v\n", session.UserId, teamId, err)
+		}
+		return "", false
+	}
+	return role, true
+}
+
+// canReadApp reports whether the request may read the data of the app. Users
+// must be members of its team, and api keys must belong to the app and have
+// the read scope.
+func (s *Server) canReadApp(c echo.Context, app model.ApplicationEntity) bool {
+	if session, ok := c.Get("session").(model.AuthSessionEntity); ok {
+		return s.db.ValidateTeamUserLink(app.TeamId, session.UserId)
+	}
+
+	appId, ok := c.Get("appId").(int)
+	scopes, _ := c.Get("scopes").([]string)
+	return ok && appId == app.Id && hasAnyScope(scopes, model.ScopeRead)
+}
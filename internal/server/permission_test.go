@@ -0,0 +1,54 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"testing"
+)
+
+func TestRoleAllows(t *testing.T) {
+	tests := []struct {
+		role     string
+		allowed  []permission
+		excluded []permission
+	}{
+		{role: model.RoleOwner, allowed: []permission{permissionRead, permissionWrite, permissionManage}},
+		{role: model.RoleAdmin, allowed: []permission{permissionRead, permissionWrite, permissionManage}},
+		{role: model.RoleDeveloper, allowed: []permission{permissionRead, permissionWrite}, excluded: []permission{permissionManage}},
+		{role: model.RoleViewer, allowed: []permission{permissionRead}, excluded: []permission{permissionWrite, permissionManage}},
+		{role: "User", excluded: []permission{permissionRead, permissionWrite, permissionManage}},
+	}
+
+	for _, tt := range tests {
+		for _, p := range tt.allowed {
+			if !roleAllows(tt.role, p) {
+				t.Errorf("roleAllows(%q, %d) = false, expected true", tt.role, p)
+			}
+		}
+		for _, p := range tt.excluded {
+			if roleAllows(tt.role, p) {
+				t.Errorf("roleAllows(%q, %d) = true, expected false", tt.role, p)
+			}
+		}
+	}
+}
+
+func TestCanGrantRole(t *testing.T) {
+	tests := []struct {
+		granter  string
+		role     string
+		expected bool
+	}{
+		{granter: model.RoleOwner, role: model.RoleOwner, expected: true},
+		{granter: model.RoleOwner, role: model.RoleViewer, expected: true},
+		{granter: model.RoleAdmin, role: model.RoleAdmin, expected: true},
+		{granter: model.RoleAdmin, role: model.RoleOwner, expected: false},
+		{granter: model.RoleDeveloper, role: model.RoleViewer, expected: false},
+		{granter: model.RoleViewer, role: model.RoleViewer, expected: false},
+	}
+
+	for _, tt := range tests {
+		if actual := canGrantRole(tt.granter, tt.role); actual != tt.expected {
+			t.Errorf("canGrantRole(%q, %q) = %v, expected %v", tt.granter, tt.role, actual, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package server
+
+import "ObservabilityServer/internal/model"
+
+// getReleaseHealth loads the releases of an app with the metrics of their
+// sessions within the time range of page
+func (s *Server) getReleaseHealth(appId int, page model.PageQuery) ([]model.ReleaseHealthDTO, error) {
+	releases, err := s.db.GetReleases(appId)
+	if err != nil {
+		return nil, err
+	}
+
+	stability, err := s.db.GetStability(appId, model.StabilityQuery{From: page.From, To: page.To, ByRelease: true})
+	if err != nil {
+		return nil, err
+	}
+
+	anrRates, err := s.db.GetAnrRates(appId, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return releaseHealth(releases, stability, anrRates), nil
+}
+
+// releaseHealth summarizes the stability of each release, in the order of
+// releases. stability and anrRates are the metrics per app version within
+// the same time range. Each release is compared with the next older release
+// that had sessions in that range.
+func releaseHealth(releases []model.ReleaseEntity, stability []model.StabilityEntity, anrRates []model.AnrRateEntity) []model.ReleaseHealthDTO {
+	byVersion := make(map[string]model.StabilityEntity, len(stability))
+	total := 0
+	for _, ent := range stability {
+		byVersion[ent.Group] = ent
+		total += ent.Sessions
+	}
+	anrsByVersion := make(map[string]model.AnrRateEntity, len(anrRates))
+	for _, ent := range anrRates {
+		anrsByVersion[ent.AppVersion] = ent
+	}
+
+	DTOS := make([]model.ReleaseHealthDTO, len(releases))
+	for i, release := range releases {
+		ent := byVersion[release.Version]
+		anrs := anrsByVersion[release.Version]
+		DTOS[i] = model.ReleaseHealthDTO{
+			ReleaseDTO: model.ReleaseDTO{
+				Version:     release.Version,
+				VersionCode: release.VersionCode,
+				FirstSeen:   release.FirstSeen,
+				LastSeen:    release.LastSeen,
+			},
+			Sessions:                  ent.Sessions,
+			CrashedSessions:           ent.CrashedSessions,
+			CrashFreeSessionRate:      crashFreeRate(ent.CrashedSessions, ent.Sessions),
+			Installations:             ent.Installations,
+			CrashedInstallations:      ent.CrashedInstallations,
+			CrashFreeInstallationRate: crashFreeRate(ent.CrashedInstallations, ent.Installations),
+			AnrSessions:               anrs.AnrSessions,
+			AnrRate:                   rate(anrs.AnrSessions, anrs.Sessions),
+			Adoption:                  rate(ent.Sessions, total),
+		}
+	}
+
+	for i := range DTOS {
+		if DTOS[i].Sessions == 0 {
+			continue
+		}
+		for j := i + 1; j < len(DTOS); j++ {
+			previous := DTOS[j]
+			if previous.Sessions == 0 {
+				continue
+			}
+			crashRate := rate(DTOS[i].CrashedSessions, DTOS[i].Sessions)
+			previousCrashRate := rate(previous.CrashedSessions, previous.Sessions)
+			DTOS[i].Comparison = &model.ReleaseComparisonDTO{
+				PreviousVersion:   previous.Version,
+				CrashRate:         crashRate,
+				PreviousCrashRate: previousCrashRate,
+				CrashRateChange:   crashRate - previousCrashRate,
+				PreviousAnrRate:   previous.AnrRate,
+				AnrRateChange:     DTOS[i].AnrRate - previous.AnrRate,
+			}
+			break
+		}
+	}
+
+	return DTOS
+}
+
+// releaseAdoption returns the share of each release of the sessions and
+// installations per bucket. totals are the metrics of all releases per
+// bucket and byRelease the metrics per bucket and release, both ordered by
+// bucket. Installations are not summed over releases, since an installation
+// that updated within a bucket has sessions of both releases.
+func releaseAdoption(totals, byRelease []model.StabilityEntity) []model.ReleaseAdoptionDTO {
+	DTOS := make([]model.ReleaseAdoptionDTO, len(totals))
+	index := make(map[int64]int, len(totals))
+	for i, ent := range totals {
+		DTOS[i] = model.ReleaseAdoptionDTO{
+			Start:         ent.Bucket,
+			Sessions:      ent.Sessions,
+			Installations: ent.Installations,
+			Releases:      make([]model.ReleaseAdoptionVersionDTO, 0),
+		}
+		index[ent.Bucket] = i
+	}
+
+	for _, ent := range byRelease {
+		i, ok := index[ent.Bucket]
+		if !ok {
+			continue
+		}
+		DTOS[i].Releases = append(DTOS[i].Releases, model.ReleaseAdoptionVersionDTO{
+			Version:           ent.Group,
+			Sessions:          ent.Sessions,
+			Installations:     ent.Installations,
+			SessionShare:      rate(ent.Sessions, DTOS[i].Sessions),
+			InstallationShare: rate(ent.Installations, DTOS[i].Installations),
+		})
+	}
+
+	return DTOS
+}
+
+func rate(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) / float64(total)
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"math"
+	"testing"
+)
+
+func TestReleaseHealth(t *testing.T) {
+	releases := []model.ReleaseEntity{
+		{Version: "1.2.0", VersionCode: 12},
+		{Version: "1.1.0", VersionCode: 11},
+		{Version: "1.0.0", VersionCode: 10},
+	}
+	stability := []model.StabilityEntity{
+		{Group: "1.2.0", Sessions: 50, CrashedSessions: 5, Installations: 20, CrashedInstallations: 4},
+		{Group: "1.0.0", Sessions: 40, CrashedSessions: 2, Installations: 10, CrashedInstallations: 2},
+		{Group: "", Sessions: 10},
+	}
+	anrRates := []model.AnrRateEntity{
+		{AppVersion: "1.2.0", Sessions: 50, AnrSessions: 10, Anrs: 12},
+		{AppVersion: "1.0.0", Sessions: 40, AnrSessions: 4, Anrs: 4},
+	}
+
+	health := releaseHealth(releases, stability, anrRates)
+	if len(health) != 3 {
+		t.Fatalf("Expected 3 releases but was %d", len(health))
+	}
+
+	latest := health[0]
+	if latest.Version != "1.2.0" || !approx(latest.CrashFreeSessionRate, 0.9) || !approx(latest.CrashFreeInstallationRate, 0.8) {
+		t.Errorf("Wrong crash-free rates of latest release: %+v", latest)
+	}
+	if !approx(latest.Adoption, 0.5) || !approx(latest.AnrRate, 0.2) {
+		t.Errorf("Wrong adoption or ANR rate of latest release: %+v", latest)
+	}
+
+	// 1.1.0 has no sessions in the range, so 1.2.0 is compared with 1.0.0
+	comparison := latest.Comparison
+	if comparison == nil || comparison.PreviousVersion != "1.0.0" {
+		t.Fatalf("Expected latest release to be compared with 1.0.0, got %+v", comparison)
+	}
+	if !approx(comparison.CrashRate, 0.1) || !approx(comparison.PreviousCrashRate, 0.05) || !approx(comparison.CrashRateChange, 0.05) {
+		t.Errorf("Wrong crash rate comparison: %+v", comparison)
+	}
+	if !approx(comparison.AnrRateChange, 0.1) {
+		t.Errorf("Wrong ANR rate comparison: %+v", comparison)
+	}
+
+	if health[1].Sessions != 0 || health[1].CrashFreeSessionRate != 1 || health[1].Comparison != nil {
+		t.Errorf("Expected release without sessions to be crash-free and not compared, got %+v", health[1])
+	}
+	if health[2].Comparison != nil {
+		t.Errorf("Expected oldest release not to be compared, got %+v", health[2].Comparison)
+	}
+}
+
+func TestReleaseAdoption(t *testing.T) {
+	totals := []model.StabilityEntity{
+		{Bucket: 0, Sessions: 10, Installations: 5},
+		{Bucket: 100, Sessions: 20, Installations: 8},
+	}
+	byRelease := []model.StabilityEntity{
+		{Bucket: 0, Group: "1.0.0", Sessions: 10, Installations: 5},
+		{Bucket: 100, Group: "1.1.0", Sessions: 15, Installations: 6},
+		{Bucket: 100, Group: "1.0.0", Sessions: 5, Installations: 3},
+	}
+
+	adoption := releaseAdoption(totals, byRelease)
+	if len(adoption) != 2 || len(adoption[0].Releases) != 1 || len(adoption[1].Releases) != 2 {
+		t.Fatalf("Wrong buckets: %+v", adoption)
+	}
+
+	newest := adoption[1].Releases[0]
+	if newest.Version != "1.1.0" || !approx(newest.SessionShare, 0.75) || !approx(newest.InstallationShare, 0.75) {
+		t.Errorf("Wrong adoption of 1.1.0: %+v", newest)
+	}
+	// An installation which updated within the bucket counts for both releases
+	if !approx(adoption[1].Releases[1].InstallationShare, 0.375) {
+		t.Errorf("Wrong installation share of 1.0.0: %+v", adoption[1].Releases[1])
+	}
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
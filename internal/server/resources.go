@@ -0,0 +1,202 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+// resourcePages are the pages of each resource type of the resources of a
+// session or installation. Memory usage keeps the 'cursor' param, so
+// clients from before the other resource types still page through it.
+type resourcePages struct {
+	memory  model.PageQuery
+	cpu     model.PageQuery
+	frames  model.PageQuery
+	battery model.PageQuery
+	network model.PageQuery
+	starts  model.PageQuery
+}
+
+// parseResourcePages reads the page of each resource type, which share the
+// time range and limit but have their own cursor
+func parseResourcePages(c echo.Context) (resourcePages, error) {
+	var pages resourcePages
+	cursors := map[string]*model.PageQuery{
+		"cursor":        &pages.memory,
+		"cpuCursor":     &pages.cpu,
+		"framesCursor":  &pages.frames,
+		"batteryCursor": &pages.battery,
+		"networkCursor": &pages.network,
+		"startsCursor":  &pages.starts,
+	}
+	for param, dest := range cursors {
+		page, err := parsePageQuery(c, param)
+		if err != nil {
+			return pages, err
+		}
+		*dest = page
+	}
+	return pages, nil
+}
+
+// resourcesDTO holds the resources of a session or installation, and the
+// page of each resource type by the same key
+type resourcesDTO struct {
+	resources map[string]any
+	pages     map[string]model.PageDTO
+}
+
+// getResources loads a page of each resource type of the session, or of the
+// installation, with the given id
+func (s *Server) getResources(id string, bySession bool, pages resourcePages) (resourcesDTO, error) {
+	getMemory, getCpu, getFrames, getBattery, getNetwork, getStarts := s.db.GetMemoryUsageByInstallationId, s.db.GetCpuUsageByInstallationId, s.db.GetFrameMetricsByInstallationId, s.db.GetBatteryStateByInstallationId, s.db.GetNetworkUsageByInstallationId, s.db.GetAppStartsByInstallationId
+	if bySession {
+		getMemory, getCpu, getFrames, getBattery, getNetwork, getStarts = s.db.GetMemoryUsageBySessionId, s.db.GetCpuUsageBySessionId, s.db.GetFrameMetricsBySessionId, s.db.GetBatteryStateBySessionId, s.db.GetNetworkUsageBySessionId, s.db.GetAppStartsBySessionId
+	}
+
+	dto := resourcesDTO{
+		resources: make(map[string]any),
+		pages:     make(map[string]model.PageDTO),
+	}
+
+	memory, next, err := getMemory(id, pages.memory)
+	if err != nil {
+		return dto, err
+	}
+	dto.resources["memoryUsage"] = mapDTOS(memory, memoryUsageDTO)
+	dto.pages["memoryUsage"] = pageDTO(pages.memory, next)
+
+	cpu, next, err := getCpu(id, pages.cpu)
+	if err != nil {
+		return dto, err
+	}
+	dto.resources["cpuUsage"] = mapDTOS(cpu, cpuUsageDTO)
+	dto.pages["cpuUsage"] = pageDTO(pages.cpu, next)
+
+	frames, next, err := getFrames(id, pages.frames)
+	if err != nil {
+		return dto, err
+	}
+	dto.resources["frameMetrics"] = mapDTOS(frames, frameMetricsDTO)
+	dto.pages["frameMetrics"] = pageDTO(pages.frames, next)
+
+	battery, next, err := getBattery(id, pages.battery)
+	if err != nil {
+		return dto, err
+	}
+	dto.resources["batteryState"] = mapDTOS(battery, batteryStateDTO)
+	dto.pages["batteryState"] = pageDTO(pages.battery, next)
+
+	network, next, err := getNetwork(id, pages.network)
+	if err != nil {
+		return dto, err
+	}
+	dto.resources["networkUsage"] = mapDTOS(network, networkUsageDTO)
+	dto.pages["networkUsage"] = pageDTO(pages.network, next)
+
+	starts, next, err := getStarts(id, pages.starts)
+	if err != nil {
+		return dto, err
+	}
+	dto.resources["appStarts"] = mapDTOS(starts, appStartDTO)
+	dto.pages["appStarts"] = pageDTO(pages.starts, next)
+
+	return dto, nil
+}
+
+func mapDTOS[E any, D any](entities []E, toDTO func(E) D) []D {
+	DTOS := make([]D, len(entities))
+	for i, ent := range entities {
+		DTOS[i] = toDTO(ent)
+	}
+	return DTOS
+}
+
+func memoryUsageDTO(ent model.MemoryUsageEntity) model.GetMemoryUsageDTO {
+	return model.GetMemoryUsageDTO{
+		Id:                 ent.Id,
+		SessionId:          ent.SessionId,
+		InstallationId:     ent.InstallationId,
+		AppId:              ent.AppId,
+		FreeMemory:         ent.FreeMemory,
+		UsedMemory:         ent.UsedMemory,
+		MaxMemory:          ent.MaxMemory,
+		TotalMemory:        ent.TotalMemory,
+		AvailableHeapSpace: ent.AvailableHeapSpace,
+		CreatedAt:          ent.CreatedAt,
+	}
+}
+
+func cpuUsageDTO(ent model.CpuUsageEntity) model.GetCpuUsageDTO {
+	return model.GetCpuUsageDTO{
+		Id:             ent.Id,
+		SessionId:      ent.SessionId,
+		InstallationId: ent.InstallationId,
+		AppId:          ent.AppId,
+		Usage:          ent.Usage,
+		UserTime:       ent.UserTime,
+		SystemTime:     ent.SystemTime,
+		Cores:          ent.Cores,
+		CreatedAt:      ent.CreatedAt,
+	}
+}
+
+func frameMetricsDTO(ent model.FrameMetricsEntity) model.GetFrameMetricsDTO {
+	return model.GetFrameMetricsDTO{
+		Id:             ent.Id,
+		SessionId:      ent.SessionId,
+		InstallationId: ent.InstallationId,
+		AppId:          ent.AppId,
+		Screen:         ent.Screen,
+		TotalFrames:    ent.TotalFrames,
+		JankyFrames:    ent.JankyFrames,
+		FrozenFrames:   ent.FrozenFrames,
+		CreatedAt:      ent.CreatedAt,
+	}
+}
+
+func batteryStateDTO(ent model.BatteryStateEntity) model.GetBatteryStateDTO {
+	return model.GetBatteryStateDTO{
+		Id:             ent.Id,
+		SessionId:      ent.SessionId,
+		InstallationId: ent.InstallationId,
+		AppId:          ent.AppId,
+		Level:          ent.Level,
+		Charging:       ent.Charging,
+		Temperature:    ent.Temperature,
+		ThermalState:   ent.ThermalState,
+		PowerSave:      ent.PowerSave,
+		CreatedAt:      ent.CreatedAt,
+	}
+}
+
+func networkUsageDTO(ent model.NetworkUsageEntity) model.GetNetworkUsageDTO {
+	return model.GetNetworkUsageDTO{
+		Id:             ent.Id,
+		SessionId:      ent.SessionId,
+		InstallationId: ent.InstallationId,
+		AppId:          ent.AppId,
+		ConnectionType: ent.ConnectionType,
+		Requests:       ent.Requests,
+		FailedRequests: ent.FailedRequests,
+		BytesSent:      ent.BytesSent,
+		BytesReceived:  ent.BytesReceived,
+		CreatedAt:      ent.CreatedAt,
+	}
+}
+
+func frameSummaryDTO(ent model.FrameSummaryEntity) model.FrameSummaryDTO {
+	dto := model.FrameSummaryDTO{
+		Screen:       ent.Screen,
+		Sessions:     ent.Sessions,
+		TotalFrames:  ent.TotalFrames,
+		JankyFrames:  ent.JankyFrames,
+		FrozenFrames: ent.FrozenFrames,
+	}
+	if ent.TotalFrames > 0 {
+		dto.JankyRate = float64(ent.JankyFrames) / float64(ent.TotalFrames)
+		dto.FrozenRate = float64(ent.FrozenFrames) / float64(ent.TotalFrames)
+	}
+	return dto
+}
@@ -3,20 +3,65 @@ package server
 import (
 	doc "ObservabilityServer"
 	"ObservabilityServer/internal/auth"
+	"ObservabilityServer/internal/mapping"
 	"ObservabilityServer/internal/model"
+	"ObservabilityServer/internal/queue"
+	"bytes"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"google.golang.org/protobuf/proto"
 )
 
+/**
+* @apiDefine Pagination
+* @apiQuery {number} [from] Only include records created at or after this timestamp
+* @apiQuery {number} [to] Only include records created at or before this timestamp
+* @apiQuery {number{1-1000}} [limit=100] Max number of records per page
+ */
+
+/**
+* @apiDefine ReleaseFilter
+* @apiQuery {String} [release] Only include data of sessions with this app version
+ */
+
+/**
+* @apiDefine ResourceCursors
+* @apiQuery {String} [cursor] 'nextCursor' of the previous page of memory usage
+* @apiQuery {String} [cpuCursor] 'nextCursor' of the previous page of CPU usage
+* @apiQuery {String} [framesCursor] 'nextCursor' of the previous page of frame metrics
+* @apiQuery {String} [batteryCursor] 'nextCursor' of the previous page of battery states
+* @apiQuery {String} [networkCursor] 'nextCursor' of the previous page of network usage
+* @apiQuery {String} [startsCursor] 'nextCursor' of the previous page of app starts
+ */
+
+/**
+* @apiDefine CompressedBody
+* @apiHeader {String} [content-encoding] Optional 'gzip' or 'zstd', if the body is compressed
+ */
+
+/**
+* @apiDefine ProtobufBody
+* @apiHeader {String} [content-type] 'application/json' or 'application/x-protobuf'.
+* Protobuf bodies use the messages defined in internal/pb/ingestion.proto
+ */
+
 /**
 * @apiDefine ApiKeyAuth
-* @apiHeader {String} authorization Api key prefixed with 'Bearer '
+* @apiHeader {String} authorization Api key prefixed with 'Bearer '. The key
+* needs the 'ingest' scope, or the ingest scope of the type of data sent
+* @apiHeader {String} [x-installation-id] Id of the installation sending the
+* request. Requests are limited per api key and per installation, and get
+* status 429 with the seconds to wait in 'Retry-After' once they exceed it
 * @apiHeaderExample {json} Auth-Example:
 * {
 *     "Authorization": "Bearer {your-api-key}"
@@ -26,6 +71,7 @@ import (
 func (s *Server) RegisterRoutes() http.Handler {
 	e := echo.New()
 	e.Validator = NewValidator()
+	e.Binder = NewBinder()
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORS())
@@ -42,7 +88,9 @@ func (s *Server) RegisterRoutes() http.Handler {
 	e.POST("/auth/sign-in", s.signInHandler)
 	e.POST("/auth/validate", s.validateSessionIdHandler, s.AppAuthMiddleware)
 
-	// APP v1 endpoints
+	// APP v1 endpoints. Every member of a team can read the data of its
+	// apps, routes which change it check the role of the member. Api keys
+	// with the 'read' scope can read the data of their app.
 	appV1 := e.Group("/app/v1", s.AppAuthMiddleware)
 
 	appV1.GET("/teams", s.getTeamsHandler)
@@ -52,26 +100,66 @@ func (s *Server) RegisterRoutes() http.Handler {
 
 	appV1.POST("/apps", s.createAppHandler)
 	appV1.GET("/apps/:id", s.getAppDataHandler)
+	appV1.GET("/apps/:id/keys", s.getKeysHandler)
 	appV1.POST("/apps/:id/keys", s.createKeyHandler)
-
-	appV1.GET("/installations/:id/resources", s.getInstallationMemoryUsageHandler)
+	appV1.DELETE("/apps/:id/keys/:keyId", s.revokeKeyHandler)
+	appV1.POST("/apps/:id/keys/:keyId/rotate", s.rotateKeyHandler)
+	appV1.GET("/apps/:id/issues", s.getAppIssuesHandler)
+	appV1.GET("/apps/:id/issues/:fingerprint/crashes", s.getIssueCrashesHandler)
+	appV1.GET("/apps/:id/issues/:fingerprint/anrs", s.getIssueAnrsHandler)
+	appV1.GET("/apps/:id/anrs/rates", s.getAnrRatesHandler)
+	appV1.GET("/apps/:id/stability", s.getStabilityHandler)
+	appV1.GET("/apps/:id/releases", s.getReleasesHandler)
+	appV1.GET("/apps/:id/releases/adoption", s.getReleaseAdoptionHandler)
+	appV1.GET("/apps/:id/releases/:version", s.getReleaseHandler)
+	appV1.POST("/apps/:id/mappings", s.uploadMappingHandler)
+	appV1.GET("/apps/:id/mappings", s.getMappingsHandler)
+	appV1.GET("/apps/:id/resources/memory/summary", s.getMemorySummaryHandler)
+	appV1.GET("/apps/:id/resources/memory/leaks", s.getMemoryLeaksHandler)
+	appV1.GET("/apps/:id/resources/frames", s.getFrameSummaryHandler)
+	appV1.GET("/apps/:id/resources/starts", s.getStartupSummaryHandler)
+	appV1.GET("/apps/:id/events/search", s.searchEventsHandler)
+	appV1.GET("/apps/:id/network", s.getNetworkEndpointsHandler)
+	appV1.GET("/apps/:id/network/endpoint", s.getNetworkEndpointHandler)
+	appV1.GET("/apps/:id/throttled", s.getThrottledRequestsHandler)
+	appV1.GET("/apps/:id/ingestion-settings", s.getIngestionSettingsHandler)
+	appV1.PUT("/apps/:id/ingestion-settings", s.updateIngestionSettingsHandler)
+
+	appV1.GET("/installations/:id/resources", s.getInstallationResourcesHandler)
 	appV1.GET("/installations/:id", s.getInstallationInfoHandler)
 
-	appV1.GET("/sessions/:id/resources", s.getSessionMemoryUsageHandler)
+	appV1.GET("/sessions/:id/resources", s.getSessionResourcesHandler)
 	appV1.GET("/sessions/:id/events", s.getSessionEventsHandler)
+	appV1.GET("/sessions/:id/network", s.getSessionNetworkRequestsHandler)
 	appV1.GET("/sessions/:id/traces", s.getSessionTracesHandler)
+	appV1.GET("/sessions/:id/traces/tree", s.getSessionTraceTreeHandler)
 	appV1.GET("/sessions/:id", s.getSessionInfoHandler)
 
-	// Api v1 endpoints
-	apiV1 := e.Group("/api/v1", s.APIKeyMiddleware)
-	apiV1.POST("/installations", s.createInstallationHandler)
-	apiV1.POST("/installations/:type", s.createTypedInstallationHandler)
-	apiV1.POST("/collection", s.createCollectionHandler)
-	apiV1.POST("/sessions", s.createSessionHandler)
-	apiV1.POST("/sessions/:id/crash", s.sessionCrashHandler)
-	apiV1.POST("/events", s.createEventHandler)
-	apiV1.POST("/traces", s.createTraceHandler)
-	apiV1.POST("/resources/memory", s.createMemoryUsageHandler)
+	// Api v1 endpoints. Api keys need the 'ingest' scope, or the scope of
+	// the type of data of the route. Requests are rate limited before their
+	// body is decompressed
+	apiV1 := e.Group("/api/v1", s.APIKeyMiddleware, s.RateLimitMiddleware, s.DecompressMiddleware)
+	apiV1.POST("/installations", s.createInstallationHandler, ingestScope("installations"))
+	apiV1.POST("/installations/:type", s.createTypedInstallationHandler, ingestScope("installations"))
+	apiV1.POST("/collection", s.createCollectionHandler, ingestScope("collection"))
+	apiV1.GET("/collection/:id", s.getCollectionStatusHandler, ingestScope("collection"))
+	apiV1.POST("/sessions", s.createSessionHandler, ingestScope("sessions"))
+	apiV1.POST("/sessions/:id/crash", s.sessionCrashHandler, ingestScope("crashes"))
+	apiV1.POST("/sessions/:id/anr", s.sessionAnrHandler, ingestScope("anrs"))
+	apiV1.POST("/events", s.createEventHandler, ingestScope("events"))
+	apiV1.POST("/traces", s.createTraceHandler, ingestScope("traces"))
+	apiV1.POST("/resources/memory", s.createMemoryUsageHandler, ingestScope("memory"))
+	apiV1.POST("/resources/cpu", s.createCpuUsageHandler, ingestScope("cpu"))
+	apiV1.POST("/resources/frames", s.createFrameMetricsHandler, ingestScope("frames"))
+	apiV1.POST("/resources/battery", s.createBatteryStateHandler, ingestScope("battery"))
+	apiV1.POST("/resources/network", s.createNetworkUsageHandler, ingestScope("network"))
+	apiV1.POST("/resources/starts", s.createAppStartsHandler, ingestScope("starts"))
+	apiV1.POST("/network/requests", s.createNetworkRequestsHandler, ingestScope("requests"))
+
+	// OpenTelemetry OTLP/HTTP receiver, served on the default exporter paths
+	otlpV1 := e.Group("/v1", s.APIKeyMiddleware, s.RateLimitMiddleware, s.DecompressMiddleware)
+	otlpV1.POST("/traces", s.exportOTLPTracesHandler, ingestScope("traces"))
+	otlpV1.POST("/logs", s.exportOTLPLogsHandler, ingestScope("events"))
 
 	return e
 }
@@ -87,7 +175,10 @@ func (s *Server) healthHandler(c echo.Context) error {
 * @apiDescription Get all teams available for the authenticated user
  */
 func (s *Server) getTeamsHandler(c echo.Context) error {
-	session := c.Get("session").(model.AuthSessionEntity)
+	session, ok := c.Get("session").(model.AuthSessionEntity)
+	if !ok {
+		return echo.NewHTTPError(http.StatusForbidden, "Api keys cannot access teams")
+	}
 
 	teams, err := s.db.GetTeamsForUser(session.UserId)
 	if err != nil {
@@ -134,7 +225,7 @@ func (s *Server) createTeamHandler(c echo.Context) error {
 	err = s.db.CreateTeamUserLink(model.NewTeamUserLinkData{
 		TeamId: id,
 		UserId: session.UserId,
-		Role:   "owner",
+		Role:   model.RoleOwner,
 	})
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err)
@@ -159,7 +250,10 @@ func (s *Server) getAppsHandler(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Team id must be a number")
 	}
 
-	session := c.Get("session").(model.AuthSessionEntity)
+	session, ok := c.Get("session").(model.AuthSessionEntity)
+	if !ok {
+		return echo.NewHTTPError(http.StatusForbidden, "Api keys cannot access teams")
+	}
 
 	if !s.db.ValidateTeamUserLink(teamId, session.UserId) {
 		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied")
@@ -185,6 +279,15 @@ func (s *Server) getAppsHandler(c echo.Context) error {
 	})
 }
 
+/**
+* @api {post} /app/v1/teams/:id/users Add user to team
+* @apiName CreateTeamUserLink
+* @apiGroup Teams
+* @apiDescription Add a user to the team with one of the roles 'owner',
+* 'admin', 'developer' or 'viewer'. Only owners and admins can add users,
+* and only owners can add other owners
+* @apiParam {number} id Unique id of the team to add the user to
+ */
 func (s *Server) createTeamUserLinkHandler(c echo.Context) error {
 	var linkDTO model.TeamUserLinkDTO
 	if err := c.Bind(&linkDTO); err != nil {
@@ -199,6 +302,14 @@ func (s *Server) createTeamUserLinkHandler(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
+	role, ok := s.teamRole(c, linkDTO.TeamId)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this team")
+	}
+	if !canGrantRole(role, linkDTO.Role) {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Role '%s' cannot add users as '%s'", role, linkDTO.Role))
+	}
+
 	err = s.db.CreateTeamUserLink(model.NewTeamUserLinkData{
 		TeamId: linkDTO.TeamId,
 		UserId: linkDTO.UserId,
@@ -302,7 +413,8 @@ func (s *Server) validateSessionIdHandler(c echo.Context) error {
 * @api {post} /app/v1/apps Create an app
 * @apiName CreateApp
 * @apiGroup Apps
-* @apiDescription Create a new app available to the team
+* @apiDescription Create a new app available to the team. Viewers of the
+* team cannot create apps
  */
 func (s *Server) createAppHandler(c echo.Context) error {
 	var appDTO model.CreateApplicationDTO
@@ -313,10 +425,13 @@ func (s *Server) createAppHandler(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
-	session := c.Get("session").(model.AuthSessionEntity)
-	if !s.db.ValidateTeamUserLink(appDTO.TeamId, session.UserId) {
+	role, ok := s.teamRole(c, appDTO.TeamId)
+	if !ok {
 		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this team")
 	}
+	if !roleAllows(role, permissionWrite) {
+		return echo.NewHTTPError(http.StatusForbidden, "Viewers cannot create apps")
+	}
 
 	id, err := s.db.CreateApplication(model.NewApplicationData{
 		Name:   appDTO.Name,
@@ -338,8 +453,13 @@ func (s *Server) createAppHandler(c echo.Context) error {
 * @api {get} /app/v1/apps/:id Get app
 * @apiName GetApp
 * @apiGroup Apps
-* @apiDescription Get info about an application
+* @apiDescription Get info about an application, with one page of its
+* installations and sessions each, ordered by creation time
 * @apiParam {number} id Unique id of the app to provide info about
+* @apiUse Pagination
+* @apiQuery {String} [installationCursor] 'nextCursor' of the previous page of installations
+* @apiQuery {String} [sessionCursor] 'nextCursor' of the previous page of sessions
+* @apiUse ReleaseFilter
  */
 func (s *Server) getAppDataHandler(c echo.Context) error {
 	appId, err := strconv.Atoi(c.Param("id"))
@@ -348,12 +468,20 @@ func (s *Server) getAppDataHandler(c echo.Context) error {
 	}
 
 	app, err := s.db.GetApplication(appId)
-	session := c.Get("session").(model.AuthSessionEntity)
-	if !s.db.ValidateTeamUserLink(app.TeamId, session.UserId) {
+	if !s.canReadApp(c, app) {
 		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
 	}
 
-	dataEntity, err := s.db.GetApplicationData(appId)
+	installationPage, err := parsePageQuery(c, "installationCursor")
+	if err != nil {
+		return err
+	}
+	sessionPage, err := parsePageQuery(c, "sessionCursor")
+	if err != nil {
+		return err
+	}
+
+	dataEntity, err := s.db.GetApplicationData(appId, installationPage, sessionPage)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err)
 	}
@@ -365,8 +493,10 @@ func (s *Server) getAppDataHandler(c echo.Context) error {
 	}
 
 	dataDTO := model.ApplicationDataDTO{
-		Installations: make([]model.InstallationDTO, len(dataEntity.Installations), len(dataEntity.Installations)),
-		Sessions:      make([]model.SessionDTO, len(dataEntity.Sessions), len(dataEntity.Sessions)),
+		Installations:     make([]model.InstallationDTO, len(dataEntity.Installations), len(dataEntity.Installations)),
+		Sessions:          make([]model.SessionDTO, len(dataEntity.Sessions), len(dataEntity.Sessions)),
+		InstallationsPage: pageDTO(installationPage, dataEntity.NextInstallationCursor),
+		SessionsPage:      pageDTO(sessionPage, dataEntity.NextSessionCursor),
 	}
 
 	for i, installation := range dataEntity.Installations {
@@ -381,6 +511,9 @@ func (s *Server) getAppDataHandler(c echo.Context) error {
 		dataDTO.Sessions[i] = model.SessionDTO{
 			Id:             session.Id,
 			InstallationId: session.InstallationId,
+			AppVersion:     session.AppVersion,
+			VersionCode:    session.VersionCode,
+			BuildType:      session.BuildType,
 			CreatedAt:      session.CreatedAt,
 			Crashed:        session.Crashed,
 		}
@@ -393,15 +526,70 @@ func (s *Server) getAppDataHandler(c echo.Context) error {
 	})
 }
 
+/**
+* @api {get} /app/v1/apps/:id/keys Get api keys
+* @apiName GetApiKeys
+* @apiGroup Keys
+* @apiDescription Get the metadata of the api keys of an app, newest first.
+* The keys themselves are only returned when they are created. The status
+* of a key is 'active', 'expired' or 'revoked'. Api keys cannot list keys
+* @apiParam {number} id Unique id of the app
+ */
+func (s *Server) getKeysHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	app, err := s.db.GetApplication(appId)
+	if err != nil {
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
+	}
+	if _, ok := s.teamRole(c, app.TeamId); !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+
+	keys, err := s.db.GetApiKeys(appId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	now := time.Now().UnixMilli()
+	keyDTOs := make([]model.GetApiKeyDTO, len(keys))
+	for i, key := range keys {
+		keyDTOs[i] = apiKeyDTO(key, now)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message": "Success",
+		"keys":    keyDTOs,
+	})
+}
+
 /**
 * @api {post} /app/v1/apps/:id/keys Create api key
 * @apiName CreateApiKey
 * @apiGroup Keys
-* @apiDescription Create a new api for the given app
+* @apiDescription Create a new api for the given app. Only owners and
+* admins of the team of the app can create api keys
 * @apiParam {number} id Unique id of the app to generate api key for
+* @apiBody {String} [name] Name describing where the key is used
+* @apiBody {String[]} [scopes=["ingest"]] What the key may be used for.
+* 'ingest' allows sending any data of the app, while 'ingest:<type>' only
+* allows one type of data: 'installations', 'sessions', 'collection',
+* 'crashes', 'anrs', 'events', 'traces', 'memory', 'cpu', 'frames',
+* 'battery', 'network', 'starts' or 'requests'. 'read' allows reading the
+* data of the app through the GET routes of /app/v1, except for teams and
+* keys. Keys embedded in apps should only have ingest scopes
+* @apiBody {Number} [expiresAt] Milliseconds since epoch after which the key
+* stops working. Keys without it never expire
  */
 func (s *Server) createKeyHandler(c echo.Context) error {
 	var apiKeyDTO model.NewApiKeyDTO
+	if err := c.Bind(&apiKeyDTO); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err)
@@ -410,26 +598,44 @@ func (s *Server) createKeyHandler(c echo.Context) error {
 	if err := c.Validate(&apiKeyDTO); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
+	now := time.Now().UnixMilli()
+	if apiKeyDTO.ExpiresAt != 0 && apiKeyDTO.ExpiresAt <= now {
+		return echo.NewHTTPError(http.StatusBadRequest, "'expiresAt' must be in the future")
+	}
 
-	session := c.Get("session").(model.AuthSessionEntity)
 	app, err := s.db.GetApplication(apiKeyDTO.AppId)
 	if err != nil {
 		log.Printf("Error getting application: %v\n", err)
 		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
 	}
 
-	if !s.db.ValidateTeamUserLink(app.TeamId, session.UserId) {
+	role, ok := s.teamRole(c, app.TeamId)
+	if !ok {
 		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
 	}
+	if !roleAllows(role, permissionManage) {
+		return echo.NewHTTPError(http.StatusForbidden, "Only owners and admins can create api keys")
+	}
 
 	key, err := auth.GenerateApiKey()
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err)
 	}
 
-	err = s.db.CreateApiKey(model.NewApiKeyData{
-		Key:   auth.HashApiKey(key),
-		AppId: apiKeyDTO.AppId,
+	scopes := apiKeyDTO.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultApiKeyScopes
+	}
+
+	session := c.Get("session").(model.AuthSessionEntity)
+	keyId, err := s.db.CreateApiKey(model.NewApiKeyData{
+		Key:       auth.HashApiKey(key),
+		AppId:     apiKeyDTO.AppId,
+		Name:      apiKeyDTO.Name,
+		Scopes:    scopes,
+		CreatedBy: session.UserId,
+		CreatedAt: now,
+		ExpiresAt: apiKeyDTO.ExpiresAt,
 	})
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
@@ -437,237 +643,1828 @@ func (s *Server) createKeyHandler(c echo.Context) error {
 		})
 	}
 
-	return c.JSON(http.StatusCreated, map[string]string{
+	return c.JSON(http.StatusCreated, map[string]any{
 		"message": "Api Key created",
+		"id":      keyId,
 		"key":     key,
 	})
 }
 
-func (s *Server) getInstallationMemoryUsageHandler(c echo.Context) error {
-	installationId := c.Param("id")
-	install, err := s.db.GetInstallation(installationId)
+/**
+* @api {delete} /app/v1/apps/:id/keys/:keyId Revoke api key
+* @apiName RevokeApiKey
+* @apiGroup Keys
+* @apiDescription Revoke an api key of an app, which stops working
+* immediately. Only owners and admins of the team of the app can revoke
+* api keys
+* @apiParam {number} id Unique id of the app
+* @apiParam {number} keyId Id of the key to revoke
+ */
+func (s *Server) revokeKeyHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		log.Printf("Getting installation failed: %v\n", err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Unknown installation id")
+		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
-	app, err := s.db.GetApplication(install.AppId)
+	keyId, err := strconv.Atoi(c.Param("keyId"))
 	if err != nil {
-		log.Printf("Getting app failed: %v\n", err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Unknown installation id")
-	}
-	session := c.Get("session").(model.AuthSessionEntity)
-	if !s.db.ValidateTeamUserLink(app.TeamId, session.UserId) {
-		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied")
+		return echo.NewHTTPError(http.StatusBadRequest, "Key id must be a number")
 	}
 
-	memoryEntities, err := s.db.GetMemoryUsageByInstallationId(install.Id)
+	app, err := s.db.GetApplication(appId)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err)
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
+	}
+	role, ok := s.teamRole(c, app.TeamId)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+	if !roleAllows(role, permissionManage) {
+		return echo.NewHTTPError(http.StatusForbidden, "Only owners and admins can revoke api keys")
 	}
 
-	memDTOS := make([]model.GetMemoryUsageDTO, len(memoryEntities), len(memoryEntities))
-	for i, ent := range memoryEntities {
-		memDTOS[i] = model.GetMemoryUsageDTO{
-			Id:                 ent.Id,
-			SessionId:          ent.SessionId,
-			InstallationId:     ent.InstallationId,
-			AppId:              ent.AppId,
-			FreeMemory:         ent.FreeMemory,
-			UsedMemory:         ent.UsedMemory,
-			MaxMemory:          ent.MaxMemory,
-			TotalMemory:        ent.TotalMemory,
-			AvailableHeapSpace: ent.AvailableHeapSpace,
-			CreatedAt:          ent.CreatedAt,
-		}
+	err = s.db.RevokeApiKey(appId, keyId, time.Now().UnixMilli())
+	if errors.Is(err, sql.ErrNoRows) {
+		return echo.NewHTTPError(http.StatusNotFound, "No unrevoked api key found with provided id")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
 	}
 
-	return c.JSON(http.StatusOK, map[string]any{
-		"message": "Success",
-		"resources": map[string]any{
-			"memoryUsage": memDTOS,
-		},
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Api Key revoked",
 	})
 }
 
-func (s *Server) getInstallationInfoHandler(c echo.Context) error {
-	installationId := c.Param("id")
-	install, err := s.db.GetInstallation(installationId)
+/**
+* @api {post} /app/v1/apps/:id/keys/:keyId/rotate Rotate api key
+* @apiName RotateApiKey
+* @apiGroup Keys
+* @apiDescription Replace an active api key of an app with a new key. Both
+* keys work during the grace period, after which the replaced key expires.
+* The new key keeps the name and scopes of the replaced key. Only owners
+* and admins of the team of the app can rotate api keys
+* @apiParam {number} id Unique id of the app
+* @apiParam {number} keyId Id of the key to replace
+* @apiBody {Number} [gracePeriod=86400] Seconds the replaced key keeps
+* working, at most 30 days
+* @apiBody {Number} [expiresAt] Milliseconds since epoch after which the new
+* key stops working. Keys without it never expire
+* @apiSuccess {Number} previousExpiry Milliseconds since epoch when the
+* replaced key stops working
+ */
+func (s *Server) rotateKeyHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		log.Printf("Getting installation failed: %v\n", err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Unknown installation id")
+		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
-	app, err := s.db.GetApplication(install.AppId)
+	keyId, err := strconv.Atoi(c.Param("keyId"))
 	if err != nil {
-		log.Printf("Getting app failed: %v\n", err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Unknown installation id")
+		return echo.NewHTTPError(http.StatusBadRequest, "Key id must be a number")
+	}
+
+	var rotateDTO model.RotateApiKeyDTO
+	if err := c.Bind(&rotateDTO); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := c.Validate(&rotateDTO); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	now := time.Now().UnixMilli()
+	if rotateDTO.ExpiresAt != 0 && rotateDTO.ExpiresAt <= now {
+		return echo.NewHTTPError(http.StatusBadRequest, "'expiresAt' must be in the future")
+	}
+
+	app, err := s.db.GetApplication(appId)
+	if err != nil {
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
+	}
+	role, ok := s.teamRole(c, app.TeamId)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
 	}
+	if !roleAllows(role, permissionManage) {
+		return echo.NewHTTPError(http.StatusForbidden, "Only owners and admins can rotate api keys")
+	}
+
+	key, err := auth.GenerateApiKey()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
 	session := c.Get("session").(model.AuthSessionEntity)
-	if !s.db.ValidateTeamUserLink(app.TeamId, session.UserId) {
-		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied")
+	newKeyId, previousExpiry, err := s.db.RotateApiKey(keyId, model.NewApiKeyData{
+		Key:       auth.HashApiKey(key),
+		AppId:     appId,
+		CreatedBy: session.UserId,
+		CreatedAt: now,
+		ExpiresAt: rotateDTO.ExpiresAt,
+	}, keyGraceEnd(now, rotateDTO.GracePeriod))
+	if errors.Is(err, sql.ErrNoRows) {
+		return echo.NewHTTPError(http.StatusNotFound, "No active api key found with provided id")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
 	}
 
-	return c.JSON(http.StatusOK, map[string]any{
-		"message": "Success",
-		"installation": model.InstallationDTO{
-			Id:        install.Id,
-			Type:      install.Type,
-			Data:      install.Data,
-			CreatedAt: install.CreatedAt,
-		},
+	return c.JSON(http.StatusCreated, map[string]any{
+		"message":        "Api Key rotated",
+		"id":             newKeyId,
+		"key":            key,
+		"previousExpiry": previousExpiry,
 	})
 }
 
-func (s *Server) getSessionMemoryUsageHandler(c echo.Context) error {
-	sessionId := c.Param("id")
-	session, err := s.db.GetSession(sessionId)
+/**
+* @api {get} /app/v1/apps/:id/issues Get issues
+* @apiName GetIssues
+* @apiGroup Issues
+* @apiDescription Get the crash and ANR issues of an app, ordered by the
+* number of sessions they affected. Crashes are grouped into one issue by
+* their exception class and top stack frames, ANRs by the top frames of their
+* main thread. Only occurrences within the time range are counted.
+* @apiParam {number} id Unique id of the app
+* @apiQuery {String="crash","anr"} [type] Only include issues of this type
+* @apiQuery {number} [from] Only count occurrences at or after this timestamp
+* @apiQuery {number} [to] Only count occurrences at or before this timestamp
+* @apiQuery {number{1-1000}} [limit=100] Max number of issues
+* @apiUse ReleaseFilter
+ */
+func (s *Server) getAppIssuesHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		log.Printf("Getting session failed: %v\n", err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Unknown session id")
+		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
-	app, err := s.db.GetApplication(session.AppId)
+
+	app, err := s.db.GetApplication(appId)
 	if err != nil {
-		log.Printf("Getting app failed: %v\n", err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Unknown session id")
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
 	}
-	authSession := c.Get("session").(model.AuthSessionEntity)
-	if !s.db.ValidateTeamUserLink(app.TeamId, authSession.UserId) {
+	if !s.canReadApp(c, app) {
 		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
 	}
 
-	memoryEntities, err := s.db.GetMemoryUsageBySessionId(session.Id)
+	page, err := parsePageQuery(c, "cursor")
+	if err != nil {
+		return err
+	}
+
+	issueType := c.QueryParam("type")
+	if issueType != "" && issueType != model.IssueTypeCrash && issueType != model.IssueTypeAnr {
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown issue type")
+	}
+
+	entities, err := s.db.GetIssuesByAppId(app.Id, issueType, page)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err)
 	}
 
-	memDTOS := make([]model.GetMemoryUsageDTO, len(memoryEntities), len(memoryEntities))
-	for i, ent := range memoryEntities {
-		memDTOS[i] = model.GetMemoryUsageDTO{
-			Id:                 ent.Id,
-			SessionId:          ent.SessionId,
-			InstallationId:     ent.InstallationId,
-			AppId:              ent.AppId,
-			FreeMemory:         ent.FreeMemory,
-			UsedMemory:         ent.UsedMemory,
-			MaxMemory:          ent.MaxMemory,
-			TotalMemory:        ent.TotalMemory,
-			AvailableHeapSpace: ent.AvailableHeapSpace,
-			CreatedAt:          ent.CreatedAt,
+	DTOS := make([]model.IssueDTO, len(entities))
+	for i, ent := range entities {
+		DTOS[i] = model.IssueDTO{
+			Fingerprint:    ent.Fingerprint,
+			Type:           ent.Type,
+			ExceptionClass: ent.ExceptionClass,
+			Message:        ent.Message,
+			FirstSeen:      ent.FirstSeen,
+			LastSeen:       ent.LastSeen,
+			Occurrences:    ent.Occurrences,
+			Sessions:       ent.Sessions,
 		}
 	}
 
 	return c.JSON(http.StatusOK, map[string]any{
 		"message": "Success",
-		"resources": map[string]any{
-			"memoryUsage": memDTOS,
-		},
+		"issues":  DTOS,
 	})
 }
 
-func (s *Server) getSessionEventsHandler(c echo.Context) error {
-	sessionId := c.Param("id")
-	session, err := s.db.GetSession(sessionId)
+/**
+* @api {get} /app/v1/apps/:id/issues/:fingerprint/crashes Get crashes of issue
+* @apiName GetIssueCrashes
+* @apiGroup Issues
+* @apiDescription Get the crashes grouped into an issue, ordered by time
+* @apiParam {number} id Unique id of the app
+* @apiParam {String} fingerprint Fingerprint of the issue
+* @apiUse Pagination
+* @apiQuery {String} [cursor] 'nextCursor' of the previous page
+* @apiUse ReleaseFilter
+ */
+func (s *Server) getIssueCrashesHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		log.Printf("Getting session failed: %v\n", err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Unknown session id")
+		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
-	app, err := s.db.GetApplication(session.AppId)
+
+	app, err := s.db.GetApplication(appId)
 	if err != nil {
-		log.Printf("Getting app failed: %v\n", err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Unknown session id")
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
 	}
-	authSession := c.Get("session").(model.AuthSessionEntity)
-	if !s.db.ValidateTeamUserLink(app.TeamId, authSession.UserId) {
+	if !s.canReadApp(c, app) {
 		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
 	}
 
-	entities, err := s.db.GetEventsBySessionId(session.Id)
+	page, err := parsePageQuery(c, "cursor")
+	if err != nil {
+		return err
+	}
+
+	entities, nextCursor, err := s.db.GetCrashesByIssue(app.Id, c.Param("fingerprint"), page)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err)
 	}
 
-	DTOS := make([]model.EventDTO, len(entities), len(entities))
+	mappings := s.newVersionMappings(app.Id)
+	for i, ent := range entities {
+		if ent.Deobfuscated {
+			continue
+		}
+		if m := mappings.get(ent.AppVersion); m != nil {
+			entities[i].ExceptionClass = m.Class(ent.ExceptionClass)
+			entities[i].Frames = m.Frames(ent.Frames)
+			entities[i].Deobfuscated = true
+		}
+	}
+
+	DTOS := make([]model.GetCrashDTO, len(entities))
 	for i, ent := range entities {
-		DTOS[i] = model.EventDTO{
+		DTOS[i] = model.GetCrashDTO{
 			Id:             ent.Id,
 			SessionId:      ent.SessionId,
-			Type:           ent.Type,
-			SerializedData: ent.SerializedData,
+			Fingerprint:    ent.Fingerprint,
+			ExceptionClass: ent.ExceptionClass,
+			Message:        ent.Message,
+			Frames:         ent.Frames,
+			ThreadName:     ent.ThreadName,
+			Screen:         ent.Screen,
+			AppVersion:     ent.AppVersion,
+			Deobfuscated:   ent.Deobfuscated,
 			CreatedAt:      ent.CreatedAt,
 		}
 	}
 
 	return c.JSON(http.StatusOK, map[string]any{
 		"message": "Success",
-		"events":  DTOS,
+		"crashes": DTOS,
+		"page":    pageDTO(page, nextCursor),
 	})
 }
 
-func (s *Server) getSessionTracesHandler(c echo.Context) error {
-	sessionId := c.Param("id")
-	session, err := s.db.GetSession(sessionId)
+/**
+* @api {get} /app/v1/apps/:id/issues/:fingerprint/anrs Get ANRs of issue
+* @apiName GetIssueAnrs
+* @apiGroup Issues
+* @apiDescription Get the ANRs grouped into an issue, ordered by time
+* @apiParam {number} id Unique id of the app
+* @apiParam {String} fingerprint Fingerprint of the issue
+* @apiUse Pagination
+* @apiQuery {String} [cursor] 'nextCursor' of the previous page
+* @apiUse ReleaseFilter
+ */
+func (s *Server) getIssueAnrsHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		log.Printf("Getting session failed: %v\n", err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Unknown session id")
+		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
-	app, err := s.db.GetApplication(session.AppId)
+
+	app, err := s.db.GetApplication(appId)
 	if err != nil {
-		log.Printf("Getting app failed: %v\n", err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Unknown session id")
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
 	}
-	authSession := c.Get("session").(model.AuthSessionEntity)
-	if !s.db.ValidateTeamUserLink(app.TeamId, authSession.UserId) {
+	if !s.canReadApp(c, app) {
 		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
 	}
 
-	entities, err := s.db.GetTracesBySessionId(session.Id)
+	page, err := parsePageQuery(c, "cursor")
+	if err != nil {
+		return err
+	}
+
+	entities, nextCursor, err := s.db.GetAnrsByIssue(app.Id, c.Param("fingerprint"), page)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err)
 	}
 
-	DTOS := make([]model.TraceDTO, len(entities), len(entities))
+	mappings := s.newVersionMappings(app.Id)
 	for i, ent := range entities {
-		DTOS[i] = model.TraceDTO{
-			TraceId:      ent.TraceId,
-			GroupId:      ent.GroupId,
-			SessionId:    ent.SessionId,
-			ParentId:     ent.ParentId,
-			Name:         ent.Name,
-			Status:       ent.Status,
-			ErrorMessage: ent.ErrorMessage,
-			StartedAt:    ent.StartedAt,
-			EndedAt:      ent.EndedAt,
-			HasEnded:     ent.HasEnded,
+		if ent.Deobfuscated {
+			continue
+		}
+		if m := mappings.get(ent.AppVersion); m != nil {
+			entities[i].MainThread = m.Frames(ent.MainThread)
+			entities[i].Threads = deobfuscateThreads(m, ent.Threads)
+			entities[i].Deobfuscated = true
+		}
+	}
+
+	DTOS := make([]model.GetAnrDTO, len(entities))
+	for i, ent := range entities {
+		DTOS[i] = model.GetAnrDTO{
+			Id:             ent.Id,
+			SessionId:      ent.SessionId,
+			InstallationId: ent.InstallationId,
+			Fingerprint:    ent.Fingerprint,
+			Duration:       ent.Duration,
+			MainThread:     ent.MainThread,
+			Threads:        ent.Threads,
+			Screen:         ent.Screen,
+			AppVersion:     ent.AppVersion,
+			Deobfuscated:   ent.Deobfuscated,
+			CreatedAt:      ent.CreatedAt,
 		}
 	}
 
 	return c.JSON(http.StatusOK, map[string]any{
 		"message": "Success",
-		"traces":  DTOS,
+		"anrs":    DTOS,
+		"page":    pageDTO(page, nextCursor),
 	})
 }
 
-func (s *Server) getSessionInfoHandler(c echo.Context) error {
-	sessionId := c.Param("id")
-	session, err := s.db.GetSession(sessionId)
+/**
+* @api {get} /app/v1/apps/:id/anrs/rates Get ANR rates
+* @apiName GetAnrRates
+* @apiGroup Issues
+* @apiDescription Get the share of sessions with at least one ANR per app
+* version, newest version first. Sessions that did not report an app
+* version are counted under the empty version.
+* @apiParam {number} id Unique id of the app
+* @apiQuery {number} [from] Only count sessions created at or after this timestamp
+* @apiQuery {number} [to] Only count sessions created at or before this timestamp
+* @apiUse ReleaseFilter
+ */
+func (s *Server) getAnrRatesHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		log.Printf("Getting session failed: %v\n", err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Unknown session id")
+		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
-	app, err := s.db.GetApplication(session.AppId)
+
+	app, err := s.db.GetApplication(appId)
 	if err != nil {
-		log.Printf("Getting app failed: %v\n", err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Unknown session id")
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
 	}
-	authSession := c.Get("session").(model.AuthSessionEntity)
-	if !s.db.ValidateTeamUserLink(app.TeamId, authSession.UserId) {
-		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied")
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
 	}
 
-	return c.JSON(http.StatusOK, map[string]any{
-		"message": "Success",
+	page, err := parsePageQuery(c, "cursor")
+	if err != nil {
+		return err
+	}
+
+	entities, err := s.db.GetAnrRates(app.Id, page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	DTOS := make([]model.AnrRateDTO, len(entities))
+	for i, ent := range entities {
+		DTOS[i] = model.AnrRateDTO{
+			AppVersion:  ent.AppVersion,
+			Sessions:    ent.Sessions,
+			AnrSessions: ent.AnrSessions,
+			Anrs:        ent.Anrs,
+		}
+		DTOS[i].Rate = rate(ent.AnrSessions, ent.Sessions)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message": "Success",
+		"rates":   DTOS,
+	})
+}
+
+/**
+* @api {get} /app/v1/apps/:id/stability Get stability
+* @apiName GetStability
+* @apiGroup Stability
+* @apiDescription Get the crash-free session and installation rates of an
+* app, in total and over time. An installation counts as crashed in a bucket
+* if any of its sessions in that bucket crashed. With 'groupBy' the metrics
+* are also broken down by an attribute of the installation data, with the
+* groups with the most sessions first.
+* @apiParam {number} id Unique id of the app
+* @apiQuery {number} [from] Only include sessions created at or after this timestamp
+* @apiQuery {number} [to] Only include sessions created at or before this timestamp
+* @apiQuery {String="hour","day","week","month"} [interval=day] Size of the buckets, aligned to UTC
+* @apiQuery {String} [groupBy] Installation data attribute to break down by, fx. 'sdkVersion', 'brand' or 'model'
+* @apiQuery {number{1-1000}} [limit=100] Max number of groups in the breakdown
+* @apiUse ReleaseFilter
+ */
+func (s *Server) getStabilityHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	app, err := s.db.GetApplication(appId)
+	if err != nil {
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+
+	page, err := parsePageQuery(c, "cursor")
+	if err != nil {
+		return err
+	}
+
+	interval, err := parseInterval(c)
+	if err != nil {
+		return err
+	}
+
+	groupBy := c.QueryParam("groupBy")
+	if groupBy != "" && !attributeKeyPattern.MatchString(groupBy) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Query param 'groupBy' is not a valid attribute")
+	}
+
+	query := model.StabilityQuery{From: page.From, To: page.To, Release: page.Release}
+
+	overall, err := s.db.GetStability(app.Id, query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	query.Interval = interval
+	series, err := s.db.GetStability(app.Id, query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	res := map[string]any{
+		"message":  "Success",
+		"interval": interval,
+		"overall":  stabilityDTO(model.StabilityEntity{}, false, false),
+		"series":   stabilityDTOS(series, true, false),
+	}
+	if len(overall) == 1 {
+		res["overall"] = stabilityDTO(overall[0], false, false)
+	}
+
+	if groupBy != "" {
+		query.Interval = ""
+		query.GroupBy = groupBy
+		groups, err := s.db.GetStability(app.Id, query)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+
+		query.Interval = interval
+		groupSeries, err := s.db.GetStability(app.Id, query)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+
+		breakdown := stabilityDTOS(groups[:min(len(groups), page.NormalizedLimit())], false, true)
+		index := make(map[string]int, len(breakdown))
+		for i, group := range breakdown {
+			index[*group.Value] = i
+		}
+		for _, ent := range groupSeries {
+			if i, ok := index[ent.Group]; ok {
+				breakdown[i].Series = append(breakdown[i].Series, stabilityDTO(ent, true, false))
+			}
+		}
+
+		res["groupBy"] = groupBy
+		res["breakdown"] = breakdown
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+/**
+* @api {get} /app/v1/apps/:id/releases Get release health
+* @apiName GetReleases
+* @apiGroup Releases
+* @apiDescription Get the releases of an app, newest first, with a health
+* summary of their sessions within the time range. Releases are created from
+* the app version of sessions. Each release is compared with the next older
+* release that has sessions in the time range.
+* @apiParam {number} id Unique id of the app
+* @apiQuery {number} [from] Only include sessions created at or after this timestamp
+* @apiQuery {number} [to] Only include sessions created at or before this timestamp
+ */
+func (s *Server) getReleasesHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	app, err := s.db.GetApplication(appId)
+	if err != nil {
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+
+	page, err := parsePageQuery(c, "cursor")
+	if err != nil {
+		return err
+	}
+	page.Release = ""
+
+	releases, err := s.getReleaseHealth(app.Id, page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message":  "Success",
+		"releases": releases,
+	})
+}
+
+/**
+* @api {get} /app/v1/apps/:id/releases/:version Get health of release
+* @apiName GetRelease
+* @apiGroup Releases
+* @apiDescription Get the health summary of a single release, compared
+* with the release before it
+* @apiParam {number} id Unique id of the app
+* @apiParam {String} version App version of the release
+* @apiQuery {number} [from] Only include sessions created at or after this timestamp
+* @apiQuery {number} [to] Only include sessions created at or before this timestamp
+ */
+func (s *Server) getReleaseHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	app, err := s.db.GetApplication(appId)
+	if err != nil {
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+
+	page, err := parsePageQuery(c, "cursor")
+	if err != nil {
+		return err
+	}
+	page.Release = ""
+
+	version := c.Param("version")
+	if _, err := s.db.GetRelease(app.Id, version); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound, "No release found with provided version")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	// The previous release is only known in the context of all releases
+	releases, err := s.getReleaseHealth(app.Id, page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	for _, release := range releases {
+		if release.Version == version {
+			return c.JSON(http.StatusOK, map[string]any{
+				"message": "Success",
+				"release": release,
+			})
+		}
+	}
+
+	return echo.NewHTTPError(http.StatusNotFound, "No release found with provided version")
+}
+
+/**
+* @api {get} /app/v1/apps/:id/releases/adoption Get release adoption
+* @apiName GetReleaseAdoption
+* @apiGroup Releases
+* @apiDescription Get the share of sessions and installations of each
+* release over time. Sessions without an app version are counted under the
+* empty version.
+* @apiParam {number} id Unique id of the app
+* @apiQuery {number} [from] Only include sessions created at or after this timestamp
+* @apiQuery {number} [to] Only include sessions created at or before this timestamp
+* @apiQuery {String="hour","day","week","month"} [interval=day] Size of the buckets, aligned to UTC
+ */
+func (s *Server) getReleaseAdoptionHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	app, err := s.db.GetApplication(appId)
+	if err != nil {
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+
+	page, err := parsePageQuery(c, "cursor")
+	if err != nil {
+		return err
+	}
+
+	interval, err := parseInterval(c)
+	if err != nil {
+		return err
+	}
+
+	query := model.StabilityQuery{From: page.From, To: page.To, Interval: interval}
+	totals, err := s.db.GetStability(app.Id, query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	query.ByRelease = true
+	byRelease, err := s.db.GetStability(app.Id, query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message":  "Success",
+		"interval": interval,
+		"adoption": releaseAdoption(totals, byRelease),
+	})
+}
+
+/**
+* @api {post} /app/v1/apps/:id/mappings Upload mapping
+* @apiName UploadMapping
+* @apiGroup Issues
+* @apiDescription Upload the R8 or ProGuard mapping of an app version as
+* 'multipart/form-data'. Crashes reporting that version are deobfuscated
+* with it, both when they are received and when crashes received before the
+* upload are queried. Uploading a mapping for the same version again replaces it.
+* Viewers of the team of the app cannot upload mappings.
+* @apiParam {number} id Unique id of the app
+* @apiBody {String} version Version of the app the mapping was built for
+* @apiBody {File} mapping The mapping.txt written by the minifier
+ */
+func (s *Server) uploadMappingHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	app, err := s.db.GetApplication(appId)
+	if err != nil {
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
+	}
+	role, ok := s.teamRole(c, app.TeamId)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+	if !roleAllows(role, permissionWrite) {
+		return echo.NewHTTPError(http.StatusForbidden, "Viewers cannot upload mappings")
+	}
+
+	// Leaves room for the other form fields and multipart headers
+	req := c.Request()
+	req.Body = http.MaxBytesReader(c.Response(), req.Body, s.maxMappingBytes+64*1024)
+
+	header, err := c.FormFile("mapping")
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Mapping exceeds %d bytes", s.maxMappingBytes))
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing mapping file")
+	}
+
+	version := c.FormValue("version")
+	if version == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing version")
+	}
+	if header.Size > s.maxMappingBytes {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Mapping exceeds %d bytes", s.maxMappingBytes))
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, s.maxMappingBytes+1))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if int64(len(content)) > s.maxMappingBytes {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Mapping exceeds %d bytes", s.maxMappingBytes))
+	}
+
+	if _, err := mapping.Parse(bytes.NewReader(content)); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid mapping: %v", err))
+	}
+
+	err = s.db.CreateMapping(model.NewMappingData{
+		AppId:     app.Id,
+		Version:   version,
+		Mapping:   content,
+		CreatedAt: time.Now().UnixMilli(),
+	})
+	if err != nil {
+		log.Printf("Creating mapping failed: %v\n", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Mapping could not be stored")
+	}
+
+	return c.JSON(http.StatusCreated, map[string]string{
+		"message": "Mapping uploaded",
+		"version": version,
+	})
+}
+
+/**
+* @api {get} /app/v1/apps/:id/mappings Get mappings
+* @apiName GetMappings
+* @apiGroup Issues
+* @apiDescription Get the app versions a mapping was uploaded for, newest first
+* @apiParam {number} id Unique id of the app
+ */
+func (s *Server) getMappingsHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	app, err := s.db.GetApplication(appId)
+	if err != nil {
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+
+	entities, err := s.db.GetMappings(app.Id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	DTOS := make([]model.MappingDTO, len(entities))
+	for i, ent := range entities {
+		DTOS[i] = model.MappingDTO{
+			Version:   ent.Version,
+			Size:      ent.Size,
+			CreatedAt: ent.CreatedAt,
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message":  "Success",
+		"mappings": DTOS,
+	})
+}
+
+/**
+* @api {get} /app/v1/apps/:id/resources/memory/summary Get memory summary
+* @apiName GetMemorySummary
+* @apiGroup Resources
+* @apiDescription Get the p50, p90 and p99 of the used memory of an app, in
+* bytes and as a share of the max memory, in total and over time. The metrics
+* are also broken down by an attribute of the installation data, with the
+* groups with the most samples first.
+* @apiParam {number} id Unique id of the app
+* @apiQuery {number} [from] Only include samples created at or after this timestamp
+* @apiQuery {number} [to] Only include samples created at or before this timestamp
+* @apiQuery {String="hour","day","week","month"} [interval=day] Size of the buckets, aligned to UTC
+* @apiQuery {String} [groupBy=model] Installation data attribute to break down by, fx. 'model' or 'sdkVersion'
+* @apiQuery {number{1-1000}} [limit=100] Max number of groups in the breakdown
+* @apiUse ReleaseFilter
+ */
+func (s *Server) getMemorySummaryHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	app, err := s.db.GetApplication(appId)
+	if err != nil {
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+
+	page, err := parsePageQuery(c, "cursor")
+	if err != nil {
+		return err
+	}
+
+	interval, err := parseInterval(c)
+	if err != nil {
+		return err
+	}
+
+	groupBy := c.QueryParam("groupBy")
+	if groupBy == "" {
+		groupBy = "model"
+	} else if !attributeKeyPattern.MatchString(groupBy) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Query param 'groupBy' is not a valid attribute")
+	}
+
+	query := model.MemorySummaryQuery{From: page.From, To: page.To, Release: page.Release}
+
+	overall, err := s.db.GetMemorySummary(app.Id, query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	query.Interval = interval
+	series, err := s.db.GetMemorySummary(app.Id, query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	query.Interval = ""
+	query.GroupBy = groupBy
+	groups, err := s.db.GetMemorySummary(app.Id, query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	query.Interval = interval
+	groupSeries, err := s.db.GetMemorySummary(app.Id, query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	breakdown := memorySummaryDTOS(groups[:min(len(groups), page.NormalizedLimit())], false, true)
+	index := make(map[string]int, len(breakdown))
+	for i, group := range breakdown {
+		index[*group.Value] = i
+	}
+	for _, ent := range groupSeries {
+		if i, ok := index[ent.Group]; ok {
+			breakdown[i].Series = append(breakdown[i].Series, memorySummaryDTO(ent, true, false))
+		}
+	}
+
+	res := map[string]any{
+		"message":   "Success",
+		"interval":  interval,
+		"overall":   memorySummaryDTO(model.MemoryPercentilesEntity{}, false, false),
+		"series":    memorySummaryDTOS(series, true, false),
+		"groupBy":   groupBy,
+		"breakdown": breakdown,
+	}
+	if len(overall) == 1 {
+		res["overall"] = memorySummaryDTO(overall[0], false, false)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+/**
+* @api {get} /app/v1/apps/:id/resources/memory/leaks Get memory leaks
+* @apiName GetMemoryLeaks
+* @apiGroup Resources
+* @apiDescription Get the sessions of an app whose used memory grew between
+* their first and last sample, with the most growth relative to their max
+* memory first. A session is suspected of leaking if at least 80% of its
+* samples used more memory than the sample before, and it grew by at least 5%
+* of its max memory. 'timeToOom' estimates the milliseconds until the used
+* memory reaches the max memory, and an OutOfMemoryError is likely.
+* @apiParam {number} id Unique id of the app
+* @apiQuery {number} [from] Only include samples created at or after this timestamp
+* @apiQuery {number} [to] Only include samples created at or before this timestamp
+* @apiQuery {number} [minSamples=5] Min number of samples of a session
+* @apiQuery {number{1-1000}} [limit=100] Max number of sessions
+* @apiUse ReleaseFilter
+ */
+func (s *Server) getMemoryLeaksHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	app, err := s.db.GetApplication(appId)
+	if err != nil {
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+
+	page, err := parsePageQuery(c, "cursor")
+	if err != nil {
+		return err
+	}
+
+	minSamples := 5
+	if value := c.QueryParam("minSamples"); value != "" {
+		minSamples, err = strconv.Atoi(value)
+		if err != nil || minSamples < 2 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Query param 'minSamples' must be at least 2")
+		}
+	}
+
+	query := model.MemorySummaryQuery{From: page.From, To: page.To, Release: page.Release}
+	trends, err := s.db.GetMemoryTrends(app.Id, query, minSamples, page.NormalizedLimit())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	DTOS := make([]model.MemoryLeakDTO, len(trends))
+	suspected := 0
+	for i, ent := range trends {
+		DTOS[i] = memoryLeakDTO(ent)
+		if DTOS[i].LeakSuspected {
+			suspected++
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message":   "Success",
+		"suspected": suspected,
+		"sessions":  DTOS,
+	})
+}
+
+/**
+* @api {get} /app/v1/apps/:id/resources/frames Get frame summary
+* @apiName GetFrameSummary
+* @apiGroup Resources
+* @apiDescription Get the janky and frozen frames per screen of an app, with
+* the screens with the most janky and frozen frames first.
+* @apiParam {number} id Unique id of the app
+* @apiQuery {number} [from] Only include frame metrics created at or after this timestamp
+* @apiQuery {number} [to] Only include frame metrics created at or before this timestamp
+* @apiQuery {number{1-1000}} [limit=100] Max number of screens
+* @apiUse ReleaseFilter
+ */
+func (s *Server) getFrameSummaryHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	app, err := s.db.GetApplication(appId)
+	if err != nil {
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+
+	page, err := parsePageQuery(c, "cursor")
+	if err != nil {
+		return err
+	}
+
+	screens, err := s.db.GetFrameSummary(app.Id, page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message": "Success",
+		"screens": mapDTOS(screens, frameSummaryDTO),
+	})
+}
+
+/**
+* @api {get} /app/v1/apps/:id/resources/starts Get startup summary
+* @apiName GetStartupSummary
+* @apiGroup Resources
+* @apiDescription Get the p50, p90 and p99 of the time it takes an app to
+* start, in milliseconds, in total and over time. Cold starts are also broken
+* down into the time from process start until Application.onCreate, and from
+* Application.onCreate until the first frame. The metrics are also broken
+* down by app version, or by an attribute of the installation data, with the
+* groups with the most starts first.
+* @apiParam {number} id Unique id of the app
+* @apiQuery {String="cold","warm","hot"} [type=cold] Type of starts to include
+* @apiQuery {number} [from] Only include starts at or after this timestamp
+* @apiQuery {number} [to] Only include starts at or before this timestamp
+* @apiQuery {String="hour","day","week","month"} [interval=day] Size of the buckets, aligned to UTC
+* @apiQuery {String} [groupBy=appVersion] 'appVersion', or an installation data attribute to break down by, fx. 'model'
+* @apiQuery {number{1-1000}} [limit=100] Max number of groups in the breakdown
+* @apiUse ReleaseFilter
+ */
+func (s *Server) getStartupSummaryHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	app, err := s.db.GetApplication(appId)
+	if err != nil {
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+
+	page, err := parsePageQuery(c, "cursor")
+	if err != nil {
+		return err
+	}
+
+	interval, err := parseInterval(c)
+	if err != nil {
+		return err
+	}
+
+	startType := c.QueryParam("type")
+	switch startType {
+	case "":
+		startType = model.StartTypeCold
+	case model.StartTypeCold, model.StartTypeWarm, model.StartTypeHot:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "Query param 'type' must be one of cold, warm or hot")
+	}
+
+	groupBy := c.QueryParam("groupBy")
+	if groupBy == "" {
+		groupBy = model.StartupGroupByAppVersion
+	} else if !attributeKeyPattern.MatchString(groupBy) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Query param 'groupBy' is not a valid attribute")
+	}
+
+	query := model.StartupQuery{From: page.From, To: page.To, Release: page.Release, StartType: startType}
+
+	overall, err := s.db.GetStartupSummary(app.Id, query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	query.Interval = interval
+	series, err := s.db.GetStartupSummary(app.Id, query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	query.Interval = ""
+	query.GroupBy = groupBy
+	groups, err := s.db.GetStartupSummary(app.Id, query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	res := map[string]any{
+		"message":   "Success",
+		"type":      startType,
+		"interval":  interval,
+		"overall":   startupSummaryDTO(model.StartupPercentilesEntity{}, startType, false, false),
+		"series":    startupSummaryDTOS(series, startType, true, false),
+		"groupBy":   groupBy,
+		"breakdown": startupSummaryDTOS(groups[:min(len(groups), page.NormalizedLimit())], startType, false, true),
+	}
+	if len(overall) == 1 {
+		res["overall"] = startupSummaryDTO(overall[0], startType, false, false)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+/**
+* @api {get} /app/v1/apps/:id/events/search Search events
+* @apiName SearchEvents
+* @apiGroup Event
+* @apiDescription Search the events of an app across its sessions by type
+* and attributes. The query is one or more predicates joined by AND, fx.
+* 'screen=Checkout AND button=pay'. Values can be quoted to include spaces,
+* which also makes them match only strings. Unquoted values like '12' or
+* 'true' match both strings and numbers or booleans.
+* @apiParam {number} id Unique id of the app
+* @apiQuery {String} [type] Only include events of this type
+* @apiQuery {String} [q] Attribute predicates the events must match
+* @apiUse Pagination
+* @apiQuery {String} [cursor] 'nextCursor' of the previous page
+* @apiUse ReleaseFilter
+ */
+func (s *Server) searchEventsHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	app, err := s.db.GetApplication(appId)
+	if err != nil {
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+
+	page, err := parsePageQuery(c, "cursor")
+	if err != nil {
+		return err
+	}
+
+	predicates, err := parseAttributeQuery(c.QueryParam("q"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query param 'q' is invalid: %v", err))
+	}
+
+	search := model.EventSearchQuery{
+		Type:       c.QueryParam("type"),
+		Predicates: predicates,
+	}
+	entities, nextCursor, err := s.db.SearchEvents(app.Id, search, page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message": "Success",
+		"events":  mapDTOS(entities, eventDTO),
+		"page":    pageDTO(page, nextCursor),
+	})
+}
+
+/**
+* @api {get} /app/v1/apps/:id/network Get network endpoints
+* @apiName GetNetworkEndpoints
+* @apiGroup Network
+* @apiDescription Get the latency and error rate of the HTTP calls of an app,
+* in total and per endpoint, with the most called endpoints first. An
+* endpoint is a method and url template. Calls which failed, or got a 4xx or
+* 5xx response, count as errors. Durations are in milliseconds.
+* @apiParam {number} id Unique id of the app
+* @apiQuery {number} [from] Only include calls started at or after this timestamp
+* @apiQuery {number} [to] Only include calls started at or before this timestamp
+* @apiQuery {String} [host] Only include calls to this host
+* @apiQuery {String} [method] Only include calls with this method
+* @apiQuery {number{1-1000}} [limit=100] Max number of endpoints
+* @apiUse ReleaseFilter
+ */
+func (s *Server) getNetworkEndpointsHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	app, err := s.db.GetApplication(appId)
+	if err != nil {
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+
+	page, err := parsePageQuery(c, "cursor")
+	if err != nil {
+		return err
+	}
+
+	query := model.NetworkQuery{
+		From:    page.From,
+		To:      page.To,
+		Release: page.Release,
+		Host:    c.QueryParam("host"),
+		Method:  c.QueryParam("method"),
+	}
+
+	endpoints, err := s.db.GetNetworkEndpoints(app.Id, query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	// The totals include every endpoint, not only those within the limit
+	requests, errors := 0, 0
+	for _, ent := range endpoints {
+		requests += ent.Requests
+		errors += ent.Errors
+	}
+	if limit := page.NormalizedLimit(); len(endpoints) > limit {
+		endpoints = endpoints[:limit]
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message":   "Success",
+		"requests":  requests,
+		"errors":    errors,
+		"errorRate": rate(errors, requests),
+		"endpoints": mapDTOS(endpoints, func(ent model.NetworkEndpointEntity) model.NetworkEndpointDTO {
+			return networkEndpointDTO(ent, false, true)
+		}),
+	})
+}
+
+/**
+* @api {get} /app/v1/apps/:id/network/endpoint Get network endpoint
+* @apiName GetNetworkEndpoint
+* @apiGroup Network
+* @apiDescription Get the latency and error rate of the HTTP calls to a
+* single endpoint of an app, in total and over time. Durations are in
+* milliseconds.
+* @apiParam {number} id Unique id of the app
+* @apiQuery {String} method Method of the endpoint
+* @apiQuery {String} urlTemplate Url template of the endpoint
+* @apiQuery {number} [from] Only include calls started at or after this timestamp
+* @apiQuery {number} [to] Only include calls started at or before this timestamp
+* @apiQuery {String="hour","day","week","month"} [interval=day] Size of the buckets, aligned to UTC
+* @apiUse ReleaseFilter
+ */
+func (s *Server) getNetworkEndpointHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	app, err := s.db.GetApplication(appId)
+	if err != nil {
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+
+	page, err := parsePageQuery(c, "cursor")
+	if err != nil {
+		return err
+	}
+
+	interval, err := parseInterval(c)
+	if err != nil {
+		return err
+	}
+
+	query := model.NetworkQuery{
+		From:        page.From,
+		To:          page.To,
+		Release:     page.Release,
+		Method:      c.QueryParam("method"),
+		UrlTemplate: c.QueryParam("urlTemplate"),
+	}
+	if query.Method == "" || query.UrlTemplate == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Query params 'method' and 'urlTemplate' are required")
+	}
+
+	overall, err := s.db.GetNetworkEndpoints(app.Id, query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if len(overall) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "No calls found to this endpoint")
+	}
+
+	query.Interval = interval
+	series, err := s.db.GetNetworkEndpoints(app.Id, query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message":  "Success",
+		"interval": interval,
+		"endpoint": networkEndpointDTO(overall[0], false, true),
+		"series": mapDTOS(series, func(ent model.NetworkEndpointEntity) model.NetworkEndpointDTO {
+			return networkEndpointDTO(ent, true, false)
+		}),
+	})
+}
+
+/**
+* @api {get} /app/v1/apps/:id/throttled Get throttled requests
+* @apiName GetThrottledRequests
+* @apiGroup Apps
+* @apiDescription Get the number of ingestion requests of an app which were
+* rejected with status 429, in total and over time, by whether the limit of
+* the api key or of the installation was exceeded. Requests are counted in
+* hourly buckets, which are written every few seconds.
+* @apiParam {number} id Unique id of the app
+* @apiQuery {number} [from] Only include hours starting at or after this timestamp
+* @apiQuery {number} [to] Only include hours starting at or before this timestamp
+* @apiQuery {String="hour","day","week","month"} [interval=day] Size of the buckets, aligned to UTC
+ */
+func (s *Server) getThrottledRequestsHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	app, err := s.db.GetApplication(appId)
+	if err != nil {
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+
+	page, err := parsePageQuery(c, "cursor")
+	if err != nil {
+		return err
+	}
+
+	interval, err := parseInterval(c)
+	if err != nil {
+		return err
+	}
+
+	query := model.ThrottledRequestsQuery{From: page.From, To: page.To}
+
+	overall, err := s.db.GetThrottledRequests(app.Id, query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	query.Interval = interval
+	series, err := s.db.GetThrottledRequests(app.Id, query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	res := map[string]any{
+		"message":  "Success",
+		"interval": interval,
+		"overall":  model.ThrottledRequestsDTO{},
+		"series":   throttledRequestsDTOS(series),
+	}
+	if overallDTOS := throttledRequestsDTOS(overall); len(overallDTOS) == 1 {
+		res["overall"] = overallDTOS[0]
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+/**
+* @api {get} /app/v1/apps/:id/ingestion-settings Get ingestion settings
+* @apiName GetIngestionSettings
+* @apiGroup Apps
+* @apiDescription Get the monthly quotas and sampling rules of an app, and
+* the events and traces ingested this month. 'usage' counts the records
+* stored, dropped by sampling and dropped for exceeding the quota, and how
+* many will be stored by the end of the month at the rate so far. Months
+* start at midnight UTC.
+* @apiParam {number} id Unique id of the app
+ */
+func (s *Server) getIngestionSettingsHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	app, err := s.db.GetApplication(appId)
+	if err != nil {
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+
+	now := time.Now()
+	settings, err := s.ingestionSettings(app.Id, now)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	usage, err := s.db.GetIngestionUsage(app.Id, usageMonth(now))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message":  "Success",
+		"settings": ingestionSettingsDTO(settings),
+		"usage":    ingestionUsageDTO(usage, settings, now),
+	})
+}
+
+/**
+* @api {put} /app/v1/apps/:id/ingestion-settings Update ingestion settings
+* @apiName UpdateIngestionSettings
+* @apiGroup Apps
+* @apiDescription Replace the monthly quotas and sampling rules of an app.
+* Once a quota is used up, further events or traces are dropped until the
+* next month. Sampling keeps either all events and traces of a session or
+* none of them. The first rule matching a session decides the fraction of
+* sessions kept, and sessions matching no rule are kept. Crashes, ANRs,
+* sessions and resources are always stored. Only owners and admins of the
+* team of the app can change the settings, which apply to every replica
+* within 30 seconds
+* @apiParam {number} id Unique id of the app
+* @apiBody {Number} [monthlyEventQuota=0] Max events stored per month, 0 is unlimited
+* @apiBody {Number} [monthlyTraceQuota=0] Max traces stored per month, 0 is unlimited
+* @apiBody {Object[]} [samplingRules] Rules like '[{"crashed": true, "rate": 1}, {"rate": 0.1}]'
+* @apiBody {Boolean} [samplingRules.crashed] Only match sessions which crashed, or which did not
+* @apiBody {String} [samplingRules.appVersion] Only match sessions of this app version
+* @apiBody {Number{0-1}} samplingRules.rate Fraction of the matching sessions to keep
+ */
+func (s *Server) updateIngestionSettingsHandler(c echo.Context) error {
+	appId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	var dto model.IngestionSettingsDTO
+	if err := c.Bind(&dto); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := c.Validate(&dto); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	app, err := s.db.GetApplication(appId)
+	if err != nil {
+		log.Printf("Error getting application: %v\n", err)
+		return echo.NewHTTPError(http.StatusNotFound, "No application found with provided id")
+	}
+
+	role, ok := s.teamRole(c, app.TeamId)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+	if !roleAllows(role, permissionManage) {
+		return echo.NewHTTPError(http.StatusForbidden, "Only owners and admins can change ingestion settings")
+	}
+
+	session := c.Get("session").(model.AuthSessionEntity)
+	err = s.db.SetIngestionSettings(model.IngestionSettingsData{
+		AppId:             app.Id,
+		MonthlyEventQuota: dto.MonthlyEventQuota,
+		MonthlyTraceQuota: dto.MonthlyTraceQuota,
+		SamplingRules:     dto.SamplingRules,
+		UpdatedBy:         session.UserId,
+		UpdatedAt:         time.Now().UnixMilli(),
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Ingestion settings could not be saved: %v", err))
+	}
+	s.settingsCache.Invalidate(app.Id)
+
+	return s.getIngestionSettingsHandler(c)
+}
+
+/**
+* @api {get} /app/v1/installations/:id/resources Get installation resources
+* @apiName GetInstallationResources
+* @apiGroup Resources
+* @apiDescription Get the memory usage, CPU usage, frame metrics, battery
+* state, network usage and app starts of an installation. Each resource type
+* is paged on its own, and 'page' is the page of the memory usage.
+* @apiParam {String} id Unique id of the installation
+* @apiUse Pagination
+* @apiUse ResourceCursors
+ */
+func (s *Server) getInstallationResourcesHandler(c echo.Context) error {
+	installationId := c.Param("id")
+	install, err := s.db.GetInstallation(installationId)
+	if err != nil {
+		log.Printf("Getting installation failed: %v\n", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown installation id")
+	}
+	app, err := s.db.GetApplication(install.AppId)
+	if err != nil {
+		log.Printf("Getting app failed: %v\n", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown installation id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied")
+	}
+
+	pages, err := parseResourcePages(c)
+	if err != nil {
+		return err
+	}
+
+	resources, err := s.getResources(install.Id, false, pages)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message":   "Success",
+		"resources": resources.resources,
+		"page":      resources.pages["memoryUsage"],
+		"pages":     resources.pages,
+	})
+}
+
+func (s *Server) getInstallationInfoHandler(c echo.Context) error {
+	installationId := c.Param("id")
+	install, err := s.db.GetInstallation(installationId)
+	if err != nil {
+		log.Printf("Getting installation failed: %v\n", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown installation id")
+	}
+	app, err := s.db.GetApplication(install.AppId)
+	if err != nil {
+		log.Printf("Getting app failed: %v\n", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown installation id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied")
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message": "Success",
+		"installation": model.InstallationDTO{
+			Id:        install.Id,
+			Type:      install.Type,
+			Data:      install.Data,
+			CreatedAt: install.CreatedAt,
+		},
+	})
+}
+
+/**
+* @api {get} /app/v1/sessions/:id/resources Get session resources
+* @apiName GetSessionResources
+* @apiGroup Resources
+* @apiDescription Get the memory usage, CPU usage, frame metrics, battery
+* state, network usage and app starts of a session. Each resource type is
+* paged on its own, and 'page' is the page of the memory usage.
+* @apiParam {String} id Unique id of the session
+* @apiUse Pagination
+* @apiUse ResourceCursors
+ */
+func (s *Server) getSessionResourcesHandler(c echo.Context) error {
+	sessionId := c.Param("id")
+	session, err := s.db.GetSession(sessionId)
+	if err != nil {
+		log.Printf("Getting session failed: %v\n", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown session id")
+	}
+	app, err := s.db.GetApplication(session.AppId)
+	if err != nil {
+		log.Printf("Getting app failed: %v\n", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown session id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+
+	pages, err := parseResourcePages(c)
+	if err != nil {
+		return err
+	}
+
+	resources, err := s.getResources(session.Id, true, pages)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message":   "Success",
+		"resources": resources.resources,
+		"page":      resources.pages["memoryUsage"],
+		"pages":     resources.pages,
+	})
+}
+
+func (s *Server) getSessionEventsHandler(c echo.Context) error {
+	sessionId := c.Param("id")
+	session, err := s.db.GetSession(sessionId)
+	if err != nil {
+		log.Printf("Getting session failed: %v\n", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown session id")
+	}
+	app, err := s.db.GetApplication(session.AppId)
+	if err != nil {
+		log.Printf("Getting app failed: %v\n", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown session id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+
+	page, err := parsePageQuery(c, "cursor")
+	if err != nil {
+		return err
+	}
+
+	entities, nextCursor, err := s.db.GetEventsBySessionId(session.Id, page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message": "Success",
+		"events":  mapDTOS(entities, eventDTO),
+		"page":    pageDTO(page, nextCursor),
+	})
+}
+
+/**
+* @api {get} /app/v1/sessions/:id/network Get session network requests
+* @apiName GetSessionNetworkRequests
+* @apiGroup Network
+* @apiDescription Get the HTTP calls of a session, in the order they were
+* started. With 'traceId' only the calls made by that trace are returned,
+* without pagination.
+* @apiParam {String} id Unique id of the session
+* @apiQuery {String} [traceId] Only include the calls linked to this trace
+* @apiUse Pagination
+* @apiQuery {String} [cursor] 'nextCursor' of the previous page
+ */
+func (s *Server) getSessionNetworkRequestsHandler(c echo.Context) error {
+	sessionId := c.Param("id")
+	session, err := s.db.GetSession(sessionId)
+	if err != nil {
+		log.Printf("Getting session failed: %v\n", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown session id")
+	}
+	app, err := s.db.GetApplication(session.AppId)
+	if err != nil {
+		log.Printf("Getting app failed: %v\n", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown session id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+
+	if traceId := c.QueryParam("traceId"); traceId != "" {
+		entities, err := s.db.GetNetworkRequestsByTraceId(traceId)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+
+		DTOS := make([]model.NetworkRequestDTO, 0, len(entities))
+		for _, ent := range entities {
+			if ent.SessionId == session.Id {
+				DTOS = append(DTOS, networkRequestDTO(ent))
+			}
+		}
+
+		return c.JSON(http.StatusOK, map[string]any{
+			"message":         "Success",
+			"networkRequests": DTOS,
+		})
+	}
+
+	page, err := parsePageQuery(c, "cursor")
+	if err != nil {
+		return err
+	}
+
+	entities, nextCursor, err := s.db.GetNetworkRequestsBySessionId(session.Id, page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message":         "Success",
+		"networkRequests": mapDTOS(entities, networkRequestDTO),
+		"page":            pageDTO(page, nextCursor),
+	})
+}
+
+// getSessionTracesHandler returns a page of the traces of a session. With
+// 'format=otlp' all traces in the time range are exported as OTLP/JSON, or
+// as OTLP protobuf if the request accepts 'application/x-protobuf', which
+// can be imported into Jaeger or Tempo. An export has no next cursor, so
+// 'limit' and 'cursor' are rejected for it
+func (s *Server) getSessionTracesHandler(c echo.Context) error {
+	sessionId := c.Param("id")
+	session, err := s.db.GetSession(sessionId)
+	if err != nil {
+		log.Printf("Getting session failed: %v\n", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown session id")
+	}
+	app, err := s.db.GetApplication(session.AppId)
+	if err != nil {
+		log.Printf("Getting app failed: %v\n", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown session id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+
+	page, err := parsePageQuery(c, "cursor")
+	if err != nil {
+		return err
+	}
+
+	if c.QueryParam("format") == "otlp" {
+		if c.QueryParam("limit") != "" || page.Cursor != "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Query params 'limit' and 'cursor' are not supported with 'format=otlp'")
+		}
+
+		entities, err := s.getAllSessionTraces(session.Id, page)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+
+		export := tracesToOTLP(app, session, entities)
+		if c.Request().Header.Get(echo.HeaderAccept) == MIMEApplicationProtobuf {
+			data, err := proto.Marshal(export)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, err)
+			}
+			return c.Blob(http.StatusOK, MIMEApplicationProtobuf, data)
+		}
+
+		data, err := marshalOTLPJSON(export)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+		return c.JSONBlob(http.StatusOK, data)
+	}
+
+	entities, nextCursor, err := s.db.GetTracesBySessionId(session.Id, page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	DTOS := make([]model.TraceDTO, len(entities), len(entities))
+	for i, ent := range entities {
+		DTOS[i] = model.TraceDTO{
+			TraceId:      ent.TraceId,
+			GroupId:      ent.GroupId,
+			SessionId:    ent.SessionId,
+			ParentId:     ent.ParentId,
+			Name:         ent.Name,
+			Status:       ent.Status,
+			ErrorMessage: ent.ErrorMessage,
+			StartedAt:    ent.StartedAt,
+			EndedAt:      ent.EndedAt,
+			HasEnded:     ent.HasEnded,
+			Attributes:   ent.Attributes,
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message": "Success",
+		"traces":  DTOS,
+		"page":    pageDTO(page, nextCursor),
+	})
+}
+
+// getSessionTraceTreeHandler returns the traces of a session as one span
+// hierarchy per group, with durations, self-time and the critical path.
+// Orphans and traces which never ended are flagged. Groups are never split
+// across pages, so only 'from' and 'to' are supported.
+func (s *Server) getSessionTraceTreeHandler(c echo.Context) error {
+	sessionId := c.Param("id")
+	session, err := s.db.GetSession(sessionId)
+	if err != nil {
+		log.Printf("Getting session failed: %v\n", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown session id")
+	}
+	app, err := s.db.GetApplication(session.AppId)
+	if err != nil {
+		log.Printf("Getting app failed: %v\n", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown session id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied to this app")
+	}
+
+	page, err := parsePageQuery(c, "cursor")
+	if err != nil {
+		return err
+	}
+
+	entities, err := s.getAllSessionTraces(session.Id, page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message": "Success",
+		"groups":  buildTraceTrees(entities),
+	})
+}
+
+func (s *Server) getSessionInfoHandler(c echo.Context) error {
+	sessionId := c.Param("id")
+	session, err := s.db.GetSession(sessionId)
+	if err != nil {
+		log.Printf("Getting session failed: %v\n", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown session id")
+	}
+	app, err := s.db.GetApplication(session.AppId)
+	if err != nil {
+		log.Printf("Getting app failed: %v\n", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown session id")
+	}
+	if !s.canReadApp(c, app) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied")
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message": "Success",
 		"session": model.SessionDTO{
 			Id:             session.Id,
 			InstallationId: session.InstallationId,
+			AppVersion:     session.AppVersion,
+			VersionCode:    session.VersionCode,
+			BuildType:      session.BuildType,
 			CreatedAt:      session.CreatedAt,
 			Crashed:        session.Crashed,
 		},
@@ -778,7 +2575,22 @@ func (s *Server) createTypedInstallationHandler(c echo.Context) error {
 * A collection can contain:
 * 0-1 sessions,
 * 0-* events,
-* 0-* traces.
+* 0-* traces,
+* 0-* crashes,
+* 0-* anrs,
+* 0-* network requests.
+*
+* The collection is persisted to the ingestion queue before the response is
+* sent, and written to the database by a background worker.
+* Uploading the same collection again is safe. Records with ids that are
+* already stored are ignored, except traces that have ended, which complete
+* a trace previously received unfinished.
+*
+* Events and traces are sampled by the rules of the app, and dropped once the
+* monthly quotas of the app are used up. 'dropped' is only set if any were,
+* and counts the events and traces which will not be stored, and which
+* should not be sent again. Events and traces uploaded again count towards
+* the quotas again.
 *
 * @apiUse ApiKeyAuth
  */
@@ -821,58 +2633,141 @@ func (s *Server) createCollectionHandler(c echo.Context) error {
 		}
 	}
 
-	go func() {
-		if collectionData.Session != nil {
-			sessionDTO := collectionData.Session
-
-			err := s.db.CreateSession(model.NewSessionData{
-				Id:             sessionDTO.Id,
-				InstallationId: sessionDTO.InstallationId,
-				AppId:          appId.(int),
-				CreatedAt:      sessionDTO.CreatedAt,
-				Crashed:        sessionDTO.Crashed,
-			})
-			if err != nil {
-				log.Printf("Error creating session (%v): %v\n", sessionDTO, err)
-			}
+	for i, e := range collectionData.Crashes {
+		if err := c.Validate(&e); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"message": fmt.Sprintf("Body validation failed: %v", err),
+				"path":    fmt.Sprintf("crashes[%d]", i),
+			})
+		}
+	}
+
+	for i, e := range collectionData.Anrs {
+		if err := c.Validate(&e); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"message": fmt.Sprintf("Body validation failed: %v", err),
+				"path":    fmt.Sprintf("anrs[%d]", i),
+			})
+		}
+	}
+
+	for i, e := range collectionData.NetworkRequests {
+		if err := c.Validate(&e); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"message": fmt.Sprintf("Body validation failed: %v", err),
+				"path":    fmt.Sprintf("networkRequests[%d]", i),
+			})
+		}
+	}
+
+	// SDKs which do not send their installation in a header are limited
+	// once the body shows which installation sent it
+	if c.Request().Header.Get(installationIdHeader) == "" && collectionData.Session != nil {
+		if err := s.throttle(c, model.ThrottleReasonInstallation, collectionData.Session.InstallationId); err != nil {
+			return err
 		}
+	}
 
-		for _, e := range collectionData.Events {
-			err := s.db.CreateEvent(model.NewEventData{
-				Id:             e.Id,
-				SessionId:      e.SessionId,
-				AppId:          appId.(int),
-				SerializedData: e.SerializedData,
-				Type:           e.Type,
-				CreatedAt:      e.CreatedAt,
-			})
-			if err != nil {
-				log.Printf("Error creating event (%v): %v\n", e, err)
-			}
-		}
+	admitted := s.admitIngestion(appId.(int), collectionData.Events, collectionData.Traces, collectionSessions(collectionData))
+	collectionData.Events, collectionData.Traces = admitted.events, admitted.traces
+
+	job := collectionJob{
+		AppId:        appId.(int),
+		Collection:   collectionData,
+		EventIndices: admitted.eventIndices,
+		TraceIndices: admitted.traceIndices,
+	}
+	if admitted.usage != nil {
+		job.UsageMonth = admitted.usage.Month
+	}
+	batchId, err := s.queue.Enqueue(collectionJobKind, job)
+	if errors.Is(err, queue.ErrQueueFull) || errors.Is(err, queue.ErrQueueClosed) {
+		s.releaseIngestion(appId.(int), admitted)
+		c.Response().Header().Set("Retry-After", "30")
+		return echo.NewHTTPError(http.StatusServiceUnavailable, "Ingestion queue is unavailable, try again later")
+	}
+	if err != nil {
+		s.releaseIngestion(appId.(int), admitted)
+		log.Printf("Error enqueuing collection: %v\n", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Collection could not be accepted")
+	}
+
+	// The batch is tracked in the database so its status can be polled from
+	// any replica. The job is already persisted, so a failure here is not fatal
+	err = s.db.CreateIngestionBatch(model.NewIngestionBatchData{
+		Id:        batchId,
+		AppId:     appId.(int),
+		CreatedAt: time.Now().UnixMilli(),
+	})
+	if err != nil {
+		log.Printf("Error creating ingestion batch %s: %v\n", batchId, err)
+	}
+
+	res := map[string]any{
+		"message": "Creation of collection have been started",
+		"batchId": batchId,
+	}
+	if admitted.drops != (model.IngestionDropsDTO{}) {
+		res["dropped"] = admitted.drops
+	}
+
+	return c.JSON(http.StatusAccepted, res)
+}
+
+/**
+* @api {get} /api/v1/collection/:id Get status of a collection
+* @apiName GetCollectionStatus
+* @apiGroup Collection
+* @apiParam {String} id Batch id returned when the collection was created
+*
+* @apiDescription Get the ingestion status of a collection.
+* The status is one of 'pending', 'retrying', 'completed' or 'failed'.
+* A collection is stored as a whole. If an item cannot be stored, nothing of
+* the collection is, and the failure names that item with a path like
+* 'session', 'events[3]' or 'traces[0]', so the collection can be resent
+* without it.
+*
+* @apiUse ApiKeyAuth
+ */
+func (s *Server) getCollectionStatusHandler(c echo.Context) error {
+	appId := c.Get("appId")
+	if appId == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Missing app id")
+	}
 
-		for _, t := range collectionData.Traces {
-			err := s.db.CreateTrace(model.NewTraceData{
-				TraceId:      t.TraceId,
-				SessionId:    t.SessionId,
-				GroupId:      t.GroupId,
-				ParentId:     t.ParentId,
-				AppId:        appId.(int),
-				Name:         t.Name,
-				Status:       t.Status,
-				ErrorMessage: t.ErrorMessage,
-				StartedAt:    t.StartedAt,
-				EndedAt:      t.EndedAt,
-				HasEnded:     t.HasEnded,
+	batchId := c.Param("id")
+	batch, err := s.db.GetIngestionBatch(batchId)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Batches of other apps are unknown, like in the database
+		if job, ok := s.queue.Pending(batchId); ok && collectionJobAppId(job) == appId.(int) {
+			return c.JSON(http.StatusOK, map[string]any{
+				"message": "Success",
+				"batch": model.IngestionBatchDTO{
+					Id:       batchId,
+					Status:   model.IngestionStatusPending,
+					Failures: make([]model.IngestionFailure, 0),
+				},
 			})
-			if err != nil {
-				log.Printf("Error creating trace (%v): %v\n", t, err)
-			}
 		}
-	}()
+	}
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && batch.AppId != appId.(int)) {
+		return echo.NewHTTPError(http.StatusNotFound, "Unknown batch id")
+	}
+	if err != nil {
+		log.Printf("Error getting ingestion batch %s: %v\n", batchId, err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Could not get status of collection")
+	}
 
-	return c.JSON(http.StatusAccepted, map[string]string{
-		"message": "Creation of collection have been started",
+	return c.JSON(http.StatusOK, map[string]any{
+		"message": "Success",
+		"batch": model.IngestionBatchDTO{
+			Id:        batch.Id,
+			Status:    batch.Status,
+			Attempts:  batch.Attempts,
+			Failures:  batch.Failures,
+			CreatedAt: batch.CreatedAt,
+			UpdatedAt: batch.UpdatedAt,
+		},
 	})
 }
 
@@ -903,6 +2798,9 @@ func (s *Server) createSessionHandler(c echo.Context) error {
 		Id:             sessionData.Id,
 		InstallationId: sessionData.InstallationId,
 		AppId:          appId.(int),
+		AppVersion:     sessionData.AppVersion,
+		VersionCode:    sessionData.VersionCode,
+		BuildType:      sessionData.BuildType,
 		CreatedAt:      sessionData.CreatedAt,
 		Crashed:        sessionData.Crashed,
 	})
@@ -918,12 +2816,33 @@ func (s *Server) createSessionHandler(c echo.Context) error {
 }
 
 /**
-* @api {post} /api/v1/sessions/:id/crash Mark a session as crashed
+* @api {post} /api/v1/sessions/:id/crash Report a crash of a session
 * @apiName MarkSessionCrash
 * @apiGroup Session
 * @apiParam {String} id UUID of crashed session
 *
+* @apiDescription Mark a session as crashed. If the body contains a crash
+* report, it is stored and grouped into an issue with other crashes that
+* have the same exception class and top stack frames.
+* Without a body, the session is only marked as crashed.
+*
+* @apiBody {String} id UUID of the crash
+* @apiBody {String} exceptionClass Fully qualified class of the exception
+* @apiBody {String} [message] Message of the exception
+* @apiBody {Object[]} [frames] Stack frames, top of the stack first
+* @apiBody {String} frames.className Fully qualified class of the frame
+* @apiBody {String} frames.methodName Method of the frame
+* @apiBody {String} [frames.fileName] Source file of the frame
+* @apiBody {Number} [frames.lineNumber] Line number of the frame
+* @apiBody {String} [threadName] Name of the crashing thread
+* @apiBody {String} [screen] Screen in the foreground at the time of the crash
+* @apiBody {String} [appVersion] Version of the app, used to deobfuscate
+* the crash with the mapping uploaded for it
+* @apiBody {Number} createdAt Timestamp of the crash
+*
 * @apiUse ApiKeyAuth
+* @apiUse CompressedBody
+* @apiUse ProtobufBody
  */
 func (s *Server) sessionCrashHandler(c echo.Context) error {
 	appId := c.Get("appId")
@@ -932,6 +2851,28 @@ func (s *Server) sessionCrashHandler(c echo.Context) error {
 	}
 
 	sessionId := c.Param("id")
+	if c.Request().ContentLength != 0 {
+		var dto model.CrashDTO
+		if err := c.Bind(&dto); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"message": fmt.Sprintf("Body could not be parsed: %v", err),
+			})
+		}
+		dto.SessionId = sessionId
+		if err := c.Validate(&dto); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		if err := s.createCrash(appId.(int), dto); err != nil {
+			log.Printf("Error creating crash for session with id %s: %v\n", sessionId, err)
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"message": fmt.Sprintf("Crash could not be created: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusCreated, map[string]string{"message": "Crash created"})
+	}
+
 	err := s.db.MarkSessionCrashed(sessionId, appId.(int))
 	if err != nil {
 		log.Printf("Error marking session with id %s as crashed: %v\n", sessionId, err)
@@ -941,10 +2882,69 @@ func (s *Server) sessionCrashHandler(c echo.Context) error {
 	return c.JSON(http.StatusCreated, map[string]string{"message": "Session marked as crashed"})
 }
 
+/**
+* @api {post} /api/v1/sessions/:id/anr Report an ANR of a session
+* @apiName CreateSessionAnr
+* @apiGroup Session
+* @apiParam {String} id UUID of the session
+*
+* @apiDescription Store an Application Not Responding report. ANRs are
+* grouped into an issue with other ANRs whose main thread was blocked at the
+* same place.
+*
+* @apiBody {String} id UUID of the ANR
+* @apiBody {Number} duration Milliseconds the main thread was blocked
+* @apiBody {Object[]} mainThread Stack of the main thread, top of the stack first
+* @apiBody {String} mainThread.className Fully qualified class of the frame
+* @apiBody {String} mainThread.methodName Method of the frame
+* @apiBody {String} [mainThread.fileName] Source file of the frame
+* @apiBody {Number} [mainThread.lineNumber] Line number of the frame
+* @apiBody {Object[]} [threads] Dump of the other threads
+* @apiBody {String} threads.name Name of the thread
+* @apiBody {String} [threads.state] State of the thread, fx. 'BLOCKED'
+* @apiBody {Object[]} [threads.frames] Stack of the thread, same format as mainThread
+* @apiBody {String} [screen] Screen in the foreground at the time of the ANR
+* @apiBody {String} [appVersion] Version of the app, used to deobfuscate
+* the stacks with the mapping uploaded for it
+* @apiBody {Number} createdAt Timestamp of the ANR
+*
+* @apiUse ApiKeyAuth
+* @apiUse CompressedBody
+* @apiUse ProtobufBody
+ */
+func (s *Server) sessionAnrHandler(c echo.Context) error {
+	appId := c.Get("appId")
+	if appId == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Missing app id")
+	}
+
+	var dto model.AnrDTO
+	if err := c.Bind(&dto); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": fmt.Sprintf("Body could not be parsed: %v", err),
+		})
+	}
+	dto.SessionId = c.Param("id")
+	if err := c.Validate(&dto); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.createAnr(appId.(int), dto); err != nil {
+		log.Printf("Error creating ANR for session with id %s: %v\n", dto.SessionId, err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": fmt.Sprintf("ANR could not be created: %v", err),
+		})
+	}
+
+	return c.JSON(http.StatusCreated, map[string]string{"message": "ANR created"})
+}
+
 /**
 * @api {post} /api/v1/events Create an event
 * @apiName CreateEvent
 * @apiGroup Event
+* @apiDescription Events which are sampled out, or exceed the monthly quota of
+* the app, are not stored and answered with status 202
 *
 * @apiUse ApiKeyAuth
  */
@@ -964,19 +2964,32 @@ func (s *Server) createEventHandler(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	err := s.db.CreateEvent(model.NewEventData{
+	admitted := s.admitIngestion(appId.(int), []model.EventDTO{dto}, nil, nil)
+	if len(admitted.events) == 0 {
+		return c.JSON(http.StatusAccepted, map[string]any{
+			"message": "Event dropped by sampling or the monthly event quota",
+			"dropped": admitted.drops,
+		})
+	}
+
+	inserted, err := s.db.CreateEvent(model.NewEventData{
 		Id:             dto.Id,
 		SessionId:      dto.SessionId,
 		AppId:          appId.(int),
 		Type:           dto.Type,
 		SerializedData: dto.SerializedData,
+		Attributes:     dto.Attributes,
 		CreatedAt:      dto.CreatedAt,
 	})
 	if err != nil {
+		s.releaseIngestion(appId.(int), admitted)
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"message": fmt.Sprintf("Event could not be created: %v", err),
 		})
 	}
+	if !inserted && admitted.usage != nil {
+		s.settleIngestion(appId.(int), *admitted.usage, 0, 0, 1)
+	}
 
 	return c.JSON(http.StatusCreated, map[string]string{
 		"message": "Event created",
@@ -987,6 +3000,8 @@ func (s *Server) createEventHandler(c echo.Context) error {
 * @api {post} /api/v1/traces Create a trace
 * @apiName CreateTrace
 * @apiGroup Trace
+* @apiDescription Traces which are sampled out, or exceed the monthly quota of
+* the app, are not stored and answered with status 202
 *
 * @apiUse ApiKeyAuth
  */
@@ -1004,7 +3019,15 @@ func (s *Server) createTraceHandler(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	err := s.db.CreateTrace(model.NewTraceData{
+	admitted := s.admitIngestion(appId.(int), nil, []model.TraceDTO{*data}, nil)
+	if len(admitted.traces) == 0 {
+		return c.JSON(http.StatusAccepted, map[string]any{
+			"message": "Trace dropped by sampling or the monthly trace quota",
+			"dropped": admitted.drops,
+		})
+	}
+
+	inserted, err := s.db.CreateTrace(model.NewTraceData{
 		TraceId:      data.TraceId,
 		SessionId:    data.SessionId,
 		GroupId:      data.GroupId,
@@ -1016,10 +3039,15 @@ func (s *Server) createTraceHandler(c echo.Context) error {
 		StartedAt:    data.StartedAt,
 		EndedAt:      data.EndedAt,
 		HasEnded:     data.HasEnded,
+		Attributes:   data.Attributes,
 	})
 	if err != nil {
+		s.releaseIngestion(appId.(int), admitted)
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Trace could not be created: %v", err))
 	}
+	if !inserted && admitted.usage != nil {
+		s.settleIngestion(appId.(int), *admitted.usage, 0, 0, 1)
+	}
 
 	return c.JSON(http.StatusCreated, map[string]string{
 		"message": "Trace created",
@@ -1040,7 +3068,11 @@ func (s *Server) createMemoryUsageHandler(c echo.Context) error {
 	}
 
 	usages := make([]model.NewMemoryUsageDTO, 0, 0)
-	if err := json.NewDecoder(c.Request().Body).Decode(&usages); err != nil {
+	if isProtobufRequest(c.Request()) {
+		if err := c.Bind(&usages); err != nil {
+			return err
+		}
+	} else if err := json.NewDecoder(c.Request().Body).Decode(&usages); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 	log.Printf("Usages: %v\n", usages)
@@ -1072,3 +3104,313 @@ func (s *Server) createMemoryUsageHandler(c echo.Context) error {
 		"message": "Memory usage created",
 	})
 }
+
+/**
+* @api {post} /api/v1/resources/cpu Create CPU usage snapshots
+* @apiName CreateCpuUsage
+* @apiGroup Resources
+*
+* @apiUse ApiKeyAuth
+* @apiUse CompressedBody
+* @apiUse ProtobufBody
+ */
+func (s *Server) createCpuUsageHandler(c echo.Context) error {
+	appId := c.Get("appId")
+	if appId == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Missing app id")
+	}
+
+	usages := make([]model.NewCpuUsageDTO, 0)
+	if isProtobufRequest(c.Request()) {
+		if err := c.Bind(&usages); err != nil {
+			return err
+		}
+	} else if err := json.NewDecoder(c.Request().Body).Decode(&usages); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	for _, usage := range usages {
+		if err := c.Validate(&usage); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	for _, data := range usages {
+		err := s.db.CreateCpuUsage(model.NewCpuUsageData{
+			Id:             data.Id,
+			SessionId:      data.SessionId,
+			InstallationId: data.InstallationId,
+			AppId:          appId.(int),
+			Usage:          data.Usage,
+			UserTime:       data.UserTime,
+			SystemTime:     data.SystemTime,
+			Cores:          data.Cores,
+			CreatedAt:      data.CreatedAt,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("CPU usage could not be created: %v", err))
+		}
+	}
+
+	return c.JSON(http.StatusCreated, map[string]string{
+		"message": "CPU usage created",
+	})
+}
+
+/**
+* @api {post} /api/v1/resources/frames Create frame metrics
+* @apiName CreateFrameMetrics
+* @apiGroup Resources
+* @apiDescription Create metrics of the frames rendered on a screen since the
+* previous metrics. Janky and frozen frames must not exceed the total frames.
+*
+* @apiUse ApiKeyAuth
+* @apiUse CompressedBody
+* @apiUse ProtobufBody
+ */
+func (s *Server) createFrameMetricsHandler(c echo.Context) error {
+	appId := c.Get("appId")
+	if appId == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Missing app id")
+	}
+
+	metrics := make([]model.NewFrameMetricsDTO, 0)
+	if isProtobufRequest(c.Request()) {
+		if err := c.Bind(&metrics); err != nil {
+			return err
+		}
+	} else if err := json.NewDecoder(c.Request().Body).Decode(&metrics); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	for _, metric := range metrics {
+		if err := c.Validate(&metric); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	for _, data := range metrics {
+		err := s.db.CreateFrameMetrics(model.NewFrameMetricsData{
+			Id:             data.Id,
+			SessionId:      data.SessionId,
+			InstallationId: data.InstallationId,
+			AppId:          appId.(int),
+			Screen:         data.Screen,
+			TotalFrames:    data.TotalFrames,
+			JankyFrames:    data.JankyFrames,
+			FrozenFrames:   data.FrozenFrames,
+			CreatedAt:      data.CreatedAt,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Frame metrics could not be created: %v", err))
+		}
+	}
+
+	return c.JSON(http.StatusCreated, map[string]string{
+		"message": "Frame metrics created",
+	})
+}
+
+/**
+* @api {post} /api/v1/resources/battery Create battery state snapshots
+* @apiName CreateBatteryState
+* @apiGroup Resources
+* @apiDescription Create snapshots of the battery and thermal state of the
+* device. 'thermalState' is one of none, light, moderate, severe, critical,
+* emergency or shutdown, like the thermal status of Android's PowerManager.
+*
+* @apiUse ApiKeyAuth
+* @apiUse CompressedBody
+* @apiUse ProtobufBody
+ */
+func (s *Server) createBatteryStateHandler(c echo.Context) error {
+	appId := c.Get("appId")
+	if appId == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Missing app id")
+	}
+
+	states := make([]model.NewBatteryStateDTO, 0)
+	if isProtobufRequest(c.Request()) {
+		if err := c.Bind(&states); err != nil {
+			return err
+		}
+	} else if err := json.NewDecoder(c.Request().Body).Decode(&states); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	for _, state := range states {
+		if err := c.Validate(&state); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	for _, data := range states {
+		err := s.db.CreateBatteryState(model.NewBatteryStateData{
+			Id:             data.Id,
+			SessionId:      data.SessionId,
+			InstallationId: data.InstallationId,
+			AppId:          appId.(int),
+			Level:          data.Level,
+			Charging:       data.Charging,
+			Temperature:    data.Temperature,
+			ThermalState:   data.ThermalState,
+			PowerSave:      data.PowerSave,
+			CreatedAt:      data.CreatedAt,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Battery state could not be created: %v", err))
+		}
+	}
+
+	return c.JSON(http.StatusCreated, map[string]string{
+		"message": "Battery state created",
+	})
+}
+
+/**
+* @api {post} /api/v1/resources/network Create network usage snapshots
+* @apiName CreateNetworkUsage
+* @apiGroup Resources
+* @apiDescription Create sums of the network requests of the app since the
+* previous snapshot.
+*
+* @apiUse ApiKeyAuth
+* @apiUse CompressedBody
+* @apiUse ProtobufBody
+ */
+func (s *Server) createNetworkUsageHandler(c echo.Context) error {
+	appId := c.Get("appId")
+	if appId == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Missing app id")
+	}
+
+	usages := make([]model.NewNetworkUsageDTO, 0)
+	if isProtobufRequest(c.Request()) {
+		if err := c.Bind(&usages); err != nil {
+			return err
+		}
+	} else if err := json.NewDecoder(c.Request().Body).Decode(&usages); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	for _, usage := range usages {
+		if err := c.Validate(&usage); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	for _, data := range usages {
+		err := s.db.CreateNetworkUsage(model.NewNetworkUsageData{
+			Id:             data.Id,
+			SessionId:      data.SessionId,
+			InstallationId: data.InstallationId,
+			AppId:          appId.(int),
+			ConnectionType: data.ConnectionType,
+			Requests:       data.Requests,
+			FailedRequests: data.FailedRequests,
+			BytesSent:      data.BytesSent,
+			BytesReceived:  data.BytesReceived,
+			CreatedAt:      data.CreatedAt,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Network usage could not be created: %v", err))
+		}
+	}
+
+	return c.JSON(http.StatusCreated, map[string]string{
+		"message": "Network usage created",
+	})
+}
+
+/**
+* @api {post} /api/v1/resources/starts Create app starts
+* @apiName CreateAppStarts
+* @apiGroup Resources
+* @apiDescription Create measurements of the time from a start of the app
+* until its first frame. Cold starts must include the time from process
+* start until Application.onCreate, which warm and hot starts skip.
+*
+* @apiUse ApiKeyAuth
+* @apiUse CompressedBody
+* @apiUse ProtobufBody
+ */
+func (s *Server) createAppStartsHandler(c echo.Context) error {
+	appId := c.Get("appId")
+	if appId == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Missing app id")
+	}
+
+	starts := make([]model.NewAppStartDTO, 0)
+	if isProtobufRequest(c.Request()) {
+		if err := c.Bind(&starts); err != nil {
+			return err
+		}
+	} else if err := json.NewDecoder(c.Request().Body).Decode(&starts); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	for _, start := range starts {
+		if err := c.Validate(&start); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	for _, data := range starts {
+		err := s.db.CreateAppStart(model.NewAppStartData{
+			Id:              data.Id,
+			SessionId:       data.SessionId,
+			InstallationId:  data.InstallationId,
+			AppId:           appId.(int),
+			StartType:       data.StartType,
+			Duration:        data.Duration,
+			ProcessDuration: data.ProcessDuration,
+			CreatedAt:       data.CreatedAt,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("App start could not be created: %v", err))
+		}
+	}
+
+	return c.JSON(http.StatusCreated, map[string]string{
+		"message": "App starts created",
+	})
+}
+
+/**
+* @api {post} /api/v1/network/requests Create network requests
+* @apiName CreateNetworkRequests
+* @apiGroup Network
+* @apiDescription Create HTTP calls made by the app. Query strings are
+* dropped from the url template, and path segments which look like ids, fx.
+* numbers or uuids, are replaced by '{id}'.
+*
+* @apiUse ApiKeyAuth
+* @apiUse CompressedBody
+* @apiUse ProtobufBody
+ */
+func (s *Server) createNetworkRequestsHandler(c echo.Context) error {
+	appId := c.Get("appId")
+	if appId == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Missing app id")
+	}
+
+	requests := make([]model.NetworkRequestDTO, 0)
+	if isProtobufRequest(c.Request()) {
+		if err := c.Bind(&requests); err != nil {
+			return err
+		}
+	} else if err := json.NewDecoder(c.Request().Body).Decode(&requests); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data := make([]model.NewNetworkRequestData, len(requests))
+	for i, request := range requests {
+		if err := c.Validate(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("networkRequests[%d]: %v", i, err))
+		}
+		data[i] = networkRequestData(appId.(int), request)
+	}
+
+	if err := s.db.CreateNetworkRequests(data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Network requests could not be created: %v", err))
+	}
+
+	return c.JSON(http.StatusCreated, map[string]string{
+		"message": "Network requests created",
+	})
+}
@@ -1,23 +1,39 @@
 package server
 
 import (
+	"ObservabilityServer/internal/auth"
 	"ObservabilityServer/internal/database"
 	"ObservabilityServer/internal/model"
+	"ObservabilityServer/internal/pb"
+	"ObservabilityServer/internal/queue"
+	"ObservabilityServer/internal/ratelimit"
 	"bytes"
+	"compress/gzip"
 	"context"
+	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"log"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/labstack/echo/v4"
+	"google.golang.org/protobuf/proto"
 )
 
 var (
-	db    database.Service
-	appId int
+	db     database.Service
+	appId  int
+	userId int
 )
 
 func TestMain(m *testing.M) {
@@ -39,6 +55,21 @@ func TestMain(m *testing.M) {
 	if err != nil {
 		log.Fatalf("Could not create test application: %v", err)
 	}
+	userId, err = db.CreateUser(model.NewUserData{
+		Name:         "Test member",
+		PasswordHash: "-",
+	})
+	if err != nil {
+		log.Fatalf("Could not create test user: %v", err)
+	}
+	err = db.CreateTeamUserLink(model.NewTeamUserLinkData{
+		TeamId: teamId,
+		UserId: userId,
+		Role:   model.RoleOwner,
+	})
+	if err != nil {
+		log.Fatalf("Could not link test user to team: %v", err)
+	}
 
 	m.Run()
 
@@ -47,6 +78,21 @@ func TestMain(m *testing.M) {
 	}
 }
 
+func newTestQueue(t *testing.T, s *Server) *queue.Queue {
+	q, err := queue.New(model.QueueConfig{
+		Dir:         t.TempDir(),
+		Capacity:    10,
+		Workers:     1,
+		MaxAttempts: 1,
+	}, s.processJob)
+	if err != nil {
+		t.Fatalf("Could not create queue: %v", err)
+	}
+	s.queue = q
+
+	return q
+}
+
 func TestTeamUserAuth(t *testing.T) {
 	s := &Server{
 		db: db,
@@ -154,6 +200,8 @@ func TestCreateCollection(t *testing.T) {
 	s := &Server{
 		db: db,
 	}
+	q := newTestQueue(t, s)
+	defer q.Shutdown(context.Background())
 
 	c.Set("appId", appId)
 
@@ -178,12 +226,390 @@ func TestCreateCollection(t *testing.T) {
 	}
 
 	// Compare the decoded response with the expected value
-	if !reflect.DeepEqual(expected, actual) {
+	if !reflect.DeepEqual(expected["message"], actual["message"]) || actual["batchId"] == "" {
 		t.Errorf("createCollectionHandler() wrong response body. expected = %v, actual = %v", expected, actual)
 		return
 	}
 }
 
+func TestCreateCollectionCompressed(t *testing.T) {
+	sessionId := "0b6f1d8e-2c3a-4e5f-8a9b-1c2d3e4f5a6b"
+	collection := model.CollectionDTO{
+		Session: &model.SessionDTO{
+			Id:             sessionId,
+			InstallationId: "1c7a2e9f-3d4b-4f6a-9bac-2d3e4f5a6b7c",
+			CreatedAt:      17000000,
+		},
+		Events: []model.EventDTO{
+			{
+				Id:             "2d8b3fa0-4e5c-4a7b-8cbd-3e4f5a6b7c8d",
+				SessionId:      sessionId,
+				Type:           "TestEvent",
+				SerializedData: strings.Repeat("{\"screen\": \"Checkout\"}", 100),
+				CreatedAt:      17000001,
+			},
+		},
+		Traces: make([]model.TraceDTO, 0, 0),
+	}
+	body, err := json.Marshal(collection)
+	if err != nil {
+		t.Fatalf("Could not marshal collectionDTO: %v", err)
+	}
+
+	var gzipBody bytes.Buffer
+	gw := gzip.NewWriter(&gzipBody)
+	gw.Write(body)
+	gw.Close()
+
+	zw, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatalf("Could not create zstd writer: %v", err)
+	}
+	zstdBody := zw.EncodeAll(body, nil)
+
+	tests := []struct {
+		name         string
+		encoding     string
+		body         []byte
+		maxBytes     int64
+		expectedCode int
+	}{
+		{"gzip", "gzip", gzipBody.Bytes(), 1 << 20, http.StatusAccepted},
+		{"zstd", "zstd", zstdBody, 1 << 20, http.StatusAccepted},
+		{"too large", "gzip", gzipBody.Bytes(), 64, http.StatusRequestEntityTooLarge},
+		{"unsupported", "br", body, 1 << 20, http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = NewValidator()
+			s := &Server{
+				db:                   db,
+				maxDecompressedBytes: tt.maxBytes,
+			}
+			q := newTestQueue(t, s)
+			defer q.Shutdown(context.Background())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/collection", bytes.NewReader(tt.body))
+			req.Header.Set("Content-type", "application/json")
+			req.Header.Set("Content-Encoding", tt.encoding)
+			resp := httptest.NewRecorder()
+			c := e.NewContext(req, resp)
+			c.Set("appId", appId)
+
+			err := s.DecompressMiddleware(s.createCollectionHandler)(c)
+			code := resp.Code
+			if httpErr, ok := err.(*echo.HTTPError); ok {
+				code = httpErr.Code
+			} else if err != nil {
+				t.Fatalf("DecompressMiddleware() error = %v", err)
+			}
+
+			if code != tt.expectedCode {
+				t.Errorf("DecompressMiddleware() wrong status code. expected = %d, actual = %d", tt.expectedCode, code)
+			}
+		})
+	}
+}
+
+func TestCreateCollectionProtobuf(t *testing.T) {
+	tests := []struct {
+		name         string
+		collection   *pb.Collection
+		expectedCode int
+		expectedPath string
+	}{
+		{
+			name: "valid",
+			collection: &pb.Collection{
+				Session: &pb.Session{
+					Id:             "3e9c4ab1-5f6d-4b8c-9dce-4f5a6b7c8d9e",
+					InstallationId: "4fad5bc2-6a7e-4c9d-8edf-5a6b7c8d9eaf",
+					CreatedAt:      17000000,
+				},
+				Traces: []*pb.Trace{
+					{
+						TraceId:   "5abe6cd3-7b8f-4dae-9fe0-6b7c8d9eafb0",
+						SessionId: "3e9c4ab1-5f6d-4b8c-9dce-4f5a6b7c8d9e",
+						GroupId:   "6bcf7de4-8c9a-4ebf-8af1-7c8d9eafb0c1",
+						Name:      "TestTrace",
+						Status:    "Ok",
+						StartTime: 17000001,
+						EndTime:   17000002,
+						HasEnded:  true,
+					},
+				},
+			},
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name: "invalid trace",
+			collection: &pb.Collection{
+				Traces: []*pb.Trace{
+					{
+						TraceId:   "7cd08ef5-9dab-4fc0-9b02-8d9eafb0c1d2",
+						SessionId: "3e9c4ab1-5f6d-4b8c-9dce-4f5a6b7c8d9e",
+						GroupId:   "8de19f06-aebc-4ad1-8c13-9eafb0c1d2e3",
+						Name:      "TestTrace",
+						Status:    "Error",
+						StartTime: 17000001,
+					},
+				},
+			},
+			expectedCode: http.StatusBadRequest,
+			expectedPath: "traces[0]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := proto.Marshal(tt.collection)
+			if err != nil {
+				t.Fatalf("Could not marshal protobuf collection: %v", err)
+			}
+
+			e := echo.New()
+			e.Validator = NewValidator()
+			e.Binder = NewBinder()
+			s := &Server{
+				db: db,
+			}
+			q := newTestQueue(t, s)
+			defer q.Shutdown(context.Background())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/collection", bytes.NewReader(body))
+			req.Header.Set("Content-type", MIMEApplicationProtobuf)
+			resp := httptest.NewRecorder()
+			c := e.NewContext(req, resp)
+			c.Set("appId", appId)
+
+			if err := s.createCollectionHandler(c); err != nil {
+				t.Fatalf("createCollectionHandler() error = %v", err)
+			}
+
+			var actual map[string]string
+			if err := json.NewDecoder(resp.Body).Decode(&actual); err != nil {
+				t.Fatalf("createCollectionHandler() error decoding response body: %v", err)
+			}
+
+			if resp.Code != tt.expectedCode {
+				t.Errorf("createCollectionHandler() wrong status code. expected = %d, actual = %d, body = %v", tt.expectedCode, resp.Code, actual)
+			}
+			if actual["path"] != tt.expectedPath {
+				t.Errorf("createCollectionHandler() wrong path. expected = '%s', actual = '%s'", tt.expectedPath, actual["path"])
+			}
+		})
+	}
+}
+
+func TestGetCollectionStatus(t *testing.T) {
+	sessionId := "8a1f3c42-58b4-4f61-9a0e-3f0f3b7c2d11"
+	collection := model.CollectionDTO{
+		Session: &model.SessionDTO{
+			Id:             sessionId,
+			InstallationId: "d2b5d7c5-1f5e-4bb3-a9a2-9d3c5f1c6a70",
+			CreatedAt:      17000000,
+		},
+		Events: []model.EventDTO{
+			{
+				Id:        "4c0b3c9e-7f5d-4d35-9c59-0c1e2a7a9f10",
+				SessionId: sessionId,
+				Type:      "TestEvent",
+				CreatedAt: 17000001,
+			},
+			{
+				// Unknown session, violates the foreign key
+				Id:        "5d1c4daf-8a6e-4e46-ad6a-1d2f3b8bae21",
+				SessionId: "6e2d5eb0-9b7f-4f57-be7b-2e3a4c9cbf32",
+				Type:      "TestEvent",
+				CreatedAt: 17000002,
+			},
+		},
+		Traces: make([]model.TraceDTO, 0, 0),
+	}
+	body, err := json.Marshal(collection)
+	if err != nil {
+		t.Fatalf("Could not marshal collectionDTO: %v", err)
+	}
+
+	e := echo.New()
+	e.Validator = NewValidator()
+	s := &Server{
+		db: db,
+	}
+	q := newTestQueue(t, s)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/collection", bytes.NewReader(body))
+	req.Header.Set("Content-type", "application/json")
+	resp := httptest.NewRecorder()
+	c := e.NewContext(req, resp)
+	c.Set("appId", appId)
+
+	if err := s.createCollectionHandler(c); err != nil {
+		t.Fatalf("createCollectionHandler() error = %v", err)
+	}
+	var created map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("createCollectionHandler() error decoding response body: %v", err)
+	}
+
+	// Wait for the collection to be processed
+	if err := q.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Could not drain queue: %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/collection/"+created["batchId"], nil)
+	resp = httptest.NewRecorder()
+	c = e.NewContext(req, resp)
+	c.SetParamNames("id")
+	c.SetParamValues(created["batchId"])
+	c.Set("appId", appId)
+
+	if err := s.getCollectionStatusHandler(c); err != nil {
+		t.Fatalf("getCollectionStatusHandler() error = %v", err)
+	}
+	var actual struct {
+		Batch model.IngestionBatchDTO `json:"batch"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&actual); err != nil {
+		t.Fatalf("getCollectionStatusHandler() error decoding response body: %v", err)
+	}
+
+	if actual.Batch.Status != model.IngestionStatusFailed {
+		t.Errorf("getCollectionStatusHandler() wrong status. expected = %s, actual = %s", model.IngestionStatusFailed, actual.Batch.Status)
+	}
+	if len(actual.Batch.Failures) != 1 || actual.Batch.Failures[0].Path != "events[1]" {
+		t.Errorf("getCollectionStatusHandler() wrong failures. expected path events[1], actual = %v", actual.Batch.Failures)
+	}
+
+	// Batches are only visible to the app that created them
+	c = e.NewContext(req, httptest.NewRecorder())
+	c.SetParamNames("id")
+	c.SetParamValues(created["batchId"])
+	c.Set("appId", -1)
+
+	err = s.getCollectionStatusHandler(c)
+	if httpErr, ok := err.(*echo.HTTPError); !ok || httpErr.Code != http.StatusNotFound {
+		t.Errorf("getCollectionStatusHandler() expected not found for other app, got %v", err)
+	}
+}
+
+func TestWriteCollectionAtomically(t *testing.T) {
+	sessionId := "27e2f3a4-b5c6-4b8d-9da4-c5d6e7f8091a"
+	err := db.CreateSession(model.NewSessionData{
+		Id:             sessionId,
+		InstallationId: "38f3a4b5-c6d7-4c9e-8eb5-d6e7f8091a2b",
+		AppId:          appId,
+		CreatedAt:      1700000000000,
+	})
+	if err != nil {
+		t.Fatalf("Could not create session: %v", err)
+	}
+	unknownSessionId := "49a4b5c6-d7e8-4daf-9fc6-e7f8091a2b3c"
+
+	s := &Server{
+		db: db,
+	}
+	failures, events, traces := s.writeCollection(collectionJob{
+		AppId: appId,
+		Collection: model.CollectionDTO{
+			Events: []model.EventDTO{
+				{Id: "5ab5c6d7-e8f9-4eb0-8ad7-f8091a2b3c4d", SessionId: sessionId, Type: "click", CreatedAt: 1700000000000},
+				{Id: "6bc6d7e8-f90a-4fc1-9be8-091a2b3c4d5e", SessionId: unknownSessionId, Type: "click", CreatedAt: 1700000000000},
+			},
+			Traces: []model.TraceDTO{
+				{TraceId: "7cd7e8f9-0a1b-4ad2-8cf9-1a2b3c4d5e6f", SessionId: unknownSessionId, GroupId: "group", Name: "trace", Status: "Ok", StartedAt: 1700000000000},
+			},
+		},
+	})
+
+	if len(failures) != 1 || failures[0].Path != "events[1]" {
+		t.Errorf("writeCollection() wrong failures. expected one failure of events[1], actual = %v", failures)
+	}
+	if events != 0 || traces != 0 {
+		t.Errorf("writeCollection() inserted %d events and %d traces, expected none", events, traces)
+	}
+
+	// The event which did not fail is rolled back with the rest
+	stored, _, err := db.GetEventsBySessionId(sessionId, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("Could not get events: %v", err)
+	}
+	if len(stored) != 0 {
+		t.Errorf("Expected nothing of the collection to be written, got %d events", len(stored))
+	}
+}
+
+func TestCollectionFailureAfterDroppedEvent(t *testing.T) {
+	app, err := db.GetApplication(appId)
+	if err != nil {
+		t.Fatalf("Could not get application: %v", err)
+	}
+	sampledAppId, err := db.CreateApplication(model.NewApplicationData{Name: "Sampled app", TeamId: app.TeamId})
+	if err != nil {
+		t.Fatalf("Could not create application: %v", err)
+	}
+	crashed := true
+	err = db.SetIngestionSettings(model.IngestionSettingsData{
+		AppId:         sampledAppId,
+		SamplingRules: []model.SamplingRule{{Crashed: &crashed, Rate: 1}, {Rate: 0}},
+		UpdatedBy:     userId,
+		UpdatedAt:     1700000000000,
+	})
+	if err != nil {
+		t.Fatalf("Could not set ingestion settings: %v", err)
+	}
+
+	// events[0] belongs to a session which is sampled out. events[1] is kept,
+	// since its session crashed, but the session is not stored
+	sampledSessionId := "8de8f90a-1b2c-4de3-9d0a-2b3c4d5e6f70"
+	crashedSessionId := "9ef90a1b-2c3d-4ef4-8e1b-3c4d5e6f7081"
+	collection := model.CollectionDTO{
+		Events: []model.EventDTO{
+			{Id: "a0f0a1b2-c3d4-4f05-9f2c-4d5e6f708192", SessionId: sampledSessionId, Type: "click", CreatedAt: 1700000000000},
+			{Id: "b1a1b2c3-d4e5-4a16-8a3d-5e6f708192a3", SessionId: crashedSessionId, Type: "click", CreatedAt: 1700000000000},
+		},
+		Crashes: []model.CrashDTO{
+			{Id: "c2b2c3d4-e5f6-4b27-9b4e-6f708192a3b4", SessionId: crashedSessionId, ExceptionClass: "java.lang.IllegalStateException", CreatedAt: 1700000000000},
+		},
+	}
+
+	e := echo.New()
+	e.Validator = NewValidator()
+	s := &Server{
+		db: db,
+	}
+	q := newTestQueue(t, s)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/collection", strings.NewReader(mustMarshal(t, collection)))
+	req.Header.Set("Content-type", "application/json")
+	resp := httptest.NewRecorder()
+	c := e.NewContext(req, resp)
+	c.Set("appId", sampledAppId)
+	if err := s.createCollectionHandler(c); err != nil {
+		t.Fatalf("createCollectionHandler() error = %v", err)
+	}
+	var created map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("createCollectionHandler() error decoding response body: %v", err)
+	}
+	batchId := created["batchId"].(string)
+
+	// Wait for the collection to be processed
+	if err := q.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Could not drain queue: %v", err)
+	}
+
+	batch, err := db.GetIngestionBatch(batchId)
+	if err != nil {
+		t.Fatalf("Could not get ingestion batch: %v", err)
+	}
+	if len(batch.Failures) != 1 || batch.Failures[0].Path != "events[1]" {
+		t.Errorf("Expected the failure to name events[1] as sent by the SDK, got %v", batch.Failures)
+	}
+}
+
 func TestCreateCollectionInvalidSession(t *testing.T) {
 	collection := model.CollectionDTO{
 		Session: &model.SessionDTO{
@@ -600,3 +1026,1929 @@ func TestCreateMemoryUsage(t *testing.T) {
 		return
 	}
 }
+
+func TestCreateResourceMetrics(t *testing.T) {
+	sessionId := "4b6c1a3e-0f7d-4e53-9d0e-7f1b2c3d4e5f"
+	installationId := "dd72f2d8-c679-4e7c-bf6b-56f6ec78391b"
+	err := db.CreateSession(model.NewSessionData{
+		Id:             sessionId,
+		InstallationId: installationId,
+		AppId:          appId,
+	})
+	if err != nil {
+		t.Fatalf("Could not create session: %v\n", err)
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		body         any
+		handler      func(s *Server) echo.HandlerFunc
+		expectedCode int
+	}{
+		{
+			name: "cpu",
+			path: "/api/v1/resources/cpu",
+			body: []model.NewCpuUsageDTO{{
+				Id: "0a4f2e7c-5d1b-4c3a-8e9f-1a2b3c4d5e01", SessionId: sessionId, InstallationId: installationId,
+				Usage: 42.5, UserTime: 120, SystemTime: 30, Cores: 8, CreatedAt: 12345678,
+			}},
+			handler:      func(s *Server) echo.HandlerFunc { return s.createCpuUsageHandler },
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "frames",
+			path: "/api/v1/resources/frames",
+			body: []model.NewFrameMetricsDTO{{
+				Id: "0a4f2e7c-5d1b-4c3a-8e9f-1a2b3c4d5e02", SessionId: sessionId, InstallationId: installationId,
+				Screen: "Checkout", TotalFrames: 600, JankyFrames: 12, FrozenFrames: 1, CreatedAt: 12345678,
+			}},
+			handler:      func(s *Server) echo.HandlerFunc { return s.createFrameMetricsHandler },
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "more janky than total frames",
+			path: "/api/v1/resources/frames",
+			body: []model.NewFrameMetricsDTO{{
+				Id: "0a4f2e7c-5d1b-4c3a-8e9f-1a2b3c4d5e03", SessionId: sessionId, InstallationId: installationId,
+				Screen: "Checkout", TotalFrames: 10, JankyFrames: 12, CreatedAt: 12345678,
+			}},
+			handler:      func(s *Server) echo.HandlerFunc { return s.createFrameMetricsHandler },
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "battery",
+			path: "/api/v1/resources/battery",
+			body: []model.NewBatteryStateDTO{{
+				Id: "0a4f2e7c-5d1b-4c3a-8e9f-1a2b3c4d5e04", SessionId: sessionId, InstallationId: installationId,
+				Level: 87, Temperature: 31.5, ThermalState: model.ThermalStateModerate, CreatedAt: 12345678,
+			}},
+			handler:      func(s *Server) echo.HandlerFunc { return s.createBatteryStateHandler },
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "unknown thermal state",
+			path: "/api/v1/resources/battery",
+			body: []model.NewBatteryStateDTO{{
+				Id: "0a4f2e7c-5d1b-4c3a-8e9f-1a2b3c4d5e05", SessionId: sessionId, InstallationId: installationId,
+				Level: 87, ThermalState: "hot", CreatedAt: 12345678,
+			}},
+			handler:      func(s *Server) echo.HandlerFunc { return s.createBatteryStateHandler },
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "network",
+			path: "/api/v1/resources/network",
+			body: []model.NewNetworkUsageDTO{{
+				Id: "0a4f2e7c-5d1b-4c3a-8e9f-1a2b3c4d5e06", SessionId: sessionId, InstallationId: installationId,
+				ConnectionType: "wifi", Requests: 20, FailedRequests: 2, BytesSent: 2048, BytesReceived: 65536, CreatedAt: 12345678,
+			}},
+			handler:      func(s *Server) echo.HandlerFunc { return s.createNetworkUsageHandler },
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "cold start",
+			path: "/api/v1/resources/starts",
+			body: []model.NewAppStartDTO{{
+				Id: "0a4f2e7c-5d1b-4c3a-8e9f-1a2b3c4d5e07", SessionId: sessionId, InstallationId: installationId,
+				StartType: model.StartTypeCold, Duration: 1200, ProcessDuration: 300, CreatedAt: 12345678,
+			}},
+			handler:      func(s *Server) echo.HandlerFunc { return s.createAppStartsHandler },
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "cold start without phases",
+			path: "/api/v1/resources/starts",
+			body: []model.NewAppStartDTO{{
+				Id: "0a4f2e7c-5d1b-4c3a-8e9f-1a2b3c4d5e08", SessionId: sessionId, InstallationId: installationId,
+				StartType: model.StartTypeCold, Duration: 1200, CreatedAt: 12345678,
+			}},
+			handler:      func(s *Server) echo.HandlerFunc { return s.createAppStartsHandler },
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "warm start with process phase",
+			path: "/api/v1/resources/starts",
+			body: []model.NewAppStartDTO{{
+				Id: "0a4f2e7c-5d1b-4c3a-8e9f-1a2b3c4d5e09", SessionId: sessionId, InstallationId: installationId,
+				StartType: model.StartTypeWarm, Duration: 600, ProcessDuration: 300, CreatedAt: 12345678,
+			}},
+			handler:      func(s *Server) echo.HandlerFunc { return s.createAppStartsHandler },
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(tt.body)
+			if err != nil {
+				t.Fatalf("Could not marshal body: %v", err)
+			}
+
+			e := echo.New()
+			e.Validator = NewValidator()
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewReader(body))
+			req.Header.Set("Content-type", "application/json")
+			resp := httptest.NewRecorder()
+			c := e.NewContext(req, resp)
+			c.Set("appId", appId)
+			s := &Server{
+				db: db,
+			}
+
+			err = tt.handler(s)(c)
+			if he, ok := err.(*echo.HTTPError); ok {
+				resp.Code = he.Code
+			} else if err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+
+			if resp.Code != tt.expectedCode {
+				t.Errorf("wrong status code. expected = %d, actual = %d", tt.expectedCode, resp.Code)
+			}
+		})
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/app/v1/sessions/:id/resources", nil)
+	resp := httptest.NewRecorder()
+	c := e.NewContext(req, resp)
+	c.SetParamNames("id")
+	c.SetParamValues(sessionId)
+	c.Set("session", model.AuthSessionEntity{UserId: userId})
+	s := &Server{
+		db: db,
+	}
+
+	if err := s.getSessionResourcesHandler(c); err != nil {
+		t.Fatalf("getSessionResourcesHandler() error = %v", err)
+	}
+
+	var actual struct {
+		Resources map[string][]map[string]any `json:"resources"`
+		Pages     map[string]model.PageDTO    `json:"pages"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&actual); err != nil {
+		t.Fatalf("getSessionResourcesHandler() error decoding response body: %v", err)
+	}
+	for _, key := range []string{"cpuUsage", "frameMetrics", "batteryState", "networkUsage", "appStarts"} {
+		if len(actual.Resources[key]) != 1 {
+			t.Errorf("Expected 1 of %s, got %v", key, actual.Resources[key])
+		}
+		if _, ok := actual.Pages[key]; !ok {
+			t.Errorf("Expected a page of %s", key)
+		}
+	}
+}
+
+func TestNetworkRequests(t *testing.T) {
+	sessionId := "6e2a9c41-3b7d-4f0e-a5c8-9d1e2f3a4b5c"
+	err := db.CreateSession(model.NewSessionData{
+		Id:             sessionId,
+		InstallationId: "dd72f2d8-c679-4e7c-bf6b-56f6ec78391b",
+		AppId:          appId,
+	})
+	if err != nil {
+		t.Fatalf("Could not create session: %v\n", err)
+	}
+
+	traceId := "5f0c2e8a-7b1d-4c9e-8a3f-2b4c6d8e0f1a"
+	createTests := []struct {
+		name         string
+		body         []model.NetworkRequestDTO
+		expectedCode int
+	}{
+		{
+			name: "valid",
+			body: []model.NetworkRequestDTO{
+				{
+					Id: "7a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c01", SessionId: sessionId, TraceId: traceId,
+					UrlTemplate: "https://api.example.com/users/42?expand=true", Method: "GET", StatusCode: 200,
+					Duration: 120, ResponseSize: 2048, StartedAt: 12345678,
+				},
+				{
+					Id: "7a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c02", SessionId: sessionId,
+					UrlTemplate: "https://api.example.com/users/43", Method: "GET", FailureReason: "timeout",
+					Duration: 10000, StartedAt: 12345679,
+				},
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "failed without reason",
+			body: []model.NetworkRequestDTO{{
+				Id: "7a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c03", SessionId: sessionId,
+				UrlTemplate: "https://api.example.com/users/44", Method: "GET", StartedAt: 12345680,
+			}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "unknown method",
+			body: []model.NetworkRequestDTO{{
+				Id: "7a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c04", SessionId: sessionId,
+				UrlTemplate: "https://api.example.com/users/45", Method: "FETCH", StatusCode: 200, StartedAt: 12345681,
+			}},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range createTests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(tt.body)
+			if err != nil {
+				t.Fatalf("Could not marshal body: %v", err)
+			}
+
+			e := echo.New()
+			e.Validator = NewValidator()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/network/requests", bytes.NewReader(body))
+			req.Header.Set("Content-type", "application/json")
+			resp := httptest.NewRecorder()
+			c := e.NewContext(req, resp)
+			c.Set("appId", appId)
+			s := &Server{
+				db: db,
+			}
+
+			err = s.createNetworkRequestsHandler(c)
+			if he, ok := err.(*echo.HTTPError); ok {
+				resp.Code = he.Code
+			} else if err != nil {
+				t.Fatalf("createNetworkRequestsHandler() error = %v", err)
+			}
+
+			if resp.Code != tt.expectedCode {
+				t.Errorf("createNetworkRequestsHandler() wrong status code. expected = %d, actual = %d", tt.expectedCode, resp.Code)
+			}
+		})
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/app/v1/sessions/:id/network?traceId="+traceId, nil)
+	resp := httptest.NewRecorder()
+	c := e.NewContext(req, resp)
+	c.SetParamNames("id")
+	c.SetParamValues(sessionId)
+	c.Set("session", model.AuthSessionEntity{UserId: userId})
+	s := &Server{
+		db: db,
+	}
+
+	if err := s.getSessionNetworkRequestsHandler(c); err != nil {
+		t.Fatalf("getSessionNetworkRequestsHandler() error = %v", err)
+	}
+
+	var actual struct {
+		NetworkRequests []model.NetworkRequestDTO `json:"networkRequests"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&actual); err != nil {
+		t.Fatalf("getSessionNetworkRequestsHandler() error decoding response body: %v", err)
+	}
+	if len(actual.NetworkRequests) != 1 || actual.NetworkRequests[0].UrlTemplate != "https://api.example.com/users/{id}" {
+		t.Errorf("getSessionNetworkRequestsHandler() got unexpected requests %+v", actual.NetworkRequests)
+	}
+
+	queryTests := []struct {
+		name         string
+		path         string
+		handler      func(s *Server) echo.HandlerFunc
+		expectedCode int
+	}{
+		{
+			name:         "endpoints",
+			path:         "/app/v1/apps/:id/network?host=api.example.com",
+			handler:      func(s *Server) echo.HandlerFunc { return s.getNetworkEndpointsHandler },
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "endpoint",
+			path:         "/app/v1/apps/:id/network/endpoint?method=GET&interval=hour&urlTemplate=" + url.QueryEscape("https://api.example.com/users/{id}"),
+			handler:      func(s *Server) echo.HandlerFunc { return s.getNetworkEndpointHandler },
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "endpoint without calls",
+			path:         "/app/v1/apps/:id/network/endpoint?method=DELETE&urlTemplate=" + url.QueryEscape("https://api.example.com/users/{id}"),
+			handler:      func(s *Server) echo.HandlerFunc { return s.getNetworkEndpointHandler },
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "endpoint without method",
+			path:         "/app/v1/apps/:id/network/endpoint?urlTemplate=" + url.QueryEscape("https://api.example.com/users/{id}"),
+			handler:      func(s *Server) echo.HandlerFunc { return s.getNetworkEndpointHandler },
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range queryTests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			s := &Server{
+				db: db,
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			resp := httptest.NewRecorder()
+			c := e.NewContext(req, resp)
+			c.SetParamNames("id")
+			c.SetParamValues(strconv.Itoa(appId))
+			c.Set("session", model.AuthSessionEntity{UserId: userId})
+
+			err := tt.handler(s)(c)
+			if he, ok := err.(*echo.HTTPError); ok {
+				resp.Code = he.Code
+			} else if err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+
+			if resp.Code != tt.expectedCode {
+				t.Errorf("wrong status code. expected = %d, actual = %d", tt.expectedCode, resp.Code)
+			}
+		})
+	}
+}
+
+func TestSearchEvents(t *testing.T) {
+	sessionId := "3c9d8e7f-6a5b-4c4d-9e3f-2a1b0c9d8e7f"
+	err := db.CreateSession(model.NewSessionData{
+		Id:             sessionId,
+		InstallationId: "dd72f2d8-c679-4e7c-bf6b-56f6ec78391b",
+		AppId:          appId,
+	})
+	if err != nil {
+		t.Fatalf("Could not create session: %v\n", err)
+	}
+	_, err = db.CreateEvents([]model.NewEventData{
+		{
+			Id: "1f2e3d4c-5b6a-4978-8695-a4b3c2d1e0f1", SessionId: sessionId, AppId: appId, Type: "search.click",
+			Attributes: map[string]any{"screen": "Checkout", "button": "pay", "amount": 12.5}, CreatedAt: 12345678,
+		},
+		{
+			Id: "1f2e3d4c-5b6a-4978-8695-a4b3c2d1e0f2", SessionId: sessionId, AppId: appId, Type: "search.click",
+			Attributes: map[string]any{"screen": "Checkout", "button": "cancel"}, CreatedAt: 12345679,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Could not create events: %v\n", err)
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		expectedCode int
+		expectedIds  []string
+	}{
+		{
+			name:         "attributes",
+			query:        "?type=search.click&q=" + url.QueryEscape("screen=Checkout AND button=pay"),
+			expectedCode: http.StatusOK,
+			expectedIds:  []string{"1f2e3d4c-5b6a-4978-8695-a4b3c2d1e0f1"},
+		},
+		{
+			name:         "number",
+			query:        "?type=search.click&q=" + url.QueryEscape("amount=12.5"),
+			expectedCode: http.StatusOK,
+			expectedIds:  []string{"1f2e3d4c-5b6a-4978-8695-a4b3c2d1e0f1"},
+		},
+		{
+			name:         "type only",
+			query:        "?type=search.click",
+			expectedCode: http.StatusOK,
+			expectedIds:  []string{"1f2e3d4c-5b6a-4978-8695-a4b3c2d1e0f1", "1f2e3d4c-5b6a-4978-8695-a4b3c2d1e0f2"},
+		},
+		{
+			name:         "invalid query",
+			query:        "?q=" + url.QueryEscape("screen=Checkout OR button=pay"),
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			s := &Server{
+				db: db,
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/app/v1/apps/:id/events/search"+tt.query, nil)
+			resp := httptest.NewRecorder()
+			c := e.NewContext(req, resp)
+			c.SetParamNames("id")
+			c.SetParamValues(strconv.Itoa(appId))
+			c.Set("session", model.AuthSessionEntity{UserId: userId})
+
+			err := s.searchEventsHandler(c)
+			if he, ok := err.(*echo.HTTPError); ok {
+				resp.Code = he.Code
+			} else if err != nil {
+				t.Fatalf("searchEventsHandler() error = %v", err)
+			}
+
+			if resp.Code != tt.expectedCode {
+				t.Fatalf("searchEventsHandler() wrong status code. expected = %d, actual = %d", tt.expectedCode, resp.Code)
+			}
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+
+			var actual struct {
+				Events []model.EventDTO `json:"events"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&actual); err != nil {
+				t.Fatalf("searchEventsHandler() error decoding response body: %v", err)
+			}
+			ids := make([]string, len(actual.Events))
+			for i, event := range actual.Events {
+				ids[i] = event.Id
+			}
+			if !reflect.DeepEqual(ids, tt.expectedIds) {
+				t.Errorf("searchEventsHandler() got events %v, expected %v", ids, tt.expectedIds)
+			}
+		})
+	}
+}
+
+func TestExportOTLPTraces(t *testing.T) {
+	sessionId := "9ef2a017-bfcd-4be2-9d24-afb0c1d2e3f4"
+	traceId := []byte{0x5b, 0x8e, 0xff, 0xf7, 0x98, 0x03, 0x81, 0x03, 0xd2, 0x69, 0xb6, 0x33, 0x81, 0x3f, 0xc6, 0x0c}
+	rootId := []byte{0xee, 0xe1, 0x9b, 0x7e, 0xc3, 0xc1, 0xb1, 0x74}
+	childId := []byte{0xee, 0xe1, 0x9b, 0x7e, 0xc3, 0xc1, 0xb1, 0x75}
+	noSessionTraceId := []byte{0x5b, 0x8e, 0xff, 0xf7, 0x98, 0x03, 0x81, 0x03, 0xd2, 0x69, 0xb6, 0x33, 0x81, 0x3f, 0xc6, 0x0e}
+
+	request := &pb.ExportTraceServiceRequest{
+		ResourceSpans: []*pb.ResourceSpans{
+			{
+				Resource: &pb.Resource{
+					Attributes: []*pb.KeyValue{
+						{Key: "service.name", Value: &pb.AnyValue{Value: &pb.AnyValue_StringValue{StringValue: "checkout"}}},
+						{Key: "session.id", Value: &pb.AnyValue{Value: &pb.AnyValue_StringValue{StringValue: sessionId}}},
+					},
+				},
+				ScopeSpans: []*pb.ScopeSpans{
+					{
+						Spans: []*pb.Span{
+							{
+								TraceId:           traceId,
+								SpanId:            rootId,
+								Name:              "GET /checkout",
+								StartTimeUnixNano: 1700000000000000000,
+								EndTimeUnixNano:   1700000001000000000,
+								Status:            &pb.Status{Code: 1},
+								Attributes: []*pb.KeyValue{
+									{Key: "http.route", Value: &pb.AnyValue{Value: &pb.AnyValue_StringValue{StringValue: "/checkout"}}},
+									{Key: "cart.items", Value: &pb.AnyValue{Value: &pb.AnyValue_IntValue{IntValue: 3}}},
+									{Key: "cart.tags", Value: &pb.AnyValue{Value: &pb.AnyValue_ArrayValue{ArrayValue: &pb.ArrayValue{}}}},
+								},
+							},
+							{
+								TraceId:           traceId,
+								SpanId:            childId,
+								ParentSpanId:      rootId,
+								Name:              "SELECT cart",
+								StartTimeUnixNano: 1700000000100000000,
+								EndTimeUnixNano:   1700000000200000000,
+								Status:            &pb.Status{Code: 2, Message: "timeout"},
+							},
+							{
+								SpanId:            []byte{0x01},
+								Name:              "Invalid",
+								StartTimeUnixNano: 1700000000100000000,
+							},
+						},
+					},
+				},
+			},
+			{
+				Resource: &pb.Resource{
+					Attributes: []*pb.KeyValue{
+						{Key: "service.name", Value: &pb.AnyValue{Value: &pb.AnyValue_StringValue{StringValue: "checkout"}}},
+					},
+				},
+				ScopeSpans: []*pb.ScopeSpans{
+					{
+						Spans: []*pb.Span{
+							{
+								TraceId:           noSessionTraceId,
+								SpanId:            []byte{0xee, 0xe1, 0x9b, 0x7e, 0xc3, 0xc1, 0xb1, 0x76},
+								Name:              "No session",
+								StartTimeUnixNano: 1700000000000000000,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	body, err := proto.Marshal(request)
+	if err != nil {
+		t.Fatalf("Could not marshal OTLP request: %v", err)
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces", bytes.NewReader(body))
+	req.Header.Set("Content-type", MIMEApplicationProtobuf)
+	resp := httptest.NewRecorder()
+	c := e.NewContext(req, resp)
+	c.Set("appId", appId)
+	s := &Server{
+		db: db,
+	}
+
+	if err := s.exportOTLPTracesHandler(c); err != nil {
+		t.Fatalf("exportOTLPTracesHandler() error = %v", err)
+	}
+	if resp.Code != http.StatusOK {
+		t.Fatalf("exportOTLPTracesHandler() wrong status code = %v", resp.Code)
+	}
+
+	var actual pb.ExportTraceServiceResponse
+	if err := proto.Unmarshal(resp.Body.Bytes(), &actual); err != nil {
+		t.Fatalf("exportOTLPTracesHandler() error decoding response body: %v", err)
+	}
+	if actual.PartialSuccess.GetRejectedSpans() != 2 {
+		t.Errorf("exportOTLPTracesHandler() wrong number of rejected spans. expected = 2, actual = %v", actual.PartialSuccess)
+	}
+
+	// The OTel trace id is not used as session
+	if _, err := db.GetSession(hex.EncodeToString(noSessionTraceId)); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected no session for span without session.id, error = %v", err)
+	}
+
+	traces, _, err := db.GetTracesBySessionId(sessionId, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetTracesBySessionId() error = %v", err)
+	}
+	if len(traces) != 2 {
+		t.Fatalf("Expected 2 traces but was %d", len(traces))
+	}
+
+	for _, trace := range traces {
+		if trace.GroupId != "5b8efff798038103d269b633813fc60c" {
+			t.Errorf("Wrong group id of trace %s: %s", trace.TraceId, trace.GroupId)
+		}
+
+		switch trace.TraceId {
+		case "eee19b7ec3c1b174":
+			if trace.ParentId != "" || trace.Status != "Ok" || trace.StartedAt != 1700000000000 || trace.EndedAt != 1700000001000 || !trace.HasEnded {
+				t.Errorf("Wrong root trace: %+v", trace)
+			}
+			// Only scalar attributes are kept
+			if len(trace.Attributes) != 2 || trace.Attributes["http.route"] != "/checkout" || trace.Attributes["cart.items"] != 3.0 {
+				t.Errorf("Wrong root trace attributes: %v", trace.Attributes)
+			}
+		case "eee19b7ec3c1b175":
+			if trace.ParentId != "eee19b7ec3c1b174" || trace.Status != "Error" || trace.ErrorMessage != "timeout" {
+				t.Errorf("Wrong child trace: %+v", trace)
+			}
+		default:
+			t.Errorf("Unexpected trace: %+v", trace)
+		}
+	}
+}
+
+func TestExportOTLPLogs(t *testing.T) {
+	sessionId := "aa03b128-cdde-4cf3-8e35-b0c1d2e3f405"
+	body := `{
+		"resourceLogs": [{
+			"resource": {
+				"attributes": [{"key": "service.name", "value": {"stringValue": "checkout"}}]
+			},
+			"scopeLogs": [{
+				"logRecords": [
+					{
+						"timeUnixNano": "1700000000000000000",
+						"severityNumber": 17,
+						"severityText": "ERROR",
+						"body": {"stringValue": "Payment failed"},
+						"attributes": [
+							{"key": "session.id", "value": {"stringValue": "` + sessionId + `"}},
+							{"key": "retries", "value": {"intValue": "3"}}
+						],
+						"traceId": "5b8efff798038103d269b633813fc60d",
+						"spanId": "eee19b7ec3c1b176"
+					},
+					{
+						"timeUnixNano": "1700000000000000000",
+						"body": {"stringValue": "No session"},
+						"traceId": "5b8efff798038103d269b633813fc60f"
+					}
+				]
+			}]
+		}]
+	}`
+
+	for _, attempt := range []string{"first", "retry"} {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/v1/logs", strings.NewReader(body))
+		req.Header.Set("Content-type", "application/json")
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.Set("appId", appId)
+		s := &Server{
+			db: db,
+		}
+
+		if err := s.exportOTLPLogsHandler(c); err != nil {
+			t.Fatalf("exportOTLPLogsHandler() %s error = %v", attempt, err)
+		}
+		if resp.Code != http.StatusOK {
+			t.Fatalf("exportOTLPLogsHandler() %s wrong status code = %v", attempt, resp.Code)
+		}
+
+		var actual map[string]map[string]any
+		if err := json.NewDecoder(resp.Body).Decode(&actual); err != nil {
+			t.Fatalf("exportOTLPLogsHandler() %s error decoding response body: %v", attempt, err)
+		}
+		if actual["partialSuccess"]["rejectedLogRecords"] != "1" {
+			t.Errorf("exportOTLPLogsHandler() %s wrong partial success: %v", attempt, actual)
+		}
+	}
+
+	events, _, err := db.GetEventsBySessionId(sessionId, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetEventsBySessionId() error = %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event but was %d", len(events))
+	}
+
+	if _, err := db.GetSession("5b8efff798038103d269b633813fc60f"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected no session for log record without session.id, error = %v", err)
+	}
+
+	event := events[0]
+	if event.Type != "otel.log" || event.CreatedAt != 1700000000000 {
+		t.Errorf("Wrong event: %+v", event)
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal([]byte(event.SerializedData), &data); err != nil {
+		t.Fatalf("Could not decode serialized data of event: %v", err)
+	}
+	if data["body"] != "Payment failed" || data["traceId"] != "5b8efff798038103d269b633813fc60d" || data["severityText"] != "ERROR" {
+		t.Errorf("Wrong serialized data of event: %v", data)
+	}
+}
+
+func TestExportOTLPSampledOut(t *testing.T) {
+	app, err := db.GetApplication(appId)
+	if err != nil {
+		t.Fatalf("Could not get application: %v", err)
+	}
+	sampledAppId, err := db.CreateApplication(model.NewApplicationData{Name: "Sampled OTLP app", TeamId: app.TeamId})
+	if err != nil {
+		t.Fatalf("Could not create application: %v", err)
+	}
+	err = db.SetIngestionSettings(model.IngestionSettingsData{
+		AppId:         sampledAppId,
+		SamplingRules: []model.SamplingRule{{Rate: 0}},
+		UpdatedBy:     userId,
+		UpdatedAt:     1700000000000,
+	})
+	if err != nil {
+		t.Fatalf("Could not set ingestion settings: %v", err)
+	}
+
+	sessionId := "ab25c34d-e6f7-4a08-9b19-2c3d4e5f6a7b"
+	body := `{
+		"resourceLogs": [{
+			"resource": {
+				"attributes": [{"key": "session.id", "value": {"stringValue": "` + sessionId + `"}}]
+			},
+			"scopeLogs": [{
+				"logRecords": [{"timeUnixNano": "1700000000000000000", "body": {"stringValue": "Sampled out"}}]
+			}]
+		}]
+	}`
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/logs", strings.NewReader(body))
+	req.Header.Set("Content-type", "application/json")
+	resp := httptest.NewRecorder()
+	c := e.NewContext(req, resp)
+	c.Set("appId", sampledAppId)
+	s := &Server{
+		db: db,
+	}
+
+	if err := s.exportOTLPLogsHandler(c); err != nil {
+		t.Fatalf("exportOTLPLogsHandler() error = %v", err)
+	}
+	if resp.Code != http.StatusOK {
+		t.Fatalf("exportOTLPLogsHandler() wrong status code = %v", resp.Code)
+	}
+	if strings.Contains(resp.Body.String(), "partialSuccess") {
+		t.Errorf("exportOTLPLogsHandler() sampled out records are not rejected: %s", resp.Body.String())
+	}
+
+	// No session is created for records dropped by sampling
+	if _, err := db.GetSession(sessionId); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected no session for sampled out records, error = %v", err)
+	}
+}
+
+func TestGetSessionTracesOTLP(t *testing.T) {
+	sessionId := "bb14c239-deef-4d04-9f46-c1d2e3f40516"
+	groupId := "cc25d34a-eff0-4e15-8a57-d2e3f4051627"
+	rootId := "dd36e45b-f001-4f26-9b68-e3f405162738"
+
+	err := db.CreateSession(model.NewSessionData{
+		Id:             sessionId,
+		InstallationId: "ee47f56c-0112-4037-8c79-f40516273849",
+		AppId:          appId,
+		CreatedAt:      1700000000000,
+	})
+	if err != nil {
+		t.Fatalf("Could not create session: %v", err)
+	}
+	_, err = db.CreateTraces([]model.NewTraceData{
+		{
+			TraceId:    rootId,
+			SessionId:  sessionId,
+			GroupId:    groupId,
+			AppId:      appId,
+			Name:       "Root",
+			Status:     "Ok",
+			StartedAt:  1700000000000,
+			EndedAt:    1700000001000,
+			HasEnded:   true,
+			Attributes: map[string]any{"screen": "Checkout", "items": 3},
+		},
+		{
+			TraceId:      "ff58067d-1223-4148-8d8a-0516273849a0",
+			SessionId:    sessionId,
+			GroupId:      groupId,
+			ParentId:     rootId,
+			AppId:        appId,
+			Name:         "Child",
+			Status:       "Error",
+			ErrorMessage: "Timeout",
+			StartedAt:    1700000000100,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Could not create traces: %v", err)
+	}
+
+	s := &Server{
+		db: db,
+	}
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/app/v1/sessions/"+sessionId+"/traces?format=otlp", nil)
+	req.Header.Set("Accept", MIMEApplicationProtobuf)
+	resp := httptest.NewRecorder()
+	c := e.NewContext(req, resp)
+	c.SetParamNames("id")
+	c.SetParamValues(sessionId)
+	c.Set("session", model.AuthSessionEntity{UserId: userId})
+
+	if err := s.getSessionTracesHandler(c); err != nil {
+		t.Fatalf("getSessionTracesHandler() error = %v", err)
+	}
+	if resp.Code != http.StatusOK {
+		t.Fatalf("getSessionTracesHandler() wrong status code = %v", resp.Code)
+	}
+
+	var export pb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(resp.Body.Bytes(), &export); err != nil {
+		t.Fatalf("getSessionTracesHandler() error decoding response body: %v", err)
+	}
+
+	spans := export.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans but was %d", len(spans))
+	}
+
+	spansByName := make(map[string]*pb.Span)
+	for _, span := range spans {
+		spansByName[span.Name] = span
+	}
+	root, child := spansByName["Root"], spansByName["Child"]
+	if root == nil || child == nil {
+		t.Fatalf("Missing spans in export: %v", spans)
+	}
+
+	expectedTraceId := "cc25d34aeff04e158a57d2e3f4051627"
+	if hex.EncodeToString(root.TraceId) != expectedTraceId || hex.EncodeToString(child.TraceId) != expectedTraceId {
+		t.Errorf("Wrong OTel trace ids: %x, %x", root.TraceId, child.TraceId)
+	}
+	if !bytes.Equal(child.ParentSpanId, root.SpanId) || len(root.SpanId) != 8 || len(root.ParentSpanId) != 0 {
+		t.Errorf("Wrong span ids. root = %x, child parent = %x", root.SpanId, child.ParentSpanId)
+	}
+	if root.StartTimeUnixNano != 1700000000000000000 || root.EndTimeUnixNano != 1700000001000000000 || child.EndTimeUnixNano != 0 {
+		t.Errorf("Wrong span times. root = %d-%d, child end = %d", root.StartTimeUnixNano, root.EndTimeUnixNano, child.EndTimeUnixNano)
+	}
+	if child.Status.GetCode() != 2 || child.Status.GetMessage() != "Timeout" || root.Status.GetCode() != 1 {
+		t.Errorf("Wrong span status. root = %v, child = %v", root.Status, child.Status)
+	}
+	attributes := otlpAttributes(root.Attributes)
+	if attributes["screen"] != "Checkout" || attributes["items"] != int64(3) || attributes["session.id"] != sessionId {
+		t.Errorf("Wrong span attributes: %v", attributes)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/app/v1/sessions/"+sessionId+"/traces?format=otlp", nil)
+	resp = httptest.NewRecorder()
+	c = e.NewContext(req, resp)
+	c.SetParamNames("id")
+	c.SetParamValues(sessionId)
+	c.Set("session", model.AuthSessionEntity{UserId: userId})
+
+	if err := s.getSessionTracesHandler(c); err != nil {
+		t.Fatalf("getSessionTracesHandler() error = %v", err)
+	}
+	if !strings.Contains(resp.Body.String(), `"traceId":"`+expectedTraceId+`"`) {
+		t.Errorf("getSessionTracesHandler() JSON export does not contain hex trace id: %s", resp.Body.String())
+	}
+
+	// An export is never paged
+	req = httptest.NewRequest(http.MethodGet, "/app/v1/sessions/"+sessionId+"/traces?format=otlp&limit=1", nil)
+	resp = httptest.NewRecorder()
+	c = e.NewContext(req, resp)
+	c.SetParamNames("id")
+	c.SetParamValues(sessionId)
+	c.Set("session", model.AuthSessionEntity{UserId: userId})
+
+	err = s.getSessionTracesHandler(c)
+	if httpErr, ok := err.(*echo.HTTPError); !ok || httpErr.Code != http.StatusBadRequest {
+		t.Errorf("getSessionTracesHandler() with limit expected bad request, error = %v", err)
+	}
+}
+
+func TestSessionCrash(t *testing.T) {
+	sessionId := "0a69178e-2334-4259-9e9b-16273849a0b1"
+	err := db.CreateSession(model.NewSessionData{
+		Id:             sessionId,
+		InstallationId: "1b7a289f-3445-426a-8fac-273849a0b1c2",
+		AppId:          appId,
+		CreatedAt:      1700000000000,
+	})
+	if err != nil {
+		t.Fatalf("Could not create session: %v", err)
+	}
+
+	crash := model.CrashDTO{
+		Id:             "2c8b39a0-4556-437b-90bd-3849a0b1c2d3",
+		ExceptionClass: "java.lang.NullPointerException",
+		Message:        "Attempt to invoke virtual method on a null object reference",
+		Frames: []model.StackFrameDTO{
+			{ClassName: "com.example.cart.CartActivity", MethodName: "onCreate", FileName: "CartActivity.kt", LineNumber: 42},
+		},
+		ThreadName: "main",
+		Screen:     "CartActivity",
+		CreatedAt:  1700000001000,
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode int
+	}{
+		{name: "crash report", body: mustMarshal(t, crash), expectedCode: http.StatusCreated},
+		{name: "replayed crash report", body: mustMarshal(t, crash), expectedCode: http.StatusCreated},
+		{name: "invalid crash report", body: `{"id": "not-a-uuid", "createdAt": 1}`, expectedCode: http.StatusBadRequest},
+		{name: "no body", body: "", expectedCode: http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = NewValidator()
+			e.Binder = NewBinder()
+			s := &Server{
+				db: db,
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/sessions/"+sessionId+"/crash", strings.NewReader(tt.body))
+			req.Header.Set("Content-type", "application/json")
+			resp := httptest.NewRecorder()
+			c := e.NewContext(req, resp)
+			c.SetParamNames("id")
+			c.SetParamValues(sessionId)
+			c.Set("appId", appId)
+
+			err := s.sessionCrashHandler(c)
+			if he, ok := err.(*echo.HTTPError); ok {
+				resp.Code = he.Code
+			} else if err != nil {
+				t.Fatalf("sessionCrashHandler() error = %v", err)
+			}
+
+			if resp.Code != tt.expectedCode {
+				t.Errorf("sessionCrashHandler() wrong status code. expected = %d, actual = %d", tt.expectedCode, resp.Code)
+			}
+		})
+	}
+
+	issues, err := db.GetIssuesByAppId(appId, "", model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetIssuesByAppId() error = %v", err)
+	}
+	fingerprint := crashFingerprint(model.IssueTypeCrash, crash.ExceptionClass, crash.Frames)
+	found := false
+	for _, issue := range issues {
+		if issue.Fingerprint == fingerprint {
+			found = true
+			if issue.Occurrences != 1 || issue.Sessions != 1 {
+				t.Errorf("Wrong counts of issue: %+v", issue)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("No issue was created for the crash")
+	}
+}
+
+func TestSessionAnr(t *testing.T) {
+	sessionId := "6a0cdef4-899a-4bbf-a4f1-7c2d3e4f5061"
+	err := db.CreateSession(model.NewSessionData{
+		Id:             sessionId,
+		InstallationId: "7b1def05-9aab-4cc0-b502-8d3e4f506172",
+		AppId:          appId,
+		AppVersion:     "3.0.0",
+		CreatedAt:      1700000000000,
+	})
+	if err != nil {
+		t.Fatalf("Could not create session: %v", err)
+	}
+
+	anr := model.AnrDTO{
+		Id:       "8c2ef016-abbc-4dd1-8613-9e4f50617283",
+		Duration: 5200,
+		MainThread: []model.StackFrameDTO{
+			{ClassName: "java.io.FileInputStream", MethodName: "read", LineNumber: 320},
+			{ClassName: "com.example.cart.CartRepository", MethodName: "load", FileName: "CartRepository.kt", LineNumber: 18},
+		},
+		Threads: []model.ThreadDTO{
+			{Name: "DefaultDispatcher-worker-1", State: "WAITING"},
+		},
+		AppVersion: "3.0.0",
+		CreatedAt:  1700000002000,
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode int
+	}{
+		{name: "anr report", body: mustMarshal(t, anr), expectedCode: http.StatusCreated},
+		{name: "replayed anr report", body: mustMarshal(t, anr), expectedCode: http.StatusCreated},
+		{name: "missing main thread", body: `{"id": "9d3f0127-bccd-4ee2-9724-af5061728394", "createdAt": 1}`, expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = NewValidator()
+			e.Binder = NewBinder()
+			s := &Server{
+				db: db,
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/sessions/"+sessionId+"/anr", strings.NewReader(tt.body))
+			req.Header.Set("Content-type", "application/json")
+			resp := httptest.NewRecorder()
+			c := e.NewContext(req, resp)
+			c.SetParamNames("id")
+			c.SetParamValues(sessionId)
+			c.Set("appId", appId)
+
+			err := s.sessionAnrHandler(c)
+			if he, ok := err.(*echo.HTTPError); ok {
+				resp.Code = he.Code
+			} else if err != nil {
+				t.Fatalf("sessionAnrHandler() error = %v", err)
+			}
+
+			if resp.Code != tt.expectedCode {
+				t.Errorf("sessionAnrHandler() wrong status code. expected = %d, actual = %d", tt.expectedCode, resp.Code)
+			}
+		})
+	}
+
+	fingerprint := crashFingerprint(model.IssueTypeAnr, "", anr.MainThread)
+	anrs, _, err := db.GetAnrsByIssue(appId, fingerprint, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetAnrsByIssue() error = %v", err)
+	}
+	if len(anrs) != 1 || anrs[0].Duration != anr.Duration {
+		t.Errorf("Expected the ANR to be stored once, got %+v", anrs)
+	}
+
+	rates, err := db.GetAnrRates(appId, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetAnrRates() error = %v", err)
+	}
+	found := false
+	for _, rate := range rates {
+		if rate.AppVersion == "3.0.0" {
+			found = rate.AnrSessions == 1 && rate.Anrs == 1
+		}
+	}
+	if !found {
+		t.Errorf("Expected version 3.0.0 to have 1 ANR, got %+v", rates)
+	}
+}
+
+func TestGetStability(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		expectedCode int
+	}{
+		{name: "default interval", query: "", expectedCode: http.StatusOK},
+		{name: "breakdown", query: "?interval=week&groupBy=sdkVersion", expectedCode: http.StatusOK},
+		{name: "unknown interval", query: "?interval=year", expectedCode: http.StatusBadRequest},
+		{name: "invalid attribute", query: "?groupBy=brand'--", expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			s := &Server{
+				db: db,
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/app/v1/apps/:id/stability"+tt.query, nil)
+			resp := httptest.NewRecorder()
+			c := e.NewContext(req, resp)
+			c.SetParamNames("id")
+			c.SetParamValues(strconv.Itoa(appId))
+			c.Set("session", model.AuthSessionEntity{UserId: userId})
+
+			err := s.getStabilityHandler(c)
+			if he, ok := err.(*echo.HTTPError); ok {
+				resp.Code = he.Code
+			} else if err != nil {
+				t.Fatalf("getStabilityHandler() error = %v", err)
+			}
+
+			if resp.Code != tt.expectedCode {
+				t.Errorf("getStabilityHandler() wrong status code. expected = %d, actual = %d", tt.expectedCode, resp.Code)
+			}
+		})
+	}
+}
+
+func TestGetMemorySummary(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		expectedCode int
+	}{
+		{name: "default breakdown", query: "", expectedCode: http.StatusOK},
+		{name: "custom breakdown", query: "?interval=hour&groupBy=sdkVersion&release=1.0.0", expectedCode: http.StatusOK},
+		{name: "unknown interval", query: "?interval=year", expectedCode: http.StatusBadRequest},
+		{name: "invalid attribute", query: "?groupBy=model'--", expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			s := &Server{
+				db: db,
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/app/v1/apps/:id/resources/memory/summary"+tt.query, nil)
+			resp := httptest.NewRecorder()
+			c := e.NewContext(req, resp)
+			c.SetParamNames("id")
+			c.SetParamValues(strconv.Itoa(appId))
+			c.Set("session", model.AuthSessionEntity{UserId: userId})
+
+			err := s.getMemorySummaryHandler(c)
+			if he, ok := err.(*echo.HTTPError); ok {
+				resp.Code = he.Code
+			} else if err != nil {
+				t.Fatalf("getMemorySummaryHandler() error = %v", err)
+			}
+
+			if resp.Code != tt.expectedCode {
+				t.Errorf("getMemorySummaryHandler() wrong status code. expected = %d, actual = %d", tt.expectedCode, resp.Code)
+			}
+		})
+	}
+}
+
+func TestGetMemoryLeaks(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		expectedCode int
+	}{
+		{name: "default min samples", query: "", expectedCode: http.StatusOK},
+		{name: "custom min samples", query: "?minSamples=10&limit=5", expectedCode: http.StatusOK},
+		{name: "too few samples", query: "?minSamples=1", expectedCode: http.StatusBadRequest},
+		{name: "invalid min samples", query: "?minSamples=many", expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			s := &Server{
+				db: db,
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/app/v1/apps/:id/resources/memory/leaks"+tt.query, nil)
+			resp := httptest.NewRecorder()
+			c := e.NewContext(req, resp)
+			c.SetParamNames("id")
+			c.SetParamValues(strconv.Itoa(appId))
+			c.Set("session", model.AuthSessionEntity{UserId: userId})
+
+			err := s.getMemoryLeaksHandler(c)
+			if he, ok := err.(*echo.HTTPError); ok {
+				resp.Code = he.Code
+			} else if err != nil {
+				t.Fatalf("getMemoryLeaksHandler() error = %v", err)
+			}
+
+			if resp.Code != tt.expectedCode {
+				t.Errorf("getMemoryLeaksHandler() wrong status code. expected = %d, actual = %d", tt.expectedCode, resp.Code)
+			}
+		})
+	}
+}
+
+func TestGetStartupSummary(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		expectedCode int
+	}{
+		{name: "default type", query: "", expectedCode: http.StatusOK},
+		{name: "warm by model", query: "?type=warm&groupBy=model&interval=week", expectedCode: http.StatusOK},
+		{name: "unknown type", query: "?type=lukewarm", expectedCode: http.StatusBadRequest},
+		{name: "invalid group by", query: "?groupBy=app%20version", expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			s := &Server{
+				db: db,
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/app/v1/apps/:id/resources/starts"+tt.query, nil)
+			resp := httptest.NewRecorder()
+			c := e.NewContext(req, resp)
+			c.SetParamNames("id")
+			c.SetParamValues(strconv.Itoa(appId))
+			c.Set("session", model.AuthSessionEntity{UserId: userId})
+
+			err := s.getStartupSummaryHandler(c)
+			if he, ok := err.(*echo.HTTPError); ok {
+				resp.Code = he.Code
+			} else if err != nil {
+				t.Fatalf("getStartupSummaryHandler() error = %v", err)
+			}
+
+			if resp.Code != tt.expectedCode {
+				t.Errorf("getStartupSummaryHandler() wrong status code. expected = %d, actual = %d", tt.expectedCode, resp.Code)
+			}
+		})
+	}
+}
+
+func TestUploadMapping(t *testing.T) {
+	content := `com.example.cart.CartActivity -> a.a:
+    1:3:void onCreate(android.os.Bundle):40:42 -> a
+com.example.cart.EmptyCartException -> a.b:
+`
+
+	tests := []struct {
+		name         string
+		version      string
+		content      string
+		maxBytes     int64
+		expectedCode int
+	}{
+		{name: "valid mapping", version: "2.0.0", content: content, maxBytes: 1024, expectedCode: http.StatusCreated},
+		{name: "missing version", version: "", content: content, maxBytes: 1024, expectedCode: http.StatusBadRequest},
+		{name: "malformed mapping", version: "2.0.1", content: "not a mapping\n", maxBytes: 1024, expectedCode: http.StatusBadRequest},
+		{name: "too large", version: "2.0.2", content: content, maxBytes: 16, expectedCode: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			w := multipart.NewWriter(&body)
+			w.WriteField("version", tt.version)
+			part, err := w.CreateFormFile("mapping", "mapping.txt")
+			if err != nil {
+				t.Fatalf("Could not create form file: %v", err)
+			}
+			part.Write([]byte(tt.content))
+			w.Close()
+
+			e := echo.New()
+			s := &Server{
+				db:              db,
+				maxMappingBytes: tt.maxBytes,
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/app/v1/apps/:id/mappings", &body)
+			req.Header.Set("Content-type", w.FormDataContentType())
+			resp := httptest.NewRecorder()
+			c := e.NewContext(req, resp)
+			c.SetParamNames("id")
+			c.SetParamValues(strconv.Itoa(appId))
+			c.Set("session", model.AuthSessionEntity{UserId: userId})
+
+			err = s.uploadMappingHandler(c)
+			if he, ok := err.(*echo.HTTPError); ok {
+				resp.Code = he.Code
+			} else if err != nil {
+				t.Fatalf("uploadMappingHandler() error = %v", err)
+			}
+
+			if resp.Code != tt.expectedCode {
+				t.Errorf("uploadMappingHandler() wrong status code. expected = %d, actual = %d", tt.expectedCode, resp.Code)
+			}
+		})
+	}
+
+	sessionId := "3d9c4ab1-5667-448c-a1ce-49a0b1c2d3e4"
+	err := db.CreateSession(model.NewSessionData{
+		Id:             sessionId,
+		InstallationId: "4eadbcd2-6778-459d-b2df-5a0b1c2d3e4f",
+		AppId:          appId,
+		CreatedAt:      1700000000000,
+	})
+	if err != nil {
+		t.Fatalf("Could not create session: %v", err)
+	}
+
+	s := &Server{db: db}
+	err = s.createCrash(appId, model.CrashDTO{
+		Id:             "5fbecde3-7889-46ae-93e0-6b1c2d3e4f50",
+		SessionId:      sessionId,
+		ExceptionClass: "a.b",
+		Frames:         []model.StackFrameDTO{{ClassName: "a.a", MethodName: "a", FileName: "SourceFile", LineNumber: 2}},
+		AppVersion:     "2.0.0",
+		CreatedAt:      1700000001000,
+	})
+	if err != nil {
+		t.Fatalf("createCrash() error = %v", err)
+	}
+
+	expectedFrames := []model.StackFrameDTO{{ClassName: "com.example.cart.CartActivity", MethodName: "onCreate", FileName: "SourceFile", LineNumber: 41}}
+	fingerprint := crashFingerprint(model.IssueTypeCrash, "com.example.cart.EmptyCartException", expectedFrames)
+	crashes, _, err := db.GetCrashesByIssue(appId, fingerprint, model.PageQuery{})
+	if err != nil {
+		t.Fatalf("GetCrashesByIssue() error = %v", err)
+	}
+	if len(crashes) != 1 || !crashes[0].Deobfuscated || !reflect.DeepEqual(crashes[0].Frames, expectedFrames) {
+		t.Errorf("Expected crash to be deobfuscated and grouped by its original names, got %+v", crashes)
+	}
+}
+
+func mustMarshal(t *testing.T, v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Could not marshal %T: %v", v, err)
+	}
+	return string(data)
+}
+
+func TestTeamRoles(t *testing.T) {
+	app, err := db.GetApplication(appId)
+	if err != nil {
+		t.Fatalf("Could not get test application: %v", err)
+	}
+
+	members := make(map[string]int)
+	for _, role := range []string{model.RoleAdmin, model.RoleDeveloper, model.RoleViewer} {
+		id, err := db.CreateUser(model.NewUserData{Name: "Test " + role, PasswordHash: "-"})
+		if err != nil {
+			t.Fatalf("Could not create test user: %v", err)
+		}
+		err = db.CreateTeamUserLink(model.NewTeamUserLinkData{TeamId: app.TeamId, UserId: id, Role: role})
+		if err != nil {
+			t.Fatalf("Could not link test user to team: %v", err)
+		}
+		members[role] = id
+	}
+	members[model.RoleOwner] = userId
+	outsider, err := db.CreateUser(model.NewUserData{Name: "Test outsider", PasswordHash: "-"})
+	if err != nil {
+		t.Fatalf("Could not create test user: %v", err)
+	}
+	added := 0
+	newUser := func(t *testing.T) int {
+		added++
+		id, err := db.CreateUser(model.NewUserData{Name: "Test new member " + strconv.Itoa(added), PasswordHash: "-"})
+		if err != nil {
+			t.Fatalf("Could not create test user: %v", err)
+		}
+		return id
+	}
+
+	s := &Server{
+		db: db,
+	}
+	e := echo.New()
+	e.Validator = NewValidator()
+
+	keyTests := []struct {
+		name         string
+		userId       int
+		expectedCode int
+	}{
+		{name: "owner creates key", userId: members[model.RoleOwner], expectedCode: http.StatusCreated},
+		{name: "admin creates key", userId: members[model.RoleAdmin], expectedCode: http.StatusCreated},
+		{name: "developer creates key", userId: members[model.RoleDeveloper], expectedCode: http.StatusForbidden},
+		{name: "viewer creates key", userId: members[model.RoleViewer], expectedCode: http.StatusForbidden},
+		{name: "outsider creates key", userId: outsider, expectedCode: http.StatusUnauthorized},
+	}
+	for _, tt := range keyTests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/app/v1/apps/:id/keys", nil)
+			resp := httptest.NewRecorder()
+			c := e.NewContext(req, resp)
+			c.SetParamNames("id")
+			c.SetParamValues(strconv.Itoa(appId))
+			c.Set("session", model.AuthSessionEntity{UserId: tt.userId})
+
+			err := s.createKeyHandler(c)
+			if he, ok := err.(*echo.HTTPError); ok {
+				resp.Code = he.Code
+			} else if err != nil {
+				t.Fatalf("createKeyHandler() error = %v", err)
+			}
+			if resp.Code != tt.expectedCode {
+				t.Errorf("createKeyHandler() wrong status code. expected = %d, actual = %d", tt.expectedCode, resp.Code)
+			}
+		})
+	}
+
+	linkTests := []struct {
+		name         string
+		userId       int
+		role         string
+		expectedCode int
+	}{
+		{name: "owner adds owner", userId: members[model.RoleOwner], role: model.RoleOwner, expectedCode: http.StatusCreated},
+		{name: "admin adds viewer", userId: members[model.RoleAdmin], role: model.RoleViewer, expectedCode: http.StatusCreated},
+		{name: "admin adds owner", userId: members[model.RoleAdmin], role: model.RoleOwner, expectedCode: http.StatusForbidden},
+		{name: "developer adds viewer", userId: members[model.RoleDeveloper], role: model.RoleViewer, expectedCode: http.StatusForbidden},
+		{name: "viewer adds viewer", userId: members[model.RoleViewer], role: model.RoleViewer, expectedCode: http.StatusForbidden},
+		{name: "outsider adds viewer", userId: outsider, role: model.RoleViewer, expectedCode: http.StatusUnauthorized},
+		{name: "unknown role", userId: members[model.RoleOwner], role: "User", expectedCode: http.StatusBadRequest},
+	}
+	for _, tt := range linkTests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := mustMarshal(t, map[string]any{"userId": newUser(t), "role": tt.role})
+			req := httptest.NewRequest(http.MethodPost, "/app/v1/teams/:id/users", strings.NewReader(body))
+			req.Header.Set("Content-type", "application/json")
+			resp := httptest.NewRecorder()
+			c := e.NewContext(req, resp)
+			c.SetParamNames("id")
+			c.SetParamValues(strconv.Itoa(app.TeamId))
+			c.Set("session", model.AuthSessionEntity{UserId: tt.userId})
+
+			err := s.createTeamUserLinkHandler(c)
+			if he, ok := err.(*echo.HTTPError); ok {
+				resp.Code = he.Code
+			} else if err != nil {
+				t.Fatalf("createTeamUserLinkHandler() error = %v", err)
+			}
+			if resp.Code != tt.expectedCode {
+				t.Errorf("createTeamUserLinkHandler() wrong status code. expected = %d, actual = %d", tt.expectedCode, resp.Code)
+			}
+		})
+	}
+
+	// Viewers can still read the data of the app
+	req := httptest.NewRequest(http.MethodGet, "/app/v1/apps/:id", nil)
+	resp := httptest.NewRecorder()
+	c := e.NewContext(req, resp)
+	c.SetParamNames("id")
+	c.SetParamValues(strconv.Itoa(appId))
+	c.Set("session", model.AuthSessionEntity{UserId: members[model.RoleViewer]})
+	if err := s.getAppDataHandler(c); err != nil {
+		t.Fatalf("getAppDataHandler() error = %v", err)
+	}
+	if resp.Code != http.StatusOK {
+		t.Errorf("getAppDataHandler() wrong status code. expected = %d, actual = %d", http.StatusOK, resp.Code)
+	}
+}
+
+func TestApiKeys(t *testing.T) {
+	s := &Server{
+		db: db,
+	}
+	e := echo.New()
+	e.Validator = NewValidator()
+
+	call := func(t *testing.T, handler echo.HandlerFunc, method, body string, params ...string) (int, map[string]any) {
+		req := httptest.NewRequest(method, "/app/v1/apps/:id/keys", strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-type", "application/json")
+		}
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetParamNames("id", "keyId")
+		c.SetParamValues(append([]string{strconv.Itoa(appId)}, params...)...)
+		c.Set("session", model.AuthSessionEntity{UserId: userId})
+
+		err := handler(c)
+		if he, ok := err.(*echo.HTTPError); ok {
+			return he.Code, nil
+		} else if err != nil {
+			t.Fatalf("handler error = %v", err)
+		}
+		var actual map[string]any
+		if err := json.NewDecoder(resp.Body).Decode(&actual); err != nil {
+			t.Fatalf("Could not decode response: %v", err)
+		}
+		return resp.Code, actual
+	}
+
+	code, _ := call(t, s.createKeyHandler, http.MethodPost, `{"name":"ci","expiresAt":1}`)
+	if code != http.StatusBadRequest {
+		t.Errorf("createKeyHandler() with expiry in the past wrong status code = %d", code)
+	}
+
+	code, created := call(t, s.createKeyHandler, http.MethodPost, `{"name":"ci"}`)
+	if code != http.StatusCreated {
+		t.Fatalf("createKeyHandler() wrong status code = %d", code)
+	}
+	keyId := strconv.Itoa(int(created["id"].(float64)))
+	key := created["key"].(string)
+
+	code, rotated := call(t, s.rotateKeyHandler, http.MethodPost, `{"gracePeriod":3600}`, keyId)
+	if code != http.StatusCreated {
+		t.Fatalf("rotateKeyHandler() wrong status code = %d", code)
+	}
+	newKeyId := strconv.Itoa(int(rotated["id"].(float64)))
+	_, oldErr := db.GetApiKey(auth.HashApiKey(key), time.Now().UnixMilli())
+	_, rotatedErr := db.GetApiKey(auth.HashApiKey(rotated["key"].(string)), time.Now().UnixMilli())
+	if oldErr != nil || rotatedErr != nil {
+		t.Errorf("Expected both keys to work during the grace period")
+	}
+
+	code, _ = call(t, s.revokeKeyHandler, http.MethodDelete, "", keyId)
+	if code != http.StatusOK {
+		t.Fatalf("revokeKeyHandler() wrong status code = %d", code)
+	}
+	if _, err := db.GetApiKey(auth.HashApiKey(key), time.Now().UnixMilli()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected revoked key to stop working")
+	}
+	code, _ = call(t, s.revokeKeyHandler, http.MethodDelete, "", keyId)
+	if code != http.StatusNotFound {
+		t.Errorf("revokeKeyHandler() of revoked key wrong status code = %d", code)
+	}
+
+	code, listed := call(t, s.getKeysHandler, http.MethodGet, "")
+	if code != http.StatusOK {
+		t.Fatalf("getKeysHandler() wrong status code = %d", code)
+	}
+	statuses := make(map[string]string)
+	for _, k := range listed["keys"].([]any) {
+		dto := k.(map[string]any)
+		if _, ok := dto["key"]; ok {
+			t.Errorf("Expected listed keys to not include the key itself")
+		}
+		statuses[strconv.Itoa(int(dto["id"].(float64)))] = dto["status"].(string)
+	}
+	if statuses[keyId] != model.ApiKeyRevoked || statuses[newKeyId] != model.ApiKeyActive {
+		t.Errorf("Expected rotated key to be revoked and new key active, got %v", statuses)
+	}
+}
+
+func TestApiKeyScopes(t *testing.T) {
+	otherTeamId, err := db.CreateTeam(model.NewTeamData{Name: "Other team"})
+	if err != nil {
+		t.Fatalf("Could not create team: %v", err)
+	}
+	otherAppId, err := db.CreateApplication(model.NewApplicationData{Name: "Other app", TeamId: otherTeamId})
+	if err != nil {
+		t.Fatalf("Could not create application: %v", err)
+	}
+
+	newKey := func(t *testing.T, appId int, scopes ...string) string {
+		key, err := auth.GenerateApiKey()
+		if err != nil {
+			t.Fatalf("Could not generate api key: %v", err)
+		}
+		_, err = db.CreateApiKey(model.NewApiKeyData{
+			Key:       auth.HashApiKey(key),
+			AppId:     appId,
+			Scopes:    scopes,
+			CreatedAt: 1700000000000,
+		})
+		if err != nil {
+			t.Fatalf("Could not create api key: %v", err)
+		}
+		return key
+	}
+
+	s := &Server{
+		db: db,
+	}
+	okHandler := func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+	serve := func(t *testing.T, handler echo.HandlerFunc, method, key string) int {
+		e := echo.New()
+		req := httptest.NewRequest(method, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+key)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(appId))
+
+		err := handler(c)
+		if he, ok := err.(*echo.HTTPError); ok {
+			return he.Code
+		} else if err != nil {
+			t.Fatalf("handler error = %v", err)
+		}
+		return resp.Code
+	}
+
+	tests := []struct {
+		name         string
+		handler      echo.HandlerFunc
+		method       string
+		key          string
+		expectedCode int
+	}{
+		{name: "ingest key ingests events", handler: s.APIKeyMiddleware(ingestScope("events")(okHandler)), method: http.MethodPost, key: newKey(t, appId, model.ScopeIngest), expectedCode: http.StatusOK},
+		{name: "events key ingests events", handler: s.APIKeyMiddleware(ingestScope("events")(okHandler)), method: http.MethodPost, key: newKey(t, appId, "ingest:events"), expectedCode: http.StatusOK},
+		{name: "events key ingests traces", handler: s.APIKeyMiddleware(ingestScope("traces")(okHandler)), method: http.MethodPost, key: newKey(t, appId, "ingest:events"), expectedCode: http.StatusForbidden},
+		{name: "read key ingests events", handler: s.APIKeyMiddleware(ingestScope("events")(okHandler)), method: http.MethodPost, key: newKey(t, appId, model.ScopeRead), expectedCode: http.StatusForbidden},
+		{name: "read key reads app", handler: s.AppAuthMiddleware(s.getAppDataHandler), method: http.MethodGet, key: newKey(t, appId, model.ScopeRead), expectedCode: http.StatusOK},
+		{name: "read key reads other app", handler: s.AppAuthMiddleware(s.getAppDataHandler), method: http.MethodGet, key: newKey(t, otherAppId, model.ScopeRead), expectedCode: http.StatusUnauthorized},
+		{name: "read key lists teams", handler: s.AppAuthMiddleware(s.getTeamsHandler), method: http.MethodGet, key: newKey(t, appId, model.ScopeRead), expectedCode: http.StatusForbidden},
+		{name: "read key creates key", handler: s.AppAuthMiddleware(s.createKeyHandler), method: http.MethodPost, key: newKey(t, appId, model.ScopeRead), expectedCode: http.StatusUnauthorized},
+		{name: "ingest key reads app", handler: s.AppAuthMiddleware(s.getAppDataHandler), method: http.MethodGet, key: newKey(t, appId, model.ScopeIngest), expectedCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := serve(t, tt.handler, tt.method, tt.key); code != tt.expectedCode {
+				t.Errorf("wrong status code. expected = %d, actual = %d", tt.expectedCode, code)
+			}
+		})
+	}
+}
+
+func TestRateLimits(t *testing.T) {
+	rateTeamId, err := db.CreateTeam(model.NewTeamData{Name: "Rate limited team"})
+	if err != nil {
+		t.Fatalf("Could not create team: %v", err)
+	}
+	rateAppId, err := db.CreateApplication(model.NewApplicationData{Name: "Rate limited app", TeamId: rateTeamId})
+	if err != nil {
+		t.Fatalf("Could not create application: %v", err)
+	}
+	key, err := auth.GenerateApiKey()
+	if err != nil {
+		t.Fatalf("Could not generate api key: %v", err)
+	}
+	_, err = db.CreateApiKey(model.NewApiKeyData{
+		Key:       auth.HashApiKey(key),
+		AppId:     rateAppId,
+		Scopes:    []string{model.ScopeIngest},
+		CreatedAt: 1700000000000,
+	})
+	if err != nil {
+		t.Fatalf("Could not create api key: %v", err)
+	}
+
+	s := &Server{
+		db:                db,
+		limiter:           ratelimit.NewMemory(),
+		keyLimit:          ratelimit.LimitPerMinute(1, 4),
+		installationLimit: ratelimit.LimitPerMinute(1, 1),
+		throttled:         newThrottleCounter(),
+	}
+	handler := s.APIKeyMiddleware(s.RateLimitMiddleware(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}))
+	serve := func(t *testing.T, installationId string) (int, string) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+key)
+		if installationId != "" {
+			req.Header.Set(installationIdHeader, installationId)
+		}
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		err := handler(c)
+		if he, ok := err.(*echo.HTTPError); ok {
+			return he.Code, resp.Header().Get("Retry-After")
+		} else if err != nil {
+			t.Fatalf("handler error = %v", err)
+		}
+		return resp.Code, resp.Header().Get("Retry-After")
+	}
+
+	tests := []struct {
+		name           string
+		installationId string
+		expectedCode   int
+	}{
+		{name: "first request of installation", installationId: "a", expectedCode: http.StatusOK},
+		{name: "installation over its limit", installationId: "a", expectedCode: http.StatusTooManyRequests},
+		{name: "other installation", installationId: "b", expectedCode: http.StatusOK},
+		{name: "request without installation", expectedCode: http.StatusOK},
+		{name: "key over its limit", installationId: "c", expectedCode: http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, retryAfter := serve(t, tt.installationId)
+			if code != tt.expectedCode {
+				t.Errorf("wrong status code. expected = %d, actual = %d", tt.expectedCode, code)
+			}
+			if code == http.StatusTooManyRequests && retryAfter != "60" {
+				t.Errorf("Retry-After = '%s', expected '60'", retryAfter)
+			}
+		})
+	}
+
+	if err := s.FlushThrottled(); err != nil {
+		t.Fatalf("FlushThrottled failed: %v", err)
+	}
+	throttled, err := db.GetThrottledRequests(rateAppId, model.ThrottledRequestsQuery{})
+	if err != nil {
+		t.Fatalf("GetThrottledRequests failed: %v", err)
+	}
+	expected := []model.ThrottledRequestsEntity{
+		{Reason: model.ThrottleReasonInstallation, Count: 1},
+		{Reason: model.ThrottleReasonKey, Count: 1},
+	}
+	if !reflect.DeepEqual(throttled, expected) {
+		t.Errorf("Throttled requests = %+v, expected %+v", throttled, expected)
+	}
+}
+
+func TestIngestionSettings(t *testing.T) {
+	app, err := db.GetApplication(appId)
+	if err != nil {
+		t.Fatalf("Could not get application: %v", err)
+	}
+	quotaAppId, err := db.CreateApplication(model.NewApplicationData{Name: "Quota app", TeamId: app.TeamId})
+	if err != nil {
+		t.Fatalf("Could not create application: %v", err)
+	}
+
+	crashedSessionId := "5a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"
+	sessionId := "6b2c3d4e-5f6a-4b7c-9d8e-0f1a2b3c4d5e"
+	for _, id := range []string{crashedSessionId, sessionId} {
+		err := db.CreateSession(model.NewSessionData{
+			Id:             id,
+			InstallationId: "7c3d4e5f-6a7b-4c8d-8e9f-1a2b3c4d5e6f",
+			AppId:          quotaAppId,
+			CreatedAt:      1700000000000,
+			Crashed:        id == crashedSessionId,
+		})
+		if err != nil {
+			t.Fatalf("Could not create session: %v", err)
+		}
+	}
+
+	s := &Server{
+		db: db,
+	}
+	e := echo.New()
+	e.Validator = NewValidator()
+
+	call := func(t *testing.T, handler echo.HandlerFunc, method, body string) (int, map[string]any) {
+		req := httptest.NewRequest(method, "/", strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-type", "application/json")
+		}
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(quotaAppId))
+		c.Set("session", model.AuthSessionEntity{UserId: userId})
+		c.Set("appId", quotaAppId)
+
+		err := handler(c)
+		if he, ok := err.(*echo.HTTPError); ok {
+			return he.Code, nil
+		} else if err != nil {
+			t.Fatalf("handler error = %v", err)
+		}
+		var actual map[string]any
+		if err := json.NewDecoder(resp.Body).Decode(&actual); err != nil {
+			t.Fatalf("Could not decode response: %v", err)
+		}
+		return resp.Code, actual
+	}
+
+	code, _ := call(t, s.updateIngestionSettingsHandler, http.MethodPut, `{"samplingRules":[{"rate":2}]}`)
+	if code != http.StatusBadRequest {
+		t.Errorf("updateIngestionSettingsHandler() with invalid rate wrong status code = %d", code)
+	}
+
+	code, updated := call(t, s.updateIngestionSettingsHandler, http.MethodPut, `{"monthlyEventQuota":2,"samplingRules":[{"crashed":true,"rate":1},{"rate":0}]}`)
+	if code != http.StatusOK {
+		t.Fatalf("updateIngestionSettingsHandler() wrong status code = %d", code)
+	}
+	if settings := updated["settings"].(map[string]any); settings["monthlyEventQuota"] != float64(2) || len(settings["samplingRules"].([]any)) != 2 {
+		t.Errorf("updateIngestionSettingsHandler() wrong settings = %v", settings)
+	}
+
+	events := []struct {
+		name         string
+		id           string
+		sessionId    string
+		expectedCode int
+	}{
+		{name: "event of crashed session", id: "8d4e5f6a-7b8c-4d9e-9f0a-2b3c4d5e6f70", sessionId: crashedSessionId, expectedCode: http.StatusCreated},
+		{name: "event of sampled out session", id: "9e5f6a7b-8c9d-4eaf-8a1b-3c4d5e6f7081", sessionId: sessionId, expectedCode: http.StatusAccepted},
+		{name: "event within quota", id: "af6a7b8c-9dae-4fb0-9b2c-4d5e6f708192", sessionId: crashedSessionId, expectedCode: http.StatusCreated},
+		{name: "event over quota", id: "b07b8c9d-aebf-4a1c-8c3d-5e6f708192a3", sessionId: crashedSessionId, expectedCode: http.StatusAccepted},
+	}
+	for _, tt := range events {
+		t.Run(tt.name, func(t *testing.T) {
+			body := mustMarshal(t, model.EventDTO{Id: tt.id, SessionId: tt.sessionId, Type: "click", CreatedAt: 1700000000000})
+			if code, _ := call(t, s.createEventHandler, http.MethodPost, body); code != tt.expectedCode {
+				t.Errorf("wrong status code. expected = %d, actual = %d", tt.expectedCode, code)
+			}
+		})
+	}
+
+	code, got := call(t, s.getIngestionSettingsHandler, http.MethodGet, "")
+	if code != http.StatusOK {
+		t.Fatalf("getIngestionSettingsHandler() wrong status code = %d", code)
+	}
+	usage := got["usage"].(map[string]any)["events"].(map[string]any)
+	if usage["stored"] != float64(2) || usage["sampled"] != float64(1) || usage["overQuota"] != float64(1) || usage["remaining"] != float64(0) {
+		t.Errorf("getIngestionSettingsHandler() wrong event usage = %v", usage)
+	}
+}
+
+func TestCreateCollectionQueueUnavailable(t *testing.T) {
+	app, err := db.GetApplication(appId)
+	if err != nil {
+		t.Fatalf("Could not get application: %v", err)
+	}
+	rejectedAppId, err := db.CreateApplication(model.NewApplicationData{Name: "Rejected app", TeamId: app.TeamId})
+	if err != nil {
+		t.Fatalf("Could not create application: %v", err)
+	}
+
+	s := &Server{
+		db: db,
+	}
+	q := newTestQueue(t, s)
+	q.Shutdown(context.Background())
+
+	body := mustMarshal(t, model.CollectionDTO{
+		Events: []model.EventDTO{{Id: "c18c9dae-bfc0-4b2d-9d4e-6f708192a3b4", SessionId: "d29daebf-c0d1-4c3e-8e5f-708192a3b4c5", Type: "click", CreatedAt: 1700000000000}},
+	})
+	e := echo.New()
+	e.Validator = NewValidator()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/collection", strings.NewReader(body))
+	req.Header.Set("Content-type", "application/json")
+	resp := httptest.NewRecorder()
+	c := e.NewContext(req, resp)
+	c.Set("appId", rejectedAppId)
+
+	err = s.createCollectionHandler(c)
+	if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusServiceUnavailable {
+		t.Fatalf("createCollectionHandler() error = %v, expected status %d", err, http.StatusServiceUnavailable)
+	}
+
+	month := usageMonth(time.Now())
+	usage, err := db.GetIngestionUsage(rejectedAppId, month)
+	if err != nil {
+		t.Fatalf("Could not get ingestion usage: %v", err)
+	}
+	if usage != (model.IngestionUsageEntity{Month: month}) {
+		t.Errorf("Expected a rejected collection to leave the usage unchanged, got %+v", usage)
+	}
+}
+
+func TestReplayedIngestionUsage(t *testing.T) {
+	app, err := db.GetApplication(appId)
+	if err != nil {
+		t.Fatalf("Could not get application: %v", err)
+	}
+	replayAppId, err := db.CreateApplication(model.NewApplicationData{Name: "Replay app", TeamId: app.TeamId})
+	if err != nil {
+		t.Fatalf("Could not create application: %v", err)
+	}
+	sessionId := "e3aebfc0-d1e2-4d4f-9f60-8192a3b4c5d6"
+	err = db.CreateSession(model.NewSessionData{
+		Id:             sessionId,
+		InstallationId: "f4bfc0d1-e2f3-4e5a-8a71-92a3b4c5d6e7",
+		AppId:          replayAppId,
+		CreatedAt:      1700000000000,
+	})
+	if err != nil {
+		t.Fatalf("Could not create session: %v", err)
+	}
+
+	s := &Server{
+		db: db,
+	}
+	e := echo.New()
+	e.Validator = NewValidator()
+
+	event := model.EventDTO{Id: "05c0d1e2-f3a4-4f6b-9b82-a3b4c5d6e7f8", SessionId: sessionId, Type: "click", CreatedAt: 1700000000000}
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/events", strings.NewReader(mustMarshal(t, event)))
+		req.Header.Set("Content-type", "application/json")
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.Set("appId", replayAppId)
+		if err := s.createEventHandler(c); err != nil || resp.Code != http.StatusCreated {
+			t.Fatalf("createEventHandler() returned %v with status code %d", err, resp.Code)
+		}
+	}
+
+	// A collection sent again is only charged for the events not stored yet
+	collection := model.CollectionDTO{
+		Events: []model.EventDTO{event, {Id: "16d1e2f3-a4b5-4a7c-8c93-b4c5d6e7f809", SessionId: sessionId, Type: "click", CreatedAt: 1700000000000}},
+	}
+	for i := 0; i < 2; i++ {
+		admitted := s.admitIngestion(replayAppId, collection.Events, nil, nil)
+		err := s.processCollection(queue.Job{Id: "replayed-collection", Attempts: 1}, collectionJob{
+			AppId:      replayAppId,
+			Collection: model.CollectionDTO{Events: admitted.events},
+			UsageMonth: admitted.usage.Month,
+		})
+		if err != nil {
+			t.Fatalf("processCollection() error = %v", err)
+		}
+	}
+
+	usage, err := db.GetIngestionUsage(replayAppId, usageMonth(time.Now()))
+	if err != nil {
+		t.Fatalf("Could not get ingestion usage: %v", err)
+	}
+	if usage.Events != 2 {
+		t.Errorf("Expected the 2 stored events to be charged once, got %+v", usage)
+	}
+}
+
+func TestPendingCollectionStatus(t *testing.T) {
+	release := make(chan struct{})
+	q, err := queue.New(model.QueueConfig{
+		Dir:         t.TempDir(),
+		Capacity:    10,
+		Workers:     1,
+		MaxAttempts: 1,
+	}, func(ctx context.Context, job queue.Job) error {
+		<-release
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Could not create queue: %v", err)
+	}
+	defer func() {
+		close(release)
+		q.Shutdown(context.Background())
+	}()
+	s := &Server{
+		db:    db,
+		queue: q,
+	}
+
+	batchId, err := q.Enqueue(collectionJobKind, collectionJob{AppId: appId})
+	if err != nil {
+		t.Fatalf("Could not enqueue collection: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		appId        int
+		expectedCode int
+	}{
+		{name: "own app", appId: appId, expectedCode: http.StatusOK},
+		{name: "other app", appId: -1, expectedCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/collection/"+batchId, nil)
+			resp := httptest.NewRecorder()
+			c := e.NewContext(req, resp)
+			c.SetParamNames("id")
+			c.SetParamValues(batchId)
+			c.Set("appId", tt.appId)
+
+			code := resp.Code
+			if err := s.getCollectionStatusHandler(c); err != nil {
+				httpErr, ok := err.(*echo.HTTPError)
+				if !ok {
+					t.Fatalf("getCollectionStatusHandler() error = %v", err)
+				}
+				code = httpErr.Code
+			}
+			if code != tt.expectedCode {
+				t.Errorf("wrong status code. expected = %d, actual = %d", tt.expectedCode, code)
+			}
+		})
+	}
+}
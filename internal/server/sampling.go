@@ -0,0 +1,379 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"log"
+	"math"
+	"sync"
+	"time"
+)
+
+// Time the ingestion settings of an app are cached for, so ingestion does
+// not read them for every request. Changes made through other replicas
+// apply once their cache expires.
+const ingestionSettingsTTL = 30 * time.Second
+
+type cachedSettings struct {
+	settings model.IngestionSettingsEntity
+	loadedAt time.Time
+}
+
+// settingsCache keeps the ingestion settings of each app in memory
+type settingsCache struct {
+	mu      sync.Mutex
+	entries map[int]cachedSettings
+}
+
+func newSettingsCache() *settingsCache {
+	return &settingsCache{
+		entries: make(map[int]cachedSettings),
+	}
+}
+
+// Get returns the settings of the app, loading them if they are not cached
+// or have expired. A nil cache loads the settings on every call.
+func (c *settingsCache) Get(appId int, now time.Time, load func() (model.IngestionSettingsEntity, error)) (model.IngestionSettingsEntity, error) {
+	if c == nil {
+		return load()
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[appId]
+	c.mu.Unlock()
+	if ok && now.Sub(entry.loadedAt) < ingestionSettingsTTL {
+		return entry.settings, nil
+	}
+
+	settings, err := load()
+	if err != nil {
+		return settings, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[appId] = cachedSettings{settings: settings, loadedAt: now}
+	return settings, nil
+}
+
+func (c *settingsCache) Invalidate(appId int) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, appId)
+}
+
+// ingestionSettings returns the settings of the app, which are the defaults
+// of no quotas and no sampling if they were never changed
+func (s *Server) ingestionSettings(appId int, now time.Time) (model.IngestionSettingsEntity, error) {
+	return s.settingsCache.Get(appId, now, func() (model.IngestionSettingsEntity, error) {
+		settings, err := s.db.GetIngestionSettings(appId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.IngestionSettingsEntity{AppId: appId, SamplingRules: []model.SamplingRule{}}, nil
+		}
+		return settings, err
+	})
+}
+
+// admittedIngestion holds the events and traces of a request which are
+// stored, their indices in the request, and how many were dropped. usage is
+// what the request added to the usage of the app, which is nil if the usage
+// could not be updated.
+type admittedIngestion struct {
+	events       []model.EventDTO
+	traces       []model.TraceDTO
+	eventIndices []int
+	traceIndices []int
+	drops        model.IngestionDropsDTO
+	usage        *model.IngestionUsageEntity
+}
+
+// admitIngestion samples the events and traces by their session, and keeps
+// as many of the rest as the monthly quotas of the app allow. The sessions
+// of the request add to the stored ones, fx. since it contains a crash. If
+// the settings or usage cannot be read, everything is stored rather than
+// losing data. The admitted records are reserved in the usage right away, so
+// concurrent requests cannot exceed the quota, and must be released with
+// releaseIngestion if the request is not stored after all.
+func (s *Server) admitIngestion(appId int, events []model.EventDTO, traces []model.TraceDTO, sessions []model.SessionEntity) admittedIngestion {
+	admitted := admittedIngestion{
+		events:       events,
+		traces:       traces,
+		eventIndices: itemIndices(len(events)),
+		traceIndices: itemIndices(len(traces)),
+	}
+	if len(events) == 0 && len(traces) == 0 {
+		return admitted
+	}
+
+	now := time.Now()
+	settings, err := s.ingestionSettings(appId, now)
+	if err != nil {
+		log.Printf("Error getting ingestion settings of app %d: %v\n", appId, err)
+		return admitted
+	}
+
+	if samplingEnabled(settings.SamplingRules) {
+		known := s.sessionsForSampling(appId, events, traces, sessions)
+		keep := func(sessionId string) bool {
+			return keepSession(sessionId, sampleRate(settings.SamplingRules, known[sessionId]))
+		}
+		admitted.events, admitted.eventIndices = filterBySession(events, func(e model.EventDTO) string { return e.SessionId }, keep)
+		admitted.traces, admitted.traceIndices = filterBySession(traces, func(t model.TraceDTO) string { return t.SessionId }, keep)
+		admitted.drops.SampledEvents = len(events) - len(admitted.events)
+		admitted.drops.SampledTraces = len(traces) - len(admitted.traces)
+	}
+
+	month := usageMonth(now)
+	storedEvents, storedTraces, err := s.db.AddIngestionUsage(model.IngestionUsageData{
+		AppId:         appId,
+		Month:         month,
+		Events:        len(admitted.events),
+		Traces:        len(admitted.traces),
+		SampledEvents: admitted.drops.SampledEvents,
+		SampledTraces: admitted.drops.SampledTraces,
+		EventQuota:    settings.MonthlyEventQuota,
+		TraceQuota:    settings.MonthlyTraceQuota,
+	})
+	if err != nil {
+		log.Printf("Error adding ingestion usage of app %d: %v\n", appId, err)
+		return admitted
+	}
+
+	admitted.drops.OverQuotaEvents = len(admitted.events) - storedEvents
+	admitted.drops.OverQuotaTraces = len(admitted.traces) - storedTraces
+	admitted.events = admitted.events[:storedEvents]
+	admitted.traces = admitted.traces[:storedTraces]
+	admitted.eventIndices = admitted.eventIndices[:storedEvents]
+	admitted.traceIndices = admitted.traceIndices[:storedTraces]
+	admitted.usage = &model.IngestionUsageEntity{
+		Month:           month,
+		Events:          int64(storedEvents),
+		Traces:          int64(storedTraces),
+		SampledEvents:   int64(admitted.drops.SampledEvents),
+		SampledTraces:   int64(admitted.drops.SampledTraces),
+		OverQuotaEvents: int64(admitted.drops.OverQuotaEvents),
+		OverQuotaTraces: int64(admitted.drops.OverQuotaTraces),
+	}
+
+	return admitted
+}
+
+// releaseIngestion takes back what admitIngestion added to the usage of the
+// app, for a request which could not be stored and will be sent again
+func (s *Server) releaseIngestion(appId int, admitted admittedIngestion) {
+	if admitted.usage == nil {
+		return
+	}
+	if err := s.db.ReleaseIngestionUsage(appId, *admitted.usage); err != nil {
+		log.Printf("Error releasing ingestion usage of app %d: %v\n", appId, err)
+	}
+}
+
+// settleIngestion replaces the events and traces admitIngestion reserved for
+// a batch with the ones which were inserted by an attempt to write it, so
+// records which were stored before, fx. since the SDK sent them again, or
+// which could not be written do not count. The first attempt releases the
+// rest of the reservation, later attempts add what they inserted.
+func (s *Server) settleIngestion(appId int, reserved model.IngestionUsageEntity, events, traces, attempt int) {
+	if attempt > 1 {
+		if events == 0 && traces == 0 {
+			return
+		}
+		_, _, err := s.db.AddIngestionUsage(model.IngestionUsageData{
+			AppId:  appId,
+			Month:  reserved.Month,
+			Events: events,
+			Traces: traces,
+		})
+		if err != nil {
+			log.Printf("Error adding ingestion usage of app %d: %v\n", appId, err)
+		}
+		return
+	}
+
+	unused := model.IngestionUsageEntity{
+		Month:  reserved.Month,
+		Events: reserved.Events - int64(events),
+		Traces: reserved.Traces - int64(traces),
+	}
+	if unused.Events <= 0 && unused.Traces <= 0 {
+		return
+	}
+	if err := s.db.ReleaseIngestionUsage(appId, unused); err != nil {
+		log.Printf("Error releasing ingestion usage of app %d: %v\n", appId, err)
+	}
+}
+
+// sessionsForSampling merges the sessions of a request with the stored
+// sessions of its events and traces, so a session which crashed before is
+// still sampled as crashed
+func (s *Server) sessionsForSampling(appId int, events []model.EventDTO, traces []model.TraceDTO, sessions []model.SessionEntity) map[string]model.SessionEntity {
+	ids := make([]string, 0)
+	seen := make(map[string]bool)
+	addId := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, e := range events {
+		addId(e.SessionId)
+	}
+	for _, t := range traces {
+		addId(t.SessionId)
+	}
+
+	stored, err := s.db.GetSessions(appId, ids)
+	if err != nil {
+		log.Printf("Error getting sessions to sample: %v\n", err)
+	}
+
+	return mergeSessions(append(stored, sessions...))
+}
+
+// collectionSessions returns the session of a collection, and the sessions
+// of its crashes as crashed
+func collectionSessions(collection model.CollectionDTO) []model.SessionEntity {
+	sessions := make([]model.SessionEntity, 0, len(collection.Crashes)+1)
+	if collection.Session != nil {
+		sessions = append(sessions, model.SessionEntity{
+			Id:         collection.Session.Id,
+			AppVersion: collection.Session.AppVersion,
+			Crashed:    collection.Session.Crashed,
+		})
+	}
+	for _, crash := range collection.Crashes {
+		sessions = append(sessions, model.SessionEntity{Id: crash.SessionId, AppVersion: crash.AppVersion, Crashed: true})
+	}
+	return sessions
+}
+
+// mergeSessions combines what is known about each session. A session has
+// crashed if any of its entries has.
+func mergeSessions(sessions []model.SessionEntity) map[string]model.SessionEntity {
+	merged := make(map[string]model.SessionEntity, len(sessions))
+	for _, session := range sessions {
+		existing, ok := merged[session.Id]
+		if ok {
+			session.Crashed = session.Crashed || existing.Crashed
+			if session.AppVersion == "" {
+				session.AppVersion = existing.AppVersion
+			}
+		}
+		merged[session.Id] = session
+	}
+	return merged
+}
+
+// samplingEnabled reports whether any rule drops sessions
+func samplingEnabled(rules []model.SamplingRule) bool {
+	for _, rule := range rules {
+		if rule.Rate < 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// sampleRate returns the rate of the first rule matching the session, or 1
+// if none matches
+func sampleRate(rules []model.SamplingRule, session model.SessionEntity) float64 {
+	for _, rule := range rules {
+		if rule.Crashed != nil && *rule.Crashed != session.Crashed {
+			continue
+		}
+		if rule.AppVersion != "" && rule.AppVersion != session.AppVersion {
+			continue
+		}
+		return rule.Rate
+	}
+	return 1
+}
+
+// keepSession decides by a hash of the session id, so every replica keeps
+// the same sessions, and all data of a kept session is stored
+func keepSession(sessionId string, rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	hash := sha256.Sum256([]byte(sessionId))
+	return float64(binary.BigEndian.Uint64(hash[:8]))/math.MaxUint64 < rate
+}
+
+// filterBySession returns the items of the sessions to keep, and their
+// indices in items
+func filterBySession[T any](items []T, sessionId func(T) string, keep func(string) bool) ([]T, []int) {
+	kept := make([]T, 0, len(items))
+	indices := make([]int, 0, len(items))
+	for i, item := range items {
+		if keep(sessionId(item)) {
+			kept = append(kept, item)
+			indices = append(indices, i)
+		}
+	}
+	return kept, indices
+}
+
+func itemIndices(n int) []int {
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+	return indices
+}
+
+// usageMonth returns the start of the month of now in UTC, in milliseconds
+func usageMonth(now time.Time) int64 {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+}
+
+// quotaUsageDTO compares the records stored in the month with the quota, and
+// projects them to the end of the month
+func quotaUsageDTO(stored, sampled, overQuota, quota int64, month int64, now time.Time) model.QuotaUsageDTO {
+	dto := model.QuotaUsageDTO{
+		Stored:    stored,
+		Sampled:   sampled,
+		OverQuota: overQuota,
+		Projected: stored,
+	}
+
+	start := time.UnixMilli(month).UTC()
+	end := start.AddDate(0, 1, 0)
+	if elapsed := now.Sub(start); elapsed > 0 && now.Before(end) {
+		dto.Projected = int64(math.Round(float64(stored) * float64(end.Sub(start)) / float64(elapsed)))
+	}
+
+	if quota > 0 {
+		remaining := max(0, quota-stored)
+		used := float64(stored) / float64(quota)
+		dto.Quota = quota
+		dto.Remaining = &remaining
+		dto.Used = &used
+	}
+	return dto
+}
+
+func ingestionUsageDTO(ent model.IngestionUsageEntity, settings model.IngestionSettingsEntity, now time.Time) model.IngestionUsageDTO {
+	return model.IngestionUsageDTO{
+		Month:  ent.Month,
+		Events: quotaUsageDTO(ent.Events, ent.SampledEvents, ent.OverQuotaEvents, settings.MonthlyEventQuota, ent.Month, now),
+		Traces: quotaUsageDTO(ent.Traces, ent.SampledTraces, ent.OverQuotaTraces, settings.MonthlyTraceQuota, ent.Month, now),
+	}
+}
+
+func ingestionSettingsDTO(ent model.IngestionSettingsEntity) model.IngestionSettingsDTO {
+	return model.IngestionSettingsDTO{
+		MonthlyEventQuota: ent.MonthlyEventQuota,
+		MonthlyTraceQuota: ent.MonthlyTraceQuota,
+		SamplingRules:     ent.SamplingRules,
+		UpdatedAt:         ent.UpdatedAt,
+	}
+}
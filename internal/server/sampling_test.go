@@ -0,0 +1,167 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSampleRate(t *testing.T) {
+	crashed, notCrashed := true, false
+	rules := []model.SamplingRule{
+		{Crashed: &crashed, Rate: 1},
+		{Crashed: &notCrashed, AppVersion: "2.0.0", Rate: 0.5},
+		{Crashed: &notCrashed, Rate: 0.1},
+	}
+
+	tests := []struct {
+		name     string
+		session  model.SessionEntity
+		expected float64
+	}{
+		{name: "crashed session", session: model.SessionEntity{Crashed: true, AppVersion: "2.0.0"}, expected: 1},
+		{name: "session of version", session: model.SessionEntity{AppVersion: "2.0.0"}, expected: 0.5},
+		{name: "other session", session: model.SessionEntity{AppVersion: "1.0.0"}, expected: 0.1},
+		{name: "unknown session", session: model.SessionEntity{}, expected: 0.1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sampleRate(rules, tt.session); got != tt.expected {
+				t.Errorf("sampleRate = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+
+	if got := sampleRate(rules[:1], model.SessionEntity{}); got != 1 {
+		t.Errorf("sampleRate without matching rule = %v, expected 1", got)
+	}
+}
+
+func TestSamplingEnabled(t *testing.T) {
+	if samplingEnabled(nil) || samplingEnabled([]model.SamplingRule{{Rate: 1}}) {
+		t.Errorf("Sampling enabled by rules which keep every session")
+	}
+	if !samplingEnabled([]model.SamplingRule{{Rate: 1}, {Rate: 0}}) {
+		t.Errorf("Sampling disabled by rule which drops sessions")
+	}
+}
+
+func TestKeepSession(t *testing.T) {
+	kept := 0
+	for i := 0; i < 10000; i++ {
+		id := fmt.Sprintf("session-%d", i)
+		keep := keepSession(id, 0.1)
+		if keep != keepSession(id, 0.1) {
+			t.Fatalf("keepSession of %s is not deterministic", id)
+		}
+		if keep && !keepSession(id, 0.5) {
+			t.Errorf("Session %s kept at rate 0.1 but not at 0.5", id)
+		}
+		if keepSession(id, 0) {
+			t.Errorf("Session %s kept at rate 0", id)
+		}
+		if !keepSession(id, 1) {
+			t.Errorf("Session %s dropped at rate 1", id)
+		}
+		if keep {
+			kept++
+		}
+	}
+
+	if kept < 900 || kept > 1100 {
+		t.Errorf("Kept %d of 10000 sessions at rate 0.1", kept)
+	}
+}
+
+func TestMergeSessions(t *testing.T) {
+	merged := mergeSessions([]model.SessionEntity{
+		{Id: "a", AppVersion: "1.0.0", Crashed: true},
+		{Id: "b", AppVersion: "1.0.0"},
+		{Id: "a", AppVersion: ""},
+		{Id: "b", AppVersion: "1.1.0", Crashed: true},
+	})
+
+	expected := map[string]model.SessionEntity{
+		"a": {Id: "a", AppVersion: "1.0.0", Crashed: true},
+		"b": {Id: "b", AppVersion: "1.1.0", Crashed: true},
+	}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("mergeSessions = %+v, expected %+v", merged, expected)
+	}
+}
+
+func TestCollectionSessions(t *testing.T) {
+	collection := model.CollectionDTO{
+		Session: &model.SessionDTO{Id: "a", AppVersion: "1.0.0"},
+		Crashes: []model.CrashDTO{{SessionId: "b", AppVersion: "1.1.0"}},
+	}
+
+	expected := []model.SessionEntity{
+		{Id: "a", AppVersion: "1.0.0"},
+		{Id: "b", AppVersion: "1.1.0", Crashed: true},
+	}
+	if got := collectionSessions(collection); !reflect.DeepEqual(got, expected) {
+		t.Errorf("collectionSessions = %+v, expected %+v", got, expected)
+	}
+}
+
+func TestFilterBySession(t *testing.T) {
+	events := []model.EventDTO{{Id: "1", SessionId: "a"}, {Id: "2", SessionId: "b"}, {Id: "3", SessionId: "a"}}
+
+	kept, indices := filterBySession(events, func(e model.EventDTO) string { return e.SessionId }, func(id string) bool { return id == "a" })
+	if len(kept) != 2 || kept[0].Id != "1" || kept[1].Id != "3" || !slices.Equal(indices, []int{0, 2}) {
+		t.Errorf("filterBySession = %+v, %v, expected the events of session a", kept, indices)
+	}
+}
+
+func TestUsageMonth(t *testing.T) {
+	now := time.Date(2024, time.March, 31, 23, 30, 0, 0, time.FixedZone("", -2*60*60))
+
+	if got := usageMonth(now); got != time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC).UnixMilli() {
+		t.Errorf("usageMonth = %v, expected the start of April in UTC", time.UnixMilli(got).UTC())
+	}
+}
+
+func TestQuotaUsageDTO(t *testing.T) {
+	month := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+	now := month.Add(3 * 24 * time.Hour)
+
+	remaining := int64(700)
+	used := 0.3
+	expected := model.QuotaUsageDTO{Stored: 300, Sampled: 20, OverQuota: 0, Projected: 3000, Quota: 1000, Remaining: &remaining, Used: &used}
+	if got := quotaUsageDTO(300, 20, 0, 1000, month.UnixMilli(), now); !reflect.DeepEqual(got, expected) {
+		t.Errorf("quotaUsageDTO = %+v, expected %+v", got, expected)
+	}
+
+	unlimited := quotaUsageDTO(300, 0, 0, 0, month.UnixMilli(), month.AddDate(0, 1, 1))
+	if unlimited.Quota != 0 || unlimited.Remaining != nil || unlimited.Used != nil || unlimited.Projected != 300 {
+		t.Errorf("quotaUsageDTO of unlimited past month = %+v", unlimited)
+	}
+}
+
+func TestSettingsCache(t *testing.T) {
+	cache := newSettingsCache()
+	loads := 0
+	load := func() (model.IngestionSettingsEntity, error) {
+		loads++
+		return model.IngestionSettingsEntity{AppId: 1, MonthlyEventQuota: int64(loads)}, nil
+	}
+
+	now := time.UnixMilli(1700000000000)
+	cache.Get(1, now, load)
+	if settings, _ := cache.Get(1, now.Add(ingestionSettingsTTL/2), load); settings.MonthlyEventQuota != 1 || loads != 1 {
+		t.Errorf("Cached settings were loaded again")
+	}
+	if settings, _ := cache.Get(1, now.Add(ingestionSettingsTTL), load); settings.MonthlyEventQuota != 2 {
+		t.Errorf("Expired settings were not loaded again")
+	}
+
+	cache.Invalidate(1)
+	if settings, _ := cache.Get(1, now.Add(ingestionSettingsTTL), load); settings.MonthlyEventQuota != 3 {
+		t.Errorf("Invalidated settings were not loaded again")
+	}
+}
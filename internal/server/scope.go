@@ -0,0 +1,68 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Types of data received by the ingestion routes, which ingest scopes of
+// api keys can be limited to
+var ingestDataTypes = []string{
+	"installations",
+	"sessions",
+	"collection",
+	"crashes",
+	"anrs",
+	"events",
+	"traces",
+	"memory",
+	"cpu",
+	"frames",
+	"battery",
+	"network",
+	"starts",
+	"requests",
+}
+
+// Keys created without scopes can ingest any data, like keys did before
+// they had scopes
+var defaultApiKeyScopes = []string{model.ScopeIngest}
+
+func validScope(scope string) bool {
+	if scope == model.ScopeIngest || scope == model.ScopeRead {
+		return true
+	}
+	dataType, ok := strings.CutPrefix(scope, model.ScopeIngestPrefix)
+	return ok && slices.Contains(ingestDataTypes, dataType)
+}
+
+// hasAnyScope reports whether any of the scopes were granted
+func hasAnyScope(granted []string, scopes ...string) bool {
+	return slices.ContainsFunc(scopes, func(scope string) bool {
+		return slices.Contains(granted, scope)
+	})
+}
+
+// ingestScope only lets requests through if their api key may ingest the
+// type of data. It must run after APIKeyMiddleware, which sets the scopes
+// of the key on the echo Context.
+func ingestScope(dataType string) echo.MiddlewareFunc {
+	if !slices.Contains(ingestDataTypes, dataType) {
+		panic(fmt.Sprintf("unknown ingest data type '%s'", dataType))
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			scopes, _ := c.Get("scopes").([]string)
+			if !hasAnyScope(scopes, model.ScopeIngest, model.ScopeIngestPrefix+dataType) {
+				return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Api key is not allowed to ingest %s", dataType))
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestValidScope(t *testing.T) {
+	tests := []struct {
+		scope    string
+		expected bool
+	}{
+		{scope: "ingest", expected: true},
+		{scope: "read", expected: true},
+		{scope: "ingest:events", expected: true},
+		{scope: "ingest:memory", expected: true},
+		{scope: "ingest:", expected: false},
+		{scope: "ingest:unknown", expected: false},
+		{scope: "write", expected: false},
+		{scope: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		if actual := validScope(tt.scope); actual != tt.expected {
+			t.Errorf("validScope(%q) = %v, expected %v", tt.scope, actual, tt.expected)
+		}
+	}
+}
+
+func TestIngestScope(t *testing.T) {
+	tests := []struct {
+		name         string
+		scopes       []string
+		dataType     string
+		expectedCode int
+	}{
+		{name: "ingest any data", scopes: []string{model.ScopeIngest}, dataType: "events", expectedCode: http.StatusOK},
+		{name: "ingest the type", scopes: []string{"ingest:events"}, dataType: "events", expectedCode: http.StatusOK},
+		{name: "ingest another type", scopes: []string{"ingest:traces"}, dataType: "events", expectedCode: http.StatusForbidden},
+		{name: "read only", scopes: []string{model.ScopeRead}, dataType: "events", expectedCode: http.StatusForbidden},
+		{name: "no scopes", scopes: nil, dataType: "events", expectedCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/events", nil)
+			resp := httptest.NewRecorder()
+			c := e.NewContext(req, resp)
+			if tt.scopes != nil {
+				c.Set("scopes", tt.scopes)
+			}
+
+			handler := ingestScope(tt.dataType)(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+			err := handler(c)
+			if he, ok := err.(*echo.HTTPError); ok {
+				resp.Code = he.Code
+			} else if err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+
+			if resp.Code != tt.expectedCode {
+				t.Errorf("ingestScope() wrong status code. expected = %d, actual = %d", tt.expectedCode, resp.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Max number of predicates in an attribute query
+const maxAttributePredicates = 16
+
+// parseAttributeQuery parses predicates like 'screen=Checkout AND button=pay'.
+// Values may be quoted to include spaces, which also makes them match only
+// strings. Unquoted values match strings, and numbers or booleans if they
+// can be read as such.
+func parseAttributeQuery(query string) ([]model.AttributePredicate, error) {
+	predicates := make([]model.AttributePredicate, 0)
+	rest := strings.TrimSpace(query)
+	for rest != "" {
+		if len(predicates) > 0 {
+			keyword, after, ok := strings.Cut(rest, " ")
+			if !ok || !strings.EqualFold(keyword, "AND") {
+				return nil, fmt.Errorf("Expected AND before '%s'", rest)
+			}
+			rest = strings.TrimSpace(after)
+		}
+		if len(predicates) == maxAttributePredicates {
+			return nil, fmt.Errorf("Query can have at most %d predicates", maxAttributePredicates)
+		}
+
+		key, after, ok := strings.Cut(rest, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !attributeKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("Expected an attribute key followed by '=' at '%s'", rest)
+		}
+		rest = strings.TrimSpace(after)
+
+		predicate := model.AttributePredicate{Key: key}
+		if strings.HasPrefix(rest, `"`) {
+			value, remaining, err := cutQuoted(rest)
+			if err != nil {
+				return nil, err
+			}
+			predicate.Values = []any{value}
+			rest = remaining
+		} else {
+			value, remaining, _ := strings.Cut(rest, " ")
+			if value == "" {
+				return nil, fmt.Errorf("Missing value of '%s'", key)
+			}
+			predicate.Values = attributeCandidates(value)
+			rest = remaining
+		}
+
+		predicates = append(predicates, predicate)
+		rest = strings.TrimSpace(rest)
+	}
+
+	return predicates, nil
+}
+
+// cutQuoted reads the quoted string at the start of s, and returns it
+// unquoted along with the rest of s
+func cutQuoted(s string) (string, string, error) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			value, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", "", fmt.Errorf("Malformed quoted value %s", s[:i+1])
+			}
+			return value, s[i+1:], nil
+		}
+	}
+	return "", "", fmt.Errorf("Unterminated quoted value %s", s)
+}
+
+func attributeCandidates(value string) []any {
+	candidates := []any{value}
+	if value == "true" || value == "false" {
+		candidates = append(candidates, value == "true")
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		candidates = append(candidates, f)
+	}
+	return candidates
+}
+
+func eventDTO(ent model.EventEntity) model.EventDTO {
+	return model.EventDTO{
+		Id:             ent.Id,
+		SessionId:      ent.SessionId,
+		Type:           ent.Type,
+		SerializedData: ent.SerializedData,
+		Attributes:     ent.Attributes,
+		CreatedAt:      ent.CreatedAt,
+	}
+}
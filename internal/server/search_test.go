@@ -0,0 +1,63 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"reflect"
+	"testing"
+)
+
+func TestParseAttributeQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []model.AttributePredicate
+		wantErr  bool
+	}{
+		{
+			name:     "empty",
+			query:    "  ",
+			expected: []model.AttributePredicate{},
+		},
+		{
+			name:  "strings",
+			query: "screen=Checkout AND button=pay",
+			expected: []model.AttributePredicate{
+				{Key: "screen", Values: []any{"Checkout"}},
+				{Key: "button", Values: []any{"pay"}},
+			},
+		},
+		{
+			name:  "typed values",
+			query: "amount = 12.5 and premium=true",
+			expected: []model.AttributePredicate{
+				{Key: "amount", Values: []any{"12.5", 12.5}},
+				{Key: "premium", Values: []any{"true", true}},
+			},
+		},
+		{
+			name:  "quoted",
+			query: `label="Pay \"now\"" AND count="3"`,
+			expected: []model.AttributePredicate{
+				{Key: "label", Values: []any{`Pay "now"`}},
+				{Key: "count", Values: []any{"3"}},
+			},
+		},
+		{name: "missing and", query: "screen=Checkout button=pay", wantErr: true},
+		{name: "or", query: "screen=Checkout OR button=pay", wantErr: true},
+		{name: "invalid key", query: "the screen=Checkout", wantErr: true},
+		{name: "missing value", query: "screen=", wantErr: true},
+		{name: "unterminated quote", query: `screen="Checkout`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			predicates, err := parseAttributeQuery(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAttributeQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(predicates, tt.expected) {
+				t.Errorf("parseAttributeQuery() = %+v, expected %+v", predicates, tt.expected)
+			}
+		})
+	}
+}
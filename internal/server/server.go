@@ -1,29 +1,79 @@
 package server
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"net/http"
 	"time"
 
 	_ "github.com/joho/godotenv/autoload"
 
 	"ObservabilityServer/internal/database"
+	"ObservabilityServer/internal/mapping"
 	"ObservabilityServer/internal/model"
+	"ObservabilityServer/internal/queue"
+	"ObservabilityServer/internal/ratelimit"
 )
 
 type Server struct {
-	port int
+	port                 int
+	maxDecompressedBytes int64
+	maxMappingBytes      int64
 
 	db database.Service
+
+	// Parsed mappings by app, version and upload time
+	mappings *mapping.Cache
+
+	queue        *queue.Queue
+	drainTimeout time.Duration
+
+	// Limits of the ingestion requests of each api key and installation
+	limiter           ratelimit.Limiter
+	keyLimit          ratelimit.Limit
+	installationLimit ratelimit.Limit
+	throttled         *throttleCounter
+
+	// Quotas and sampling rules of each app
+	settingsCache *settingsCache
 }
 
-func NewServer(config model.Config) *http.Server {
+func NewServer(config model.Config) (*http.Server, *Server) {
 	newServer := &Server{
-		port: config.Port,
+		port:                 config.Port,
+		maxDecompressedBytes: int64(config.MaxDecompressedBytes),
+		maxMappingBytes:      int64(config.MaxMappingBytes),
 
 		db: database.New(config.Database),
+
+		mappings: mapping.NewCache(config.MappingCacheSize),
+
+		drainTimeout: time.Duration(config.Queue.DrainTimeoutSeconds) * time.Second,
+
+		keyLimit:          ratelimit.LimitPerMinute(config.RateLimit.KeyPerMinute, config.RateLimit.KeyBurst),
+		installationLimit: ratelimit.LimitPerMinute(config.RateLimit.InstallationPerMinute, config.RateLimit.InstallationBurst),
+		throttled:         newThrottleCounter(),
+
+		settingsCache: newSettingsCache(),
 	}
 
+	switch config.RateLimit.Store {
+	case "memory":
+		newServer.limiter = ratelimit.NewMemory()
+	case "postgres":
+		newServer.limiter = ratelimit.NewPostgres(newServer.db)
+	default:
+		log.Fatalf("Unknown rate limit store '%s', expected memory or postgres\n", config.RateLimit.Store)
+	}
+	go newServer.flushThrottledLoop(time.Duration(max(config.RateLimit.FlushSeconds, 1)) * time.Second)
+
+	q, err := queue.New(config.Queue, newServer.processJob)
+	if err != nil {
+		log.Fatalf("Error creating ingestion queue: %v\n", err)
+	}
+	newServer.queue = q
+
 	// Declare Server config
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", newServer.port),
@@ -33,5 +83,15 @@ func NewServer(config model.Config) *http.Server {
 		WriteTimeout: 30 * time.Second,
 	}
 
-	return server
+	return server, newServer
+}
+
+// DrainQueue stops accepting new ingestion jobs and waits for the
+// accepted ones to be written. Jobs not finished within the drain timeout
+// are kept on disk and replayed on the next start.
+func (s *Server) DrainQueue() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.drainTimeout)
+	defer cancel()
+
+	return s.queue.Shutdown(ctx)
 }
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"net/http"
+	"regexp"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Keys of installation data which can be used to break down metrics
+var attributeKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// parseInterval reads the 'interval' query param, which defaults to a day
+func parseInterval(c echo.Context) (string, error) {
+	switch interval := c.QueryParam("interval"); interval {
+	case "":
+		return model.StabilityIntervalDay, nil
+	case model.StabilityIntervalHour, model.StabilityIntervalDay, model.StabilityIntervalWeek, model.StabilityIntervalMonth:
+		return interval, nil
+	default:
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Query param 'interval' must be one of hour, day, week or month")
+	}
+}
+
+func stabilityDTOS(entities []model.StabilityEntity, withStart, withValue bool) []model.StabilityDTO {
+	DTOS := make([]model.StabilityDTO, len(entities))
+	for i, ent := range entities {
+		DTOS[i] = stabilityDTO(ent, withStart, withValue)
+	}
+	return DTOS
+}
+
+func stabilityDTO(ent model.StabilityEntity, withStart, withValue bool) model.StabilityDTO {
+	dto := model.StabilityDTO{
+		Sessions:                  ent.Sessions,
+		CrashedSessions:           ent.CrashedSessions,
+		CrashFreeSessionRate:      crashFreeRate(ent.CrashedSessions, ent.Sessions),
+		Installations:             ent.Installations,
+		CrashedInstallations:      ent.CrashedInstallations,
+		CrashFreeInstallationRate: crashFreeRate(ent.CrashedInstallations, ent.Installations),
+	}
+	if withStart {
+		dto.Start = ent.Bucket
+	}
+	if withValue {
+		value := ent.Group
+		dto.Value = &value
+	}
+	return dto
+}
+
+// crashFreeRate is 1 if there is nothing to count, since nothing crashed
+func crashFreeRate(crashed, total int) float64 {
+	if total == 0 {
+		return 1
+	}
+	return 1 - rate(crashed, total)
+}
@@ -0,0 +1,64 @@
+package server
+
+import "ObservabilityServer/internal/model"
+
+func appStartDTO(ent model.AppStartEntity) model.GetAppStartDTO {
+	dto := model.GetAppStartDTO{
+		Id:             ent.Id,
+		SessionId:      ent.SessionId,
+		InstallationId: ent.InstallationId,
+		AppId:          ent.AppId,
+		StartType:      ent.StartType,
+		Duration:       ent.Duration,
+		CreatedAt:      ent.CreatedAt,
+	}
+	if ent.StartType == model.StartTypeCold {
+		processDuration := ent.ProcessDuration
+		applicationDuration := ent.Duration - ent.ProcessDuration
+		dto.ProcessDuration = &processDuration
+		dto.ApplicationDuration = &applicationDuration
+	}
+	return dto
+}
+
+func startupSummaryDTOS(entities []model.StartupPercentilesEntity, startType string, withStart, withValue bool) []model.StartupSummaryDTO {
+	DTOS := make([]model.StartupSummaryDTO, len(entities))
+	for i, ent := range entities {
+		DTOS[i] = startupSummaryDTO(ent, startType, withStart, withValue)
+	}
+	return DTOS
+}
+
+// startupSummaryDTO only includes the phases of cold starts, since warm and
+// hot starts skip them
+func startupSummaryDTO(ent model.StartupPercentilesEntity, startType string, withStart, withValue bool) model.StartupSummaryDTO {
+	dto := model.StartupSummaryDTO{
+		Starts:   ent.Starts,
+		Sessions: ent.Sessions,
+		Duration: model.PercentilesDTO{
+			P50: ent.DurationP50,
+			P90: ent.DurationP90,
+			P99: ent.DurationP99,
+		},
+	}
+	if startType == model.StartTypeCold {
+		dto.ProcessDuration = &model.PercentilesDTO{
+			P50: ent.ProcessP50,
+			P90: ent.ProcessP90,
+			P99: ent.ProcessP99,
+		}
+		dto.ApplicationDuration = &model.PercentilesDTO{
+			P50: ent.ApplicationP50,
+			P90: ent.ApplicationP90,
+			P99: ent.ApplicationP99,
+		}
+	}
+	if withStart {
+		dto.Start = ent.Bucket
+	}
+	if withValue {
+		value := ent.Group
+		dto.Value = &value
+	}
+	return dto
+}
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"testing"
+)
+
+func TestAppStartPhases(t *testing.T) {
+	cold := appStartDTO(model.AppStartEntity{StartType: model.StartTypeCold, Duration: 900, ProcessDuration: 250})
+	if cold.ProcessDuration == nil || *cold.ProcessDuration != 250 {
+		t.Errorf("Expected process duration of 250, got %+v", cold)
+	}
+	if cold.ApplicationDuration == nil || *cold.ApplicationDuration != 650 {
+		t.Errorf("Expected application duration of 650, got %+v", cold)
+	}
+
+	warm := appStartDTO(model.AppStartEntity{StartType: model.StartTypeWarm, Duration: 400})
+	if warm.ProcessDuration != nil || warm.ApplicationDuration != nil {
+		t.Errorf("Expected no phases of warm start, got %+v", warm)
+	}
+
+	summary := startupSummaryDTO(model.StartupPercentilesEntity{Starts: 3, DurationP50: 300}, model.StartTypeHot, false, false)
+	if summary.ProcessDuration != nil || summary.ApplicationDuration != nil || summary.Duration.P50 != 300 {
+		t.Errorf("Expected no phases of hot starts, got %+v", summary)
+	}
+}
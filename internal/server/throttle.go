@@ -0,0 +1,158 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"cmp"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Header in which SDKs send the id of their installation, so its requests
+// are limited before their body is read
+const installationIdHeader = "X-Installation-Id"
+
+type throttleBucket struct {
+	appId  int
+	bucket int64
+	reason string
+}
+
+// throttleCounter counts the throttled requests of each app in memory, so
+// a flood of requests does not turn into a flood of writes
+type throttleCounter struct {
+	mu     sync.Mutex
+	counts map[throttleBucket]int
+}
+
+func newThrottleCounter() *throttleCounter {
+	return &throttleCounter{
+		counts: make(map[throttleBucket]int),
+	}
+}
+
+func (t *throttleCounter) add(appId int, reason string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.counts[throttleBucket{appId: appId, bucket: now.Truncate(time.Hour).UnixMilli(), reason: reason}]++
+}
+
+// take returns the counts since the last take, ordered so concurrent writes
+// of replicas lock their rows in the same order
+func (t *throttleCounter) take() []model.ThrottledRequestsData {
+	t.mu.Lock()
+	counts := t.counts
+	t.counts = make(map[throttleBucket]int)
+	t.mu.Unlock()
+
+	data := make([]model.ThrottledRequestsData, 0, len(counts))
+	for b, count := range counts {
+		data = append(data, model.ThrottledRequestsData{AppId: b.appId, Bucket: b.bucket, Reason: b.reason, Count: count})
+	}
+	slices.SortFunc(data, func(a, b model.ThrottledRequestsData) int {
+		return cmp.Or(cmp.Compare(a.AppId, b.AppId), cmp.Compare(a.Bucket, b.Bucket), cmp.Compare(a.Reason, b.Reason))
+	})
+	return data
+}
+
+// Limits the requests of each api key, and of each installation which sends
+// its id in the X-Installation-Id header. Throttled requests get status 429
+// with the seconds to wait in Retry-After, and are counted per app.
+func (s *Server) RateLimitMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		keyId, _ := c.Get("apiKeyId").(int)
+		if err := s.throttle(c, model.ThrottleReasonKey, strconv.Itoa(keyId)); err != nil {
+			return err
+		}
+
+		if installationId := c.Request().Header.Get(installationIdHeader); installationId != "" {
+			if err := s.throttle(c, model.ThrottleReasonInstallation, installationId); err != nil {
+				return err
+			}
+		}
+
+		return next(c)
+	}
+}
+
+// throttle takes a token from the bucket of the api key or installation with
+// the id, and returns an error with status 429 if it has none left. Requests
+// are let through if the limiter fails, rather than stopping ingestion.
+func (s *Server) throttle(c echo.Context, reason, id string) error {
+	limit := s.keyLimit
+	if reason == model.ThrottleReasonInstallation {
+		limit = s.installationLimit
+	}
+	if s.limiter == nil || !limit.Enabled() {
+		return nil
+	}
+
+	appId, _ := c.Get("appId").(int)
+	now := time.Now()
+	ok, wait, err := s.limiter.Take(fmt.Sprintf("%s:%d:%s", reason, appId, id), limit, now)
+	if err != nil {
+		log.Printf("Error checking rate limit: %v\n", err)
+		return nil
+	}
+	if ok {
+		return nil
+	}
+
+	s.throttled.add(appId, reason, now)
+	c.Response().Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(wait)))
+	return echo.NewHTTPError(http.StatusTooManyRequests, fmt.Sprintf("Too many requests from this %s, try again later", reason))
+}
+
+// retryAfterSeconds rounds wait up to whole seconds, and at least one
+func retryAfterSeconds(wait time.Duration) int {
+	return max(1, int(math.Ceil(wait.Seconds())))
+}
+
+// flushThrottledLoop writes the counts of throttled requests every interval
+func (s *Server) flushThrottledLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.FlushThrottled(); err != nil {
+			log.Printf("Error writing throttled request counts: %v\n", err)
+		}
+	}
+}
+
+// FlushThrottled writes the counts of requests throttled since the last
+// flush. Counts which could not be written are dropped.
+func (s *Server) FlushThrottled() error {
+	if s.throttled == nil {
+		return nil
+	}
+	return s.db.AddThrottledRequests(s.throttled.take())
+}
+
+// throttledRequestsDTOS merges the counts of each reason of a bucket. Counts
+// without an interval are all in bucket 0, which is left out of the DTO.
+func throttledRequestsDTOS(entities []model.ThrottledRequestsEntity) []model.ThrottledRequestsDTO {
+	DTOS := make([]model.ThrottledRequestsDTO, 0)
+	for _, ent := range entities {
+		if len(DTOS) == 0 || DTOS[len(DTOS)-1].Start != ent.Bucket {
+			DTOS = append(DTOS, model.ThrottledRequestsDTO{Start: ent.Bucket})
+		}
+		dto := &DTOS[len(DTOS)-1]
+		switch ent.Reason {
+		case model.ThrottleReasonKey:
+			dto.Key += ent.Count
+		case model.ThrottleReasonInstallation:
+			dto.Installation += ent.Count
+		}
+		dto.Total += ent.Count
+	}
+	return DTOS
+}
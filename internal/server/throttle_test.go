@@ -0,0 +1,63 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestThrottleCounter(t *testing.T) {
+	hour := time.UnixMilli(1700002800000)
+	counter := newThrottleCounter()
+	counter.add(2, model.ThrottleReasonKey, hour.Add(time.Minute))
+	counter.add(1, model.ThrottleReasonInstallation, hour.Add(59*time.Minute))
+	counter.add(1, model.ThrottleReasonInstallation, hour)
+	counter.add(1, model.ThrottleReasonKey, hour.Add(time.Hour))
+
+	expected := []model.ThrottledRequestsData{
+		{AppId: 1, Bucket: hour.UnixMilli(), Reason: model.ThrottleReasonInstallation, Count: 2},
+		{AppId: 1, Bucket: hour.Add(time.Hour).UnixMilli(), Reason: model.ThrottleReasonKey, Count: 1},
+		{AppId: 2, Bucket: hour.UnixMilli(), Reason: model.ThrottleReasonKey, Count: 1},
+	}
+	if got := counter.take(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("take = %+v, expected %+v", got, expected)
+	}
+	if got := counter.take(); len(got) != 0 {
+		t.Errorf("Second take = %+v, expected the counts to be reset", got)
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	tests := []struct {
+		wait     time.Duration
+		expected int
+	}{
+		{wait: 0, expected: 1},
+		{wait: 200 * time.Millisecond, expected: 1},
+		{wait: 1001 * time.Millisecond, expected: 2},
+		{wait: 30 * time.Second, expected: 30},
+	}
+
+	for _, tt := range tests {
+		if got := retryAfterSeconds(tt.wait); got != tt.expected {
+			t.Errorf("retryAfterSeconds(%v) = %d, expected %d", tt.wait, got, tt.expected)
+		}
+	}
+}
+
+func TestThrottledRequestsDTOS(t *testing.T) {
+	entities := []model.ThrottledRequestsEntity{
+		{Bucket: 1000, Reason: model.ThrottleReasonInstallation, Count: 3},
+		{Bucket: 1000, Reason: model.ThrottleReasonKey, Count: 2},
+		{Bucket: 2000, Reason: model.ThrottleReasonKey, Count: 4},
+	}
+
+	expected := []model.ThrottledRequestsDTO{
+		{Start: 1000, Key: 2, Installation: 3, Total: 5},
+		{Start: 2000, Key: 4, Total: 4},
+	}
+	if got := throttledRequestsDTOS(entities); !reflect.DeepEqual(got, expected) {
+		t.Errorf("throttledRequestsDTOS = %+v, expected %+v", got, expected)
+	}
+}
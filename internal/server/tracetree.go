@@ -0,0 +1,226 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"sort"
+)
+
+type traceNode struct {
+	trace    model.TraceEntity
+	end      int64
+	orphan   bool
+	critical bool
+	children []*traceNode
+}
+
+// buildTraceTrees reconstructs the span hierarchy of every group from the
+// flat list of traces. Groups are sorted by start time, as are the children
+// of every trace.
+func buildTraceTrees(traces []model.TraceEntity) []model.TraceGroupDTO {
+	groupIds := make([]string, 0)
+	groups := make(map[string][]model.TraceEntity)
+	for _, trace := range traces {
+		if _, ok := groups[trace.GroupId]; !ok {
+			groupIds = append(groupIds, trace.GroupId)
+		}
+		groups[trace.GroupId] = append(groups[trace.GroupId], trace)
+	}
+
+	trees := make([]model.TraceGroupDTO, len(groupIds))
+	for i, groupId := range groupIds {
+		trees[i] = buildTraceGroup(groupId, groups[groupId])
+	}
+	sort.SliceStable(trees, func(i, j int) bool {
+		return trees[i].StartedAt < trees[j].StartedAt
+	})
+
+	return trees
+}
+
+func buildTraceGroup(groupId string, traces []model.TraceEntity) model.TraceGroupDTO {
+	group := model.TraceGroupDTO{
+		GroupId:      groupId,
+		CriticalPath: make([]string, 0),
+		Orphans:      make([]string, 0),
+		Unended:      make([]string, 0),
+		Roots:        make([]model.TraceNodeDTO, 0),
+	}
+
+	// The group ends with the last timestamp known, which is also where
+	// traces that never ended are cut off
+	group.StartedAt = traces[0].StartedAt
+	group.EndedAt = traces[0].StartedAt
+	for _, trace := range traces {
+		group.StartedAt = min(group.StartedAt, trace.StartedAt)
+		group.EndedAt = max(group.EndedAt, trace.StartedAt)
+		if trace.HasEnded {
+			group.EndedAt = max(group.EndedAt, trace.EndedAt)
+		}
+	}
+	group.Duration = group.EndedAt - group.StartedAt
+
+	nodes := make(map[string]*traceNode, len(traces))
+	for _, trace := range traces {
+		end := group.EndedAt
+		if trace.HasEnded {
+			end = max(trace.EndedAt, trace.StartedAt)
+		} else {
+			group.Unended = append(group.Unended, trace.TraceId)
+		}
+		nodes[trace.TraceId] = &traceNode{trace: trace, end: end}
+	}
+
+	roots := make([]*traceNode, 0)
+	for _, trace := range traces {
+		node := nodes[trace.TraceId]
+		parent, ok := nodes[trace.ParentId]
+		if trace.ParentId == "" {
+			roots = append(roots, node)
+		} else if !ok || hasAncestor(nodes, trace.ParentId, trace.TraceId) {
+			// Traces which are their own ancestor only occur in malformed
+			// data, and are treated as orphans as well to break the cycle
+			node.orphan = true
+			group.Orphans = append(group.Orphans, trace.TraceId)
+			roots = append(roots, node)
+		} else {
+			parent.children = append(parent.children, node)
+		}
+	}
+	sortTraceNodes(roots)
+
+	// Orphans are only part of the critical path if the real root is missing
+	var root *traceNode
+	for _, node := range roots {
+		if node.orphan && root != nil && !root.orphan {
+			continue
+		}
+		if root == nil || (root.orphan && !node.orphan) || node.end > root.end {
+			root = node
+		}
+	}
+	if root != nil {
+		markCriticalPath(root, root.end)
+	}
+
+	for _, node := range roots {
+		group.Roots = append(group.Roots, traceNodeToDTO(node))
+	}
+	group.CriticalPath = criticalPathIds(roots)
+
+	return group
+}
+
+// hasAncestor reports whether ancestorId is reached by following the parents
+// starting at traceId
+func hasAncestor(nodes map[string]*traceNode, traceId, ancestorId string) bool {
+	for i := 0; i <= len(nodes) && traceId != ""; i++ {
+		if traceId == ancestorId {
+			return true
+		}
+		node, ok := nodes[traceId]
+		if !ok {
+			return false
+		}
+		traceId = node.trace.ParentId
+	}
+	return false
+}
+
+func sortTraceNodes(nodes []*traceNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].trace.StartedAt < nodes[j].trace.StartedAt
+	})
+	for _, node := range nodes {
+		sortTraceNodes(node.children)
+	}
+}
+
+// markCriticalPath marks the chain of traces that determined when node
+// finished. Starting at the end, it repeatedly follows the child that
+// finished last before the current point in time, then continues from the
+// start of that child.
+func markCriticalPath(node *traceNode, end int64) {
+	if node.critical {
+		return
+	}
+	node.critical = true
+
+	children := make([]*traceNode, len(node.children))
+	copy(children, node.children)
+	sort.SliceStable(children, func(i, j int) bool {
+		return children[i].end > children[j].end
+	})
+
+	cursor := min(node.end, end)
+	for _, child := range children {
+		if child.trace.StartedAt >= cursor {
+			continue
+		}
+		markCriticalPath(child, cursor)
+		cursor = child.trace.StartedAt
+	}
+}
+
+func criticalPathIds(nodes []*traceNode) []string {
+	ids := make([]string, 0)
+	for _, node := range nodes {
+		if node.critical {
+			ids = append(ids, node.trace.TraceId)
+			ids = append(ids, criticalPathIds(node.children)...)
+		}
+	}
+	return ids
+}
+
+// selfTime is the time of node not covered by any of its children
+func selfTime(node *traceNode) int64 {
+	start, end := node.trace.StartedAt, node.end
+	if len(node.children) == 0 {
+		return end - start
+	}
+
+	covered := int64(0)
+	cursor := start
+	// Children are sorted by start time, so overlapping children are merged
+	for _, child := range node.children {
+		childStart := max(child.trace.StartedAt, cursor)
+		childEnd := min(child.end, end)
+		if childEnd > childStart {
+			covered += childEnd - childStart
+			cursor = childEnd
+		}
+	}
+
+	return max(end-start-covered, 0)
+}
+
+func traceNodeToDTO(node *traceNode) model.TraceNodeDTO {
+	trace := node.trace
+
+	dto := model.TraceNodeDTO{
+		TraceDTO: model.TraceDTO{
+			TraceId:      trace.TraceId,
+			SessionId:    trace.SessionId,
+			GroupId:      trace.GroupId,
+			ParentId:     trace.ParentId,
+			Name:         trace.Name,
+			Status:       trace.Status,
+			ErrorMessage: trace.ErrorMessage,
+			StartedAt:    trace.StartedAt,
+			EndedAt:      trace.EndedAt,
+			HasEnded:     trace.HasEnded,
+			Attributes:   trace.Attributes,
+		},
+		Duration:       node.end - trace.StartedAt,
+		SelfTime:       selfTime(node),
+		OnCriticalPath: node.critical,
+		Orphan:         node.orphan,
+		Children:       make([]model.TraceNodeDTO, 0, len(node.children)),
+	}
+
+	for _, child := range node.children {
+		dto.Children = append(dto.Children, traceNodeToDTO(child))
+	}
+
+	return dto
+}
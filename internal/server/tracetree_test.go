@@ -0,0 +1,75 @@
+package server
+
+import (
+	"ObservabilityServer/internal/model"
+	"reflect"
+	"testing"
+)
+
+func TestBuildTraceTrees(t *testing.T) {
+	traces := []model.TraceEntity{
+		{TraceId: "root", GroupId: "g1", Name: "Root", StartedAt: 0, EndedAt: 100, HasEnded: true},
+		{TraceId: "a", GroupId: "g1", ParentId: "root", Name: "A", StartedAt: 10, EndedAt: 40, HasEnded: true},
+		{TraceId: "b", GroupId: "g1", ParentId: "root", Name: "B", StartedAt: 30, EndedAt: 90, HasEnded: true},
+		{TraceId: "c", GroupId: "g1", ParentId: "b", Name: "C", StartedAt: 35, EndedAt: 50, HasEnded: true},
+		{TraceId: "orphan", GroupId: "g1", ParentId: "missing", Name: "Orphan", StartedAt: 5, EndedAt: 15, HasEnded: true},
+		{TraceId: "unended", GroupId: "g0", Name: "Unended", StartedAt: -10},
+		{TraceId: "child", GroupId: "g0", ParentId: "unended", Name: "Child", StartedAt: -8, EndedAt: -2, HasEnded: true},
+	}
+
+	groups := buildTraceTrees(traces)
+	if len(groups) != 2 {
+		t.Fatalf("Expected 2 groups but was %d", len(groups))
+	}
+
+	unendedGroup := groups[0]
+	if unendedGroup.GroupId != "g0" || unendedGroup.Duration != 8 {
+		t.Errorf("Wrong group order or duration: %s, %d", unendedGroup.GroupId, unendedGroup.Duration)
+	}
+	if !reflect.DeepEqual(unendedGroup.Unended, []string{"unended"}) {
+		t.Errorf("Wrong unended traces. expected = [unended], actual = %v", unendedGroup.Unended)
+	}
+	if unended := unendedGroup.Roots[0]; unended.Duration != 8 || unended.SelfTime != 2 {
+		t.Errorf("Wrong duration of unended trace. duration = %d, self time = %d", unended.Duration, unended.SelfTime)
+	}
+
+	group := groups[1]
+	if group.GroupId != "g1" || group.StartedAt != 0 || group.EndedAt != 100 {
+		t.Errorf("Wrong group: %s %d-%d", group.GroupId, group.StartedAt, group.EndedAt)
+	}
+	if !reflect.DeepEqual(group.Orphans, []string{"orphan"}) {
+		t.Errorf("Wrong orphans. expected = [orphan], actual = %v", group.Orphans)
+	}
+	if !reflect.DeepEqual(group.CriticalPath, []string{"root", "a", "b", "c"}) {
+		t.Errorf("Wrong critical path. expected = [root a b c], actual = %v", group.CriticalPath)
+	}
+	if len(group.Roots) != 2 || group.Roots[0].TraceId != "root" || !group.Roots[1].Orphan || group.Roots[1].OnCriticalPath {
+		t.Fatalf("Wrong roots: %+v", group.Roots)
+	}
+
+	root := group.Roots[0]
+	if root.Duration != 100 || root.SelfTime != 20 {
+		t.Errorf("Wrong root times. duration = %d, self time = %d", root.Duration, root.SelfTime)
+	}
+	if len(root.Children) != 2 || root.Children[0].TraceId != "a" || root.Children[1].TraceId != "b" {
+		t.Fatalf("Wrong children of root: %+v", root.Children)
+	}
+	if b := root.Children[1]; b.Duration != 60 || b.SelfTime != 45 || len(b.Children) != 1 {
+		t.Errorf("Wrong times of trace b. duration = %d, self time = %d", b.Duration, b.SelfTime)
+	}
+}
+
+func TestBuildTraceTreesCycle(t *testing.T) {
+	traces := []model.TraceEntity{
+		{TraceId: "x", GroupId: "g", ParentId: "y", StartedAt: 0, EndedAt: 10, HasEnded: true},
+		{TraceId: "y", GroupId: "g", ParentId: "x", StartedAt: 1, EndedAt: 5, HasEnded: true},
+	}
+
+	groups := buildTraceTrees(traces)
+	if len(groups) != 1 || len(groups[0].Roots) != 2 {
+		t.Fatalf("Expected every trace of a cycle as root, but was %+v", groups)
+	}
+	if !reflect.DeepEqual(groups[0].Orphans, []string{"x", "y"}) {
+		t.Errorf("Wrong orphans. expected = [x y], actual = %v", groups[0].Orphans)
+	}
+}
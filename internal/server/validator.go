@@ -1,17 +1,56 @@
 package server
 
-import "github.com/go-playground/validator/v10"
+import (
+	"reflect"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// Max number of attributes of an event or trace
+	maxAttributes = 64
+	// Max length of string attribute values of events and traces
+	maxAttributeValueLength = 1024
+)
 
 type Validator struct {
 	v *validator.Validate
 }
 
 func NewValidator() *Validator {
+	v := validator.New()
+	v.RegisterValidation("attributekey", validateAttributeKey)
+	v.RegisterValidation("attributevalue", validateAttributeValue)
+	v.RegisterValidation("apikeyscope", validateApiKeyScope)
+
 	return &Validator{
-		v: validator.New(),
+		v: v,
 	}
 }
 
 func (v *Validator) Validate(i interface{}) error {
 	return v.v.Struct(i)
 }
+
+func validateAttributeKey(fl validator.FieldLevel) bool {
+	return attributeKeyPattern.MatchString(fl.Field().String())
+}
+
+// validateAttributeValue only accepts scalar values, which can be searched for
+func validateAttributeValue(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	switch field.Kind() {
+	case reflect.String:
+		return len(field.String()) <= maxAttributeValueLength
+	case reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+func validateApiKeyScope(fl validator.FieldLevel) bool {
+	return validScope(fl.Field().String())
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS public.ob_ingestion_batches;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS public.ob_ingestion_batches (
+	id TEXT PRIMARY KEY,
+	app_id INTEGER NOT NULL REFERENCES public.ob_applications(id) ON DELETE CASCADE,
+	status TEXT NOT NULL DEFAULT 'pending',
+	attempts INTEGER NOT NULL DEFAULT 0,
+	failures JSONB NOT NULL DEFAULT '[]',
+	created_at BIGINT NOT NULL,
+	updated_at BIGINT NOT NULL
+);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS public.ob_events_session_page_idx;
+DROP INDEX IF EXISTS public.ob_trace_session_page_idx;
+DROP INDEX IF EXISTS public.ob_memory_usage_session_page_idx;
+DROP INDEX IF EXISTS public.ob_memory_usage_installation_page_idx;
+DROP INDEX IF EXISTS public.ob_installations_app_page_idx;
+DROP INDEX IF EXISTS public.ob_sessions_app_page_idx;
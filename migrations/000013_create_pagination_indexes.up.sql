@@ -0,0 +1,6 @@
+CREATE INDEX IF NOT EXISTS ob_events_session_page_idx ON public.ob_events (session_id, created_at, id);
+CREATE INDEX IF NOT EXISTS ob_trace_session_page_idx ON public.ob_trace (session_id, started_at, trace_id);
+CREATE INDEX IF NOT EXISTS ob_memory_usage_session_page_idx ON public.ob_memory_usage (session_id, created_at, id);
+CREATE INDEX IF NOT EXISTS ob_memory_usage_installation_page_idx ON public.ob_memory_usage (installation_id, created_at, id);
+CREATE INDEX IF NOT EXISTS ob_installations_app_page_idx ON public.ob_installations (app_id, created_at, id);
+CREATE INDEX IF NOT EXISTS ob_sessions_app_page_idx ON public.ob_sessions (app_id, created_at, id);
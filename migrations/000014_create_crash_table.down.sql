@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.ob_crashes;
+DROP TABLE IF EXISTS public.ob_issues;
+
+COMMIT;
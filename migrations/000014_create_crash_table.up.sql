@@ -0,0 +1,32 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS public.ob_issues (
+	fingerprint TEXT NOT NULL,
+	app_id INTEGER NOT NULL REFERENCES public.ob_applications(id) ON DELETE CASCADE,
+	type TEXT NOT NULL,
+	exception_class TEXT NOT NULL,
+	message TEXT NOT NULL DEFAULT '',
+	first_seen BIGINT NOT NULL,
+	last_seen BIGINT NOT NULL,
+	PRIMARY KEY (app_id, fingerprint)
+);
+
+CREATE TABLE IF NOT EXISTS public.ob_crashes (
+	id TEXT PRIMARY KEY,
+	session_id TEXT NOT NULL REFERENCES public.ob_sessions(id) ON DELETE NO ACTION,
+	app_id INTEGER NOT NULL,
+	fingerprint TEXT NOT NULL,
+	exception_class TEXT NOT NULL,
+	message TEXT NOT NULL DEFAULT '',
+	frames JSONB NOT NULL DEFAULT '[]',
+	thread_name TEXT NOT NULL DEFAULT '',
+	screen TEXT NOT NULL DEFAULT '',
+	created_at BIGINT NOT NULL,
+	FOREIGN KEY (app_id, fingerprint) REFERENCES public.ob_issues (app_id, fingerprint)
+		ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+CREATE INDEX IF NOT EXISTS ob_crashes_issue_idx ON public.ob_crashes (app_id, fingerprint, created_at, id);
+CREATE INDEX IF NOT EXISTS ob_crashes_session_idx ON public.ob_crashes (session_id);
+
+COMMIT;
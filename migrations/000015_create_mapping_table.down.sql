@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE public.ob_crashes DROP COLUMN IF EXISTS deobfuscated;
+ALTER TABLE public.ob_crashes DROP COLUMN IF EXISTS app_version;
+
+DROP TABLE IF EXISTS public.ob_mappings;
+
+COMMIT;
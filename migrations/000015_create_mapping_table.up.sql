@@ -0,0 +1,14 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS public.ob_mappings (
+	app_id INTEGER NOT NULL REFERENCES public.ob_applications(id) ON DELETE CASCADE,
+	version TEXT NOT NULL,
+	mapping BYTEA NOT NULL,
+	created_at BIGINT NOT NULL,
+	PRIMARY KEY (app_id, version)
+);
+
+ALTER TABLE public.ob_crashes ADD COLUMN IF NOT EXISTS app_version TEXT NOT NULL DEFAULT '';
+ALTER TABLE public.ob_crashes ADD COLUMN IF NOT EXISTS deobfuscated BOOLEAN NOT NULL DEFAULT false;
+
+COMMIT;
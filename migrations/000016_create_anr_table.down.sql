@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.ob_anrs;
+
+DROP INDEX IF EXISTS public.ob_sessions_app_version_idx;
+ALTER TABLE public.ob_sessions DROP COLUMN IF EXISTS app_version;
+
+COMMIT;
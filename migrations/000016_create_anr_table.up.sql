@@ -0,0 +1,28 @@
+BEGIN;
+
+ALTER TABLE public.ob_sessions ADD COLUMN IF NOT EXISTS app_version TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS ob_sessions_app_version_idx ON public.ob_sessions (app_id, app_version, created_at);
+
+CREATE TABLE IF NOT EXISTS public.ob_anrs (
+	id TEXT PRIMARY KEY,
+	session_id TEXT NOT NULL REFERENCES public.ob_sessions(id) ON DELETE NO ACTION,
+	installation_id TEXT NOT NULL,
+	app_id INTEGER NOT NULL,
+	fingerprint TEXT NOT NULL,
+	duration BIGINT NOT NULL DEFAULT 0,
+	main_thread JSONB NOT NULL DEFAULT '[]',
+	threads JSONB NOT NULL DEFAULT '[]',
+	screen TEXT NOT NULL DEFAULT '',
+	app_version TEXT NOT NULL DEFAULT '',
+	deobfuscated BOOLEAN NOT NULL DEFAULT false,
+	created_at BIGINT NOT NULL,
+	FOREIGN KEY (app_id, fingerprint) REFERENCES public.ob_issues (app_id, fingerprint)
+		ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+CREATE INDEX IF NOT EXISTS ob_anrs_issue_idx ON public.ob_anrs (app_id, fingerprint, created_at, id);
+CREATE INDEX IF NOT EXISTS ob_anrs_session_idx ON public.ob_anrs (session_id);
+CREATE INDEX IF NOT EXISTS ob_anrs_app_version_idx ON public.ob_anrs (app_id, app_version, created_at);
+
+COMMIT;
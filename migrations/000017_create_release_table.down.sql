@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP INDEX IF EXISTS public.ob_crashes_app_version_idx;
+
+DROP TABLE IF EXISTS public.ob_releases;
+
+ALTER TABLE public.ob_sessions DROP COLUMN IF EXISTS build_type;
+ALTER TABLE public.ob_sessions DROP COLUMN IF EXISTS version_code;
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+ALTER TABLE public.ob_sessions ADD COLUMN IF NOT EXISTS version_code BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE public.ob_sessions ADD COLUMN IF NOT EXISTS build_type TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS public.ob_releases (
+	app_id INTEGER NOT NULL REFERENCES public.ob_applications(id) ON DELETE CASCADE,
+	version TEXT NOT NULL,
+	version_code BIGINT NOT NULL DEFAULT 0,
+	first_seen BIGINT NOT NULL,
+	last_seen BIGINT NOT NULL,
+	PRIMARY KEY (app_id, version)
+);
+
+INSERT INTO public.ob_releases (app_id, version, first_seen, last_seen)
+	SELECT app_id, app_version, MIN(created_at), MAX(created_at)
+	FROM public.ob_sessions
+	WHERE app_version <> ''
+	GROUP BY app_id, app_version
+ON CONFLICT DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS ob_crashes_app_version_idx ON public.ob_crashes (app_id, app_version, created_at);
+
+COMMIT;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS public.ob_memory_usage_app_idx;
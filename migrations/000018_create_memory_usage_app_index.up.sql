@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS ob_memory_usage_app_idx ON public.ob_memory_usage (app_id, created_at);
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.ob_network_usage;
+DROP TABLE IF EXISTS public.ob_battery_state;
+DROP TABLE IF EXISTS public.ob_frame_metrics;
+DROP TABLE IF EXISTS public.ob_cpu_usage;
+
+COMMIT;
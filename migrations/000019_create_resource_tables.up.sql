@@ -0,0 +1,63 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS public.ob_cpu_usage (
+	id TEXT PRIMARY KEY,
+	session_id TEXT NOT NULL REFERENCES public.ob_sessions(id) ON DELETE CASCADE,
+	installation_id TEXT NOT NULL,
+	app_id INTEGER NOT NULL REFERENCES public.ob_applications(id) ON DELETE CASCADE,
+	usage DOUBLE PRECISION NOT NULL,
+	user_time BIGINT NOT NULL,
+	system_time BIGINT NOT NULL,
+	cores INTEGER NOT NULL,
+	created_at BIGINT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS public.ob_frame_metrics (
+	id TEXT PRIMARY KEY,
+	session_id TEXT NOT NULL REFERENCES public.ob_sessions(id) ON DELETE CASCADE,
+	installation_id TEXT NOT NULL,
+	app_id INTEGER NOT NULL REFERENCES public.ob_applications(id) ON DELETE CASCADE,
+	screen TEXT NOT NULL,
+	total_frames INTEGER NOT NULL,
+	janky_frames INTEGER NOT NULL,
+	frozen_frames INTEGER NOT NULL,
+	created_at BIGINT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS public.ob_battery_state (
+	id TEXT PRIMARY KEY,
+	session_id TEXT NOT NULL REFERENCES public.ob_sessions(id) ON DELETE CASCADE,
+	installation_id TEXT NOT NULL,
+	app_id INTEGER NOT NULL REFERENCES public.ob_applications(id) ON DELETE CASCADE,
+	level DOUBLE PRECISION NOT NULL,
+	charging BOOLEAN NOT NULL,
+	temperature DOUBLE PRECISION NOT NULL,
+	thermal_state TEXT NOT NULL,
+	power_save BOOLEAN NOT NULL,
+	created_at BIGINT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS public.ob_network_usage (
+	id TEXT PRIMARY KEY,
+	session_id TEXT NOT NULL REFERENCES public.ob_sessions(id) ON DELETE CASCADE,
+	installation_id TEXT NOT NULL,
+	app_id INTEGER NOT NULL REFERENCES public.ob_applications(id) ON DELETE CASCADE,
+	connection_type TEXT NOT NULL,
+	requests INTEGER NOT NULL,
+	failed_requests INTEGER NOT NULL,
+	bytes_sent BIGINT NOT NULL,
+	bytes_received BIGINT NOT NULL,
+	created_at BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS ob_cpu_usage_session_page_idx ON public.ob_cpu_usage (session_id, created_at, id);
+CREATE INDEX IF NOT EXISTS ob_cpu_usage_installation_page_idx ON public.ob_cpu_usage (installation_id, created_at, id);
+CREATE INDEX IF NOT EXISTS ob_frame_metrics_session_page_idx ON public.ob_frame_metrics (session_id, created_at, id);
+CREATE INDEX IF NOT EXISTS ob_frame_metrics_installation_page_idx ON public.ob_frame_metrics (installation_id, created_at, id);
+CREATE INDEX IF NOT EXISTS ob_frame_metrics_app_idx ON public.ob_frame_metrics (app_id, created_at);
+CREATE INDEX IF NOT EXISTS ob_battery_state_session_page_idx ON public.ob_battery_state (session_id, created_at, id);
+CREATE INDEX IF NOT EXISTS ob_battery_state_installation_page_idx ON public.ob_battery_state (installation_id, created_at, id);
+CREATE INDEX IF NOT EXISTS ob_network_usage_session_page_idx ON public.ob_network_usage (session_id, created_at, id);
+CREATE INDEX IF NOT EXISTS ob_network_usage_installation_page_idx ON public.ob_network_usage (installation_id, created_at, id);
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS public.ob_network_requests (
+	id TEXT PRIMARY KEY,
+	session_id TEXT NOT NULL REFERENCES public.ob_sessions(id) ON DELETE CASCADE,
+	trace_id TEXT NOT NULL DEFAULT '',
+	app_id INTEGER NOT NULL REFERENCES public.ob_applications(id) ON DELETE CASCADE,
+	host TEXT NOT NULL,
+	url_template TEXT NOT NULL,
+	method TEXT NOT NULL,
+	status_code INTEGER NOT NULL,
+	duration BIGINT NOT NULL,
+	request_size BIGINT NOT NULL,
+	response_size BIGINT NOT NULL,
+	failure_reason TEXT NOT NULL DEFAULT '',
+	started_at BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS ob_network_requests_session_page_idx ON public.ob_network_requests (session_id, started_at, id);
+CREATE INDEX IF NOT EXISTS ob_network_requests_trace_idx ON public.ob_network_requests (trace_id) WHERE trace_id <> '';
+CREATE INDEX IF NOT EXISTS ob_network_requests_endpoint_idx ON public.ob_network_requests (app_id, method, url_template, started_at);
+CREATE INDEX IF NOT EXISTS ob_network_requests_app_idx ON public.ob_network_requests (app_id, started_at);
+
+COMMIT;
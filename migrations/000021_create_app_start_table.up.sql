@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS public.ob_app_starts (
+	id TEXT PRIMARY KEY,
+	session_id TEXT NOT NULL REFERENCES public.ob_sessions(id) ON DELETE CASCADE,
+	installation_id TEXT NOT NULL,
+	app_id INTEGER NOT NULL REFERENCES public.ob_applications(id) ON DELETE CASCADE,
+	start_type TEXT NOT NULL,
+	duration BIGINT NOT NULL,
+	process_duration BIGINT NOT NULL DEFAULT 0,
+	created_at BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS ob_app_starts_session_page_idx ON public.ob_app_starts (session_id, created_at, id);
+CREATE INDEX IF NOT EXISTS ob_app_starts_installation_page_idx ON public.ob_app_starts (installation_id, created_at, id);
+CREATE INDEX IF NOT EXISTS ob_app_starts_app_idx ON public.ob_app_starts (app_id, start_type, created_at);
+
+COMMIT;
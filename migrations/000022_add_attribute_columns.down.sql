@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP INDEX IF EXISTS public.ob_events_app_type_idx;
+DROP INDEX IF EXISTS public.ob_trace_attributes_idx;
+DROP INDEX IF EXISTS public.ob_events_attributes_idx;
+
+ALTER TABLE public.ob_trace DROP COLUMN IF EXISTS attributes;
+ALTER TABLE public.ob_events DROP COLUMN IF EXISTS attributes;
+
+COMMIT;
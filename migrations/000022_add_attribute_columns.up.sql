@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE public.ob_events ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE public.ob_trace ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS ob_events_attributes_idx ON public.ob_events USING GIN (attributes jsonb_path_ops);
+CREATE INDEX IF NOT EXISTS ob_trace_attributes_idx ON public.ob_trace USING GIN (attributes jsonb_path_ops);
+CREATE INDEX IF NOT EXISTS ob_events_app_type_idx ON public.ob_events (app_id, type, created_at, id);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE public.ob_team_users DROP CONSTRAINT IF EXISTS ob_team_users_role_check;
+
+COMMIT;
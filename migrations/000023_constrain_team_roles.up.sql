@@ -0,0 +1,11 @@
+BEGIN;
+
+-- Links created before roles were validated can hold any role
+UPDATE public.ob_team_users
+	SET role = 'developer'
+	WHERE role NOT IN ('owner', 'admin', 'developer', 'viewer');
+
+ALTER TABLE public.ob_team_users
+	ADD CONSTRAINT ob_team_users_role_check CHECK (role IN ('owner', 'admin', 'developer', 'viewer'));
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+DROP INDEX IF EXISTS public.ob_api_keys_app_idx;
+
+ALTER TABLE public.ob_api_keys
+	DROP COLUMN IF EXISTS revoked_at,
+	DROP COLUMN IF EXISTS expires_at,
+	DROP COLUMN IF EXISTS last_used_at,
+	DROP COLUMN IF EXISTS created_by,
+	DROP COLUMN IF EXISTS created_at,
+	DROP COLUMN IF EXISTS name,
+	DROP COLUMN IF EXISTS id;
+
+COMMIT;
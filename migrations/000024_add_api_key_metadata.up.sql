@@ -0,0 +1,15 @@
+BEGIN;
+
+-- Keys created before this migration have no creation time or creator
+ALTER TABLE public.ob_api_keys
+	ADD COLUMN IF NOT EXISTS id SERIAL UNIQUE,
+	ADD COLUMN IF NOT EXISTS name TEXT NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS created_at BIGINT NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS created_by INTEGER REFERENCES public.ob_users (id) ON DELETE SET NULL,
+	ADD COLUMN IF NOT EXISTS last_used_at BIGINT,
+	ADD COLUMN IF NOT EXISTS expires_at BIGINT,
+	ADD COLUMN IF NOT EXISTS revoked_at BIGINT;
+
+CREATE INDEX IF NOT EXISTS ob_api_keys_app_idx ON public.ob_api_keys (app_id, created_at);
+
+COMMIT;
@@ -0,0 +1 @@
+ALTER TABLE public.ob_api_keys DROP COLUMN IF EXISTS scopes;
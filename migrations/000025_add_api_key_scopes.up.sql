@@ -0,0 +1,3 @@
+-- Keys created before scopes could ingest any data
+ALTER TABLE public.ob_api_keys
+	ADD COLUMN IF NOT EXISTS scopes JSONB NOT NULL DEFAULT '["ingest"]';
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.ob_throttled_requests;
+DROP TABLE IF EXISTS public.ob_rate_limits;
+
+COMMIT;
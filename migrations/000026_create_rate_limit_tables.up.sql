@@ -0,0 +1,23 @@
+BEGIN;
+
+-- Token buckets shared by all replicas. Buckets which are full again at
+-- full_at are removed, since a missing bucket is full.
+CREATE TABLE IF NOT EXISTS public.ob_rate_limits (
+	key TEXT PRIMARY KEY,
+	tokens DOUBLE PRECISION NOT NULL,
+	updated_at BIGINT NOT NULL,
+	full_at BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS ob_rate_limits_full_idx ON public.ob_rate_limits (full_at);
+
+-- Requests throttled per app, counted in hourly buckets
+CREATE TABLE IF NOT EXISTS public.ob_throttled_requests (
+	app_id INTEGER NOT NULL REFERENCES public.ob_applications(id) ON DELETE CASCADE,
+	bucket BIGINT NOT NULL,
+	reason TEXT NOT NULL,
+	count BIGINT NOT NULL,
+	PRIMARY KEY (app_id, bucket, reason)
+);
+
+COMMIT;
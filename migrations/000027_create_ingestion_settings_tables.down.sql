@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.ob_ingestion_usage;
+DROP TABLE IF EXISTS public.ob_ingestion_settings;
+
+COMMIT;
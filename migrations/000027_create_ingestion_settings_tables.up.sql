@@ -0,0 +1,26 @@
+BEGIN;
+
+-- Apps without settings have no quotas and keep every session
+CREATE TABLE IF NOT EXISTS public.ob_ingestion_settings (
+	app_id INTEGER PRIMARY KEY REFERENCES public.ob_applications(id) ON DELETE CASCADE,
+	monthly_event_quota BIGINT NOT NULL DEFAULT 0,
+	monthly_trace_quota BIGINT NOT NULL DEFAULT 0,
+	sampling_rules JSONB NOT NULL DEFAULT '[]',
+	updated_by INTEGER REFERENCES public.ob_users (id) ON DELETE SET NULL,
+	updated_at BIGINT NOT NULL
+);
+
+-- Events and traces of an app per calendar month in UTC
+CREATE TABLE IF NOT EXISTS public.ob_ingestion_usage (
+	app_id INTEGER NOT NULL REFERENCES public.ob_applications(id) ON DELETE CASCADE,
+	month BIGINT NOT NULL,
+	events BIGINT NOT NULL DEFAULT 0,
+	traces BIGINT NOT NULL DEFAULT 0,
+	sampled_events BIGINT NOT NULL DEFAULT 0,
+	sampled_traces BIGINT NOT NULL DEFAULT 0,
+	over_quota_events BIGINT NOT NULL DEFAULT 0,
+	over_quota_traces BIGINT NOT NULL DEFAULT 0,
+	PRIMARY KEY (app_id, month)
+);
+
+COMMIT;